@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -12,6 +13,7 @@ import (
 	"gorelay/pkg/account"
 	"gorelay/pkg/client"
 	"gorelay/pkg/config"
+	"gorelay/pkg/events"
 	"gorelay/pkg/logger"
 	"gorelay/pkg/models"
 	"gorelay/pkg/plugin"
@@ -101,6 +103,24 @@ func main() {
 	}
 	models.SetAccountAliases(aliases)
 
+	// Load the vault mirror shared by all clients
+	vaults, err := models.NewVaultStorage("vaults")
+	if err != nil {
+		logger.Error("Main", "Failed to load vaults: %v", err)
+	} else {
+		models.SetVaultStorage(vaults)
+	}
+
+	// The accounts file is shared by every client, saves must not interleave
+	var accountsMu sync.Mutex
+	saveAccounts := func() {
+		accountsMu.Lock()
+		defer accountsMu.Unlock()
+		if err := accManager.Save(*accountsPath); err != nil {
+			logger.Error("Main", "Failed to save accounts: %v", err)
+		}
+	}
+
 	// Create wait group for managing client goroutines
 	var wg sync.WaitGroup
 	var clients []*client.Client
@@ -113,6 +133,7 @@ func main() {
 			defer wg.Done()
 
 			// Create client
+			savedServer := acc.LastServer
 			client := client.NewClient(acc, cfg, logger)
 			if client == nil {
 				logger.Error("Main", "Failed to create client for account %s, skipping", acc.Alias)
@@ -132,6 +153,47 @@ func main() {
 				}
 			}
 
+			// Report quest and mission progress to the monitor
+			monitor.AddClient(acc.Alias, &models.Account{Alias: acc.Alias, ServerPref: acc.ServerPref})
+			events.On(client.Events(), events.EventQuestsFetched, func(_ *events.Event, data *events.QuestEventData) {
+				monitor.UpdateClientStatus(acc.Alias, map[string]interface{}{"quests": data.Quests})
+			})
+			events.On(client.Events(), events.EventMissionProgress, func(_ *events.Event, data *events.MissionEventData) {
+				monitor.UpdateClientStatus(acc.Alias, map[string]interface{}{"missions": client.Quests().Missions()})
+			})
+
+			events.On(client.Events(), events.EventQueueUpdate, func(_ *events.Event, data *events.QueueEventData) {
+				monitor.UpdateClientStatus(acc.Alias, map[string]interface{}{
+					"queue":    fmt.Sprintf("%d/%d on %s", data.Position, data.MaxPosition, data.Server),
+					"queueETA": data.ETA.Round(time.Second).String(),
+				})
+			})
+			events.On(client.Events(), events.EventQueueLeft, func(_ *events.Event, data *events.QueueEventData) {
+				monitor.UpdateClientStatus(acc.Alias, map[string]interface{}{"queue": "", "queueETA": ""})
+			})
+
+			// Persist the server each connection lands on, sticky server
+			// selection starts from it after a restart
+			events.On(client.Events(), events.EventConnect, func(_ *events.Event, data *events.ConnectionEventData) {
+				if data.Server == "" || data.Server == savedServer {
+					return
+				}
+				savedServer = data.Server
+				saveAccounts()
+			})
+
+			// Report latency on every server ping
+			client.Events().Subscribe(events.EventPing, func(_ *events.Event) {
+				stats := client.Clock().Stats()
+				monitor.UpdateClientStatus(acc.Alias, map[string]interface{}{
+					"rtt":         stats.AvgRTT.Milliseconds(),
+					"rttMin":      stats.MinRTT.Milliseconds(),
+					"rttMax":      stats.MaxRTT.Milliseconds(),
+					"jitter":      stats.Jitter.Milliseconds(),
+					"clockOffset": stats.Offset.Milliseconds(),
+				})
+			})
+
 			// Add client to slice with proper synchronization
 			clientMutex.Lock()
 			clients = append(clients, client)
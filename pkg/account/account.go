@@ -69,6 +69,22 @@ type Account struct {
 	TeleportWait          int                `json:"teleportWait"`
 	TOSPopup              bool               `json:"tosPopup"`
 	Timestamp             string             `json:"timestamp"`
+
+	// HTTP client used for web API calls, routed through the account's proxy
+	httpClient *http.Client
+}
+
+// SetHTTPClient sets the HTTP client used for this account's web API calls
+func (a *Account) SetHTTPClient(client *http.Client) {
+	a.httpClient = client
+}
+
+// HTTPClient returns the HTTP client used for this account's web API calls
+func (a *Account) HTTPClient() *http.Client {
+	if a.httpClient == nil {
+		return http.DefaultClient
+	}
+	return a.httpClient
 }
 
 // SecurityQuestions represents security question settings
@@ -91,8 +107,11 @@ type Char struct {
 
 // Proxy represents proxy configuration
 type Proxy struct {
-	Host string `json:"host"`
-	Port int    `json:"port"`
+	Type     string `json:"type,omitempty"` // "http" (default) or "socks5"
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
 }
 
 // TokenExpired checks if the access token has expired
@@ -180,8 +199,10 @@ type AccountVerifyResponse struct {
 
 // CharListResponse represents the XML response from char/list
 type CharListResponse struct {
-	XMLName xml.Name `xml:"Chars"`
-	Chars   []struct {
+	XMLName     xml.Name `xml:"Chars"`
+	NextCharId  int      `xml:"nextCharId,attr"`
+	MaxNumChars int      `xml:"maxNumChars,attr"`
+	Chars       []struct {
 		ID int32 `xml:"id,attr"`
 	} `xml:"Char"`
 	Servers struct {
@@ -314,7 +335,7 @@ func (a *Account) verify(hwidToken string) error {
 	}
 
 	// Make the request
-	resp, err := http.Get(verifyURL)
+	resp, err := a.HTTPClient().Get(verifyURL)
 	if err != nil {
 		return fmt.Errorf("failed to verify account: %v", err)
 	}
@@ -360,8 +381,33 @@ func (a *Account) VerifyAccount(hwidToken string) error {
 
 // GetCharList retrieves the character list and updates account information
 func (a *Account) GetCharList() error {
+	chars, err := a.FetchChars()
+	if err != nil {
+		return err
+	}
+
+	// Initialize CharInfo if nil
+	if a.CharInfo == nil {
+		a.CharInfo = &CharInfo{}
+	}
+
+	// Update character ID if characters exist
+	if len(chars.Characters) > 0 {
+		a.CharInfo.CharID = int32(chars.Characters[0].ID)
+	}
+
+	// Keep the full character list so callers can pick a specific character
+	a.Chars = chars
+	a.CharInfo.NextCharID = int32(chars.NextCharId)
+	a.CharInfo.MaxNumChars = int32(chars.MaxNumChars)
+
+	return nil
+}
+
+// FetchChars requests the character list without changing the account
+func (a *Account) FetchChars() (*Chars, error) {
 	if a.AccessToken == "" {
-		return fmt.Errorf("no access token available")
+		return nil, fmt.Errorf("no access token available")
 	}
 
 	// Build the char list URL
@@ -369,42 +415,40 @@ func (a *Account) GetCharList() error {
 		url.QueryEscape(a.AccessToken))
 
 	// Make the request
-	resp, err := http.Get(charListURL)
+	resp, err := a.HTTPClient().Get(charListURL)
 	if err != nil {
-		return fmt.Errorf("failed to get char list: %v", err)
+		return nil, fmt.Errorf("failed to get char list: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %v", err)
+		return nil, fmt.Errorf("failed to read response: %v", err)
 	}
 
 	xmlData := string(body)
 
 	// Check for various error conditions
 	if strings.Contains(xmlData, "<Error>Try again later</Error>") {
-		return fmt.Errorf("rate limited, please wait 1 minute")
+		return nil, fmt.Errorf("rate limited, please wait 1 minute")
 	}
 	if strings.Contains(xmlData, "<Error>Account in use</Error>") {
-		return fmt.Errorf("account currently in use")
+		return nil, fmt.Errorf("account currently in use")
 	}
 
 	// Parse the XML response
 	var charListResp CharListResponse
 	if err := xml.Unmarshal(body, &charListResp); err != nil {
-		return fmt.Errorf("failed to parse char list response: %v", err)
+		return nil, fmt.Errorf("failed to parse char list response: %v", err)
 	}
 
-	// Initialize CharInfo if nil
-	if a.CharInfo == nil {
-		a.CharInfo = &CharInfo{}
+	chars := &Chars{
+		NextCharId:  charListResp.NextCharId,
+		MaxNumChars: charListResp.MaxNumChars,
+		Characters:  make([]Char, 0, len(charListResp.Chars)),
 	}
-
-	// Update character ID if characters exist
-	if len(charListResp.Chars) > 0 {
-		a.CharInfo.CharID = charListResp.Chars[0].ID
+	for _, c := range charListResp.Chars {
+		chars.Characters = append(chars.Characters, Char{ID: int(c.ID)})
 	}
-
-	return nil
+	return chars, nil
 }
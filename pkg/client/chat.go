@@ -0,0 +1,382 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/server"
+)
+
+// Chat defaults, used when the config leaves them empty
+const (
+	DefaultCommandPrefix = "!"
+	DefaultSendInterval  = time.Second
+	chatQueueSize        = 32
+)
+
+// Chat errors
+var (
+	ErrChatQueueFull = errors.New("chat send queue is full")
+	ErrCommandExists = errors.New("command is already registered")
+)
+
+// CommandContext describes a command received in chat
+type CommandContext struct {
+	Sender  string
+	Kind    events.ChatKind
+	Command string
+	Args    []string
+	Raw     string // text after the command name
+
+	chat *Chat
+}
+
+// Reply answers a command on the channel it was received on
+func (ctx *CommandContext) Reply(text string) error {
+	switch ctx.Kind {
+	case events.ChatPrivate, events.ChatOwn:
+		return ctx.chat.Tell(ctx.Sender, text)
+	case events.ChatGuild:
+		return ctx.chat.GuildChat(text)
+	case events.ChatParty:
+		return ctx.chat.PartyChat(text)
+	default:
+		return ctx.chat.Say(text)
+	}
+}
+
+// CommandHandler handles a chat command. Handlers run on their own goroutine
+// and may block.
+type CommandHandler func(ctx *CommandContext) error
+
+// CommandOption configures a registered command
+type CommandOption func(*command)
+
+// WithAllowedSenders restricts a command to the named players
+func WithAllowedSenders(names ...string) CommandOption {
+	return func(cmd *command) {
+		for _, name := range names {
+			cmd.allowed[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithMinArgs rejects invocations with fewer arguments, replying with the usage
+func WithMinArgs(n int, usage string) CommandOption {
+	return func(cmd *command) {
+		cmd.minArgs = n
+		cmd.usage = usage
+	}
+}
+
+// command is a registered chat command
+type command struct {
+	handler CommandHandler
+	allowed map[string]bool
+	minArgs int
+	usage   string
+}
+
+// Chat parses chat messages, sends rate-limited messages and routes commands
+// to registered handlers
+type Chat struct {
+	// Interval is the minimum time between two sent messages
+	Interval time.Duration
+	// Prefix starts every command, "!" by default
+	Prefix string
+
+	client *Client
+
+	mu       sync.RWMutex
+	commands map[string]*command
+	allowed  map[string]bool // senders allowed to use any command
+
+	startOnce sync.Once
+	queue     chan string
+}
+
+// newChat creates the chat subsystem for a client
+func newChat(c *Client) *Chat {
+	chat := &Chat{
+		Interval: DefaultSendInterval,
+		Prefix:   DefaultCommandPrefix,
+		client:   c,
+		commands: make(map[string]*command),
+		allowed:  make(map[string]bool),
+		queue:    make(chan string, chatQueueSize),
+	}
+	if c.config.Chat.SendInterval > 0 {
+		chat.Interval = time.Duration(c.config.Chat.SendInterval) * time.Millisecond
+	}
+	if c.config.Chat.CommandPrefix != "" {
+		chat.Prefix = c.config.Chat.CommandPrefix
+	}
+	chat.AllowSender(c.config.Chat.AllowedSenders...)
+	return chat
+}
+
+// Chat returns the client's chat subsystem
+func (c *Client) Chat() *Chat {
+	return c.chat
+}
+
+// Say sends a public chat message
+func (ch *Chat) Say(text string) error {
+	return ch.enqueue(text)
+}
+
+// Tell sends a private message to a player
+func (ch *Chat) Tell(name, text string) error {
+	return ch.enqueue(fmt.Sprintf("/tell %s %s", name, text))
+}
+
+// GuildChat sends a message to the guild
+func (ch *Chat) GuildChat(text string) error {
+	return ch.enqueue("/g " + text)
+}
+
+// PartyChat sends a message to the party
+func (ch *Chat) PartyChat(text string) error {
+	return ch.enqueue("/p " + text)
+}
+
+// AllowSender lets the named players use every command. While no sender is
+// allowed, either here or on a command, only the player's tells to itself and
+// guild chat may use the command.
+func (ch *Chat) AllowSender(names ...string) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	for _, name := range names {
+		ch.allowed[strings.ToLower(name)] = true
+	}
+}
+
+// RegisterCommand registers a handler for a command, given without the prefix
+func (ch *Chat) RegisterCommand(name string, handler CommandHandler, opts ...CommandOption) error {
+	cmd := &command{handler: handler, allowed: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(cmd)
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	name = strings.ToLower(name)
+	if _, exists := ch.commands[name]; exists {
+		return fmt.Errorf("%w: %s", ErrCommandExists, name)
+	}
+	ch.commands[name] = cmd
+	return nil
+}
+
+// UnregisterCommand removes a command handler
+func (ch *Chat) UnregisterCommand(name string) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	delete(ch.commands, strings.ToLower(name))
+}
+
+// enqueue adds a message to the send queue, starting the sender on first use
+func (ch *Chat) enqueue(text string) error {
+	ch.startOnce.Do(func() {
+		go ch.sendLoop()
+	})
+	select {
+	case ch.queue <- text:
+		return nil
+	default:
+		return ErrChatQueueFull
+	}
+}
+
+// sendLoop sends queued messages no faster than Interval
+func (ch *Chat) sendLoop() {
+	var last time.Time
+	for text := range ch.queue {
+		if wait := ch.Interval - time.Since(last); wait > 0 {
+			time.Sleep(wait)
+		}
+		for !ch.client.IsConnected() {
+			time.Sleep(ch.Interval)
+		}
+
+		packet := client.NewPlayerText()
+		packet.Text = text
+		if err := ch.client.Send(packet); err != nil {
+			ch.client.logger.Error("Chat", "Failed to send message: %v", err)
+		}
+		last = time.Now()
+	}
+}
+
+// handleText classifies a Text packet, logs it, emits it and runs commands
+func (ch *Chat) handleText(text *server.Text) {
+	chat := ch.parse(text)
+	ch.log(chat)
+	ch.client.emit(events.EventPlayerChat, text, chat)
+	if chat.Kind == events.ChatGuild {
+		ch.client.emit(events.EventGuildChat, text, chat)
+	}
+	ch.dispatch(chat)
+}
+
+// parse turns a Text packet into a typed chat message
+func (ch *Chat) parse(text *server.Text) *events.ChatEventData {
+	chat := &events.ChatEventData{
+		Name:      text.Name,
+		Recipient: text.Recipient,
+		Text:      text.RawText,
+		ObjectID:  text.ObjectId,
+		NumStars:  int32(text.NumStars),
+	}
+
+	ownName := ""
+	if data := ch.client.state.PlayerData; data != nil {
+		ownName = data.Name
+	}
+
+	switch {
+	case ownName != "" && text.Name == ownName:
+		chat.Kind = events.ChatOwn
+	case text.Recipient != "":
+		chat.Kind = events.ChatPrivate
+	case strings.HasPrefix(text.Name, "#"):
+		chat.Kind = events.ChatAnnouncement
+		chat.Name = strings.TrimPrefix(text.Name, "#")
+	case strings.HasPrefix(text.Name, "*"):
+		chat.Kind = events.ChatGuild
+		chat.Name = strings.TrimPrefix(text.Name, "*")
+	case strings.HasPrefix(text.Name, "@"):
+		chat.Kind = events.ChatParty
+		chat.Name = strings.TrimPrefix(text.Name, "@")
+	case text.Name == "":
+		chat.Kind = events.ChatServer
+	default:
+		chat.Kind = events.ChatPublic
+	}
+	return chat
+}
+
+// log writes a chat message to the client log
+func (ch *Chat) log(chat *events.ChatEventData) {
+	switch chat.Kind {
+	case events.ChatOwn:
+		if chat.Recipient != "" {
+			ch.client.logger.Info("Chat", "To %s: %s", chat.Recipient, chat.Text)
+		} else {
+			ch.client.logger.Debug("Chat", "<%s> %s", chat.Name, chat.Text)
+		}
+	case events.ChatPrivate:
+		ch.client.logger.Info("Chat", "From %s: %s", chat.Name, chat.Text)
+	case events.ChatAnnouncement:
+		ch.client.logger.Info("Chat", "[Announcement] %s: %s", chat.Name, chat.Text)
+	case events.ChatGuild:
+		ch.client.logger.Info("Chat", "[Guild] %s: %s", chat.Name, chat.Text)
+	case events.ChatParty:
+		ch.client.logger.Info("Chat", "[Party] %s: %s", chat.Name, chat.Text)
+	case events.ChatServer:
+		ch.client.logger.Info("Chat", "[Server] %s", chat.Text)
+	default:
+		ch.client.logger.Info("Chat", "<%s> %s", chat.Name, chat.Text)
+	}
+}
+
+// dispatch runs the command handler for a message starting with the prefix
+func (ch *Chat) dispatch(chat *events.ChatEventData) {
+	switch chat.Kind {
+	case events.ChatServer, events.ChatAnnouncement:
+		return
+	case events.ChatOwn:
+		// Our own messages echo back, only a tell to ourselves is a command
+		if !strings.EqualFold(chat.Recipient, chat.Name) {
+			return
+		}
+	}
+	if !strings.HasPrefix(chat.Text, ch.Prefix) {
+		return
+	}
+
+	line := strings.TrimPrefix(chat.Text, ch.Prefix)
+	name, raw, _ := strings.Cut(line, " ")
+	name = strings.ToLower(name)
+
+	ch.mu.RLock()
+	cmd, ok := ch.commands[name]
+	allowed := ch.senderAllowed(cmd, chat)
+	ch.mu.RUnlock()
+	if !ok {
+		return
+	}
+	if !allowed {
+		ch.client.logger.Warning("Chat", "%s is not allowed to use %s%s", chat.Name, ch.Prefix, name)
+		return
+	}
+
+	ctx := &CommandContext{
+		Sender:  chat.Name,
+		Kind:    chat.Kind,
+		Command: name,
+		Args:    ParseArgs(raw),
+		Raw:     strings.TrimSpace(raw),
+		chat:    ch,
+	}
+
+	go func() {
+		if len(ctx.Args) < cmd.minArgs {
+			ctx.Reply("Usage: " + ch.Prefix + name + " " + cmd.usage)
+			return
+		}
+		if err := cmd.handler(ctx); err != nil {
+			ch.client.logger.Warning("Chat", "Command %s%s from %s failed: %v", ch.Prefix, name, chat.Name, err)
+		}
+	}()
+}
+
+// senderAllowed checks the global and per-command allow-lists, ch.mu must be
+// held. The player itself may always use commands, guild chat only while no
+// allow-list is set.
+func (ch *Chat) senderAllowed(cmd *command, chat *events.ChatEventData) bool {
+	if cmd == nil {
+		return false
+	}
+	if chat.Kind == events.ChatOwn {
+		return true
+	}
+	if len(ch.allowed) == 0 && len(cmd.allowed) == 0 {
+		return chat.Kind == events.ChatGuild
+	}
+	sender := strings.ToLower(chat.Name)
+	return ch.allowed[sender] || cmd.allowed[sender]
+}
+
+// ParseArgs splits command arguments on spaces, keeping double-quoted
+// arguments together
+func ParseArgs(raw string) []string {
+	var args []string
+	var current strings.Builder
+	quoted, hasArg := false, false
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			hasArg = true
+		case r == ' ' && !quoted:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return args
+}
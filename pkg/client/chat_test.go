@@ -0,0 +1,157 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/server"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{raw: "", want: nil},
+		{raw: "   ", want: nil},
+		{raw: "one", want: []string{"one"}},
+		{raw: " one  two ", want: []string{"one", "two"}},
+		{raw: `say "hello there" now`, want: []string{"say", "hello there", "now"}},
+		{raw: `empty "" arg`, want: []string{"empty", "", "arg"}},
+		{raw: `open "quote runs on`, want: []string{"open", "quote runs on"}},
+	}
+	for _, tt := range tests {
+		got := ParseArgs(tt.raw)
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseArgs(%q) = %q, want %q", tt.raw, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseArgs(%q) = %q, want %q", tt.raw, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestChatParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     server.Text
+		wantKind events.ChatKind
+		wantName string
+	}{
+		{name: "public", text: server.Text{Name: "Player"}, wantKind: events.ChatPublic, wantName: "Player"},
+		{name: "tell", text: server.Text{Name: "Player", Recipient: "Self"}, wantKind: events.ChatPrivate, wantName: "Player"},
+		{name: "own message", text: server.Text{Name: "Self"}, wantKind: events.ChatOwn, wantName: "Self"},
+		{name: "own tell", text: server.Text{Name: "Self", Recipient: "Player"}, wantKind: events.ChatOwn, wantName: "Self"},
+		{name: "announcement", text: server.Text{Name: "#Oryx"}, wantKind: events.ChatAnnouncement, wantName: "Oryx"},
+		{name: "guild", text: server.Text{Name: "*Mate"}, wantKind: events.ChatGuild, wantName: "Mate"},
+		{name: "party", text: server.Text{Name: "@Mate"}, wantKind: events.ChatParty, wantName: "Mate"},
+		{name: "server", text: server.Text{}, wantKind: events.ChatServer},
+	}
+	c := startTestClient(t)
+	if !c.run(func() { c.state.PlayerData.Name = "Self" }) {
+		t.Fatal("state goroutine did not run the setup")
+	}
+	for _, tt := range tests {
+		var chat *events.ChatEventData
+		if !c.run(func() { chat = c.Chat().parse(&tt.text) }) {
+			t.Fatalf("%s: state goroutine did not parse the text", tt.name)
+		}
+		if chat.Kind != tt.wantKind || chat.Name != tt.wantName {
+			t.Errorf("%s: parsed %s from %q, want %s from %q", tt.name, chat.Kind, chat.Name, tt.wantKind, tt.wantName)
+		}
+	}
+}
+
+func TestChatSenderAllowed(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    []string
+		cmdAllowed []string
+		chat       events.ChatEventData
+		want       bool
+	}{
+		{name: "public without allow-list", chat: events.ChatEventData{Kind: events.ChatPublic, Name: "Stranger"}},
+		{name: "tell without allow-list", chat: events.ChatEventData{Kind: events.ChatPrivate, Name: "Stranger"}},
+		{name: "party without allow-list", chat: events.ChatEventData{Kind: events.ChatParty, Name: "Stranger"}},
+		{name: "guild without allow-list", chat: events.ChatEventData{Kind: events.ChatGuild, Name: "Mate"}, want: true},
+		{name: "own tell", chat: events.ChatEventData{Kind: events.ChatOwn, Name: "Self"}, want: true},
+		{name: "own tell with allow-list", allowed: []string{"Friend"}, chat: events.ChatEventData{Kind: events.ChatOwn, Name: "Self"}, want: true},
+		{name: "allowed sender", allowed: []string{"Friend"}, chat: events.ChatEventData{Kind: events.ChatPublic, Name: "friend"}, want: true},
+		{name: "guild with allow-list", allowed: []string{"Friend"}, chat: events.ChatEventData{Kind: events.ChatGuild, Name: "Mate"}},
+		{name: "allowed on the command", cmdAllowed: []string{"Friend"}, chat: events.ChatEventData{Kind: events.ChatPrivate, Name: "Friend"}, want: true},
+		{name: "other sender with a command allow-list", cmdAllowed: []string{"Friend"}, chat: events.ChatEventData{Kind: events.ChatPrivate, Name: "Stranger"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := startTestClient(t).Chat()
+			ch.AllowSender(tt.allowed...)
+			cmd := &command{allowed: make(map[string]bool)}
+			WithAllowedSenders(tt.cmdAllowed...)(cmd)
+
+			ch.mu.RLock()
+			got := ch.senderAllowed(cmd, &tt.chat)
+			ch.mu.RUnlock()
+			if got != tt.want {
+				t.Fatalf("senderAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatDispatchesCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     server.Text
+		wantRun  bool
+		wantArgs int
+	}{
+		{name: "guild command", text: server.Text{Name: "*Mate", RawText: "!echo a b"}, wantRun: true, wantArgs: 2},
+		{name: "tell to ourselves", text: server.Text{Name: "Self", Recipient: "Self", RawText: "!ECHO"}, wantRun: true},
+		{name: "echo of our own tell", text: server.Text{Name: "Self", Recipient: "Player", RawText: "!echo"}},
+		{name: "public command", text: server.Text{Name: "Stranger", RawText: "!echo"}},
+		{name: "unknown command", text: server.Text{Name: "*Mate", RawText: "!other"}},
+		{name: "no prefix", text: server.Text{Name: "*Mate", RawText: "echo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			ran := make(chan *CommandContext, 1)
+			err := c.Chat().RegisterCommand("echo", func(ctx *CommandContext) error {
+				ran <- ctx
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("RegisterCommand failed: %v", err)
+			}
+			if !c.run(func() {
+				c.state.PlayerData.Name = "Self"
+				c.Chat().handleText(&tt.text)
+			}) {
+				t.Fatal("state goroutine did not handle the text")
+			}
+
+			wait := 50 * time.Millisecond
+			if tt.wantRun {
+				wait = time.Second
+			}
+			select {
+			case ctx := <-ran:
+				if !tt.wantRun {
+					t.Fatalf("command ran for %s", ctx.Sender)
+				}
+				if len(ctx.Args) != tt.wantArgs {
+					t.Fatalf("command got args %q, want %d", ctx.Args, tt.wantArgs)
+				}
+			case <-time.After(wait):
+				if tt.wantRun {
+					t.Fatal("command did not run")
+				}
+			}
+		})
+	}
+}
@@ -3,12 +3,14 @@ package client
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"net"
 	"reflect"
-	"strings"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"gorelay/pkg/account"
@@ -22,17 +24,37 @@ import (
 	"gorelay/pkg/packets/dataobjects"
 	"gorelay/pkg/packets/interfaces"
 	"gorelay/pkg/packets/server"
+	"gorelay/pkg/services/proxy"
+)
+
+// Queue sizes for the state goroutine
+const (
+	actionQueueSize   = 64
+	incomingQueueSize = 256
+	runTimeout        = 5 * time.Second
 )
 
 // Client represents a connected RotMG client
 type Client struct {
 	// Connection info
 	conn      net.Conn
-	connected bool
+	connected atomic.Bool
 	server    *models.Server
+	selector  models.ServerSelector
+	dialer    proxy.Dialer
 	mu        sync.Mutex
+	sendMu    sync.Mutex // serializes encryption and writes
 	rc4       *crypto.RC4Manager
 
+	// gameTarget is the game the server sent us to, joined on the next Connect
+	gameTarget *server.Reconnect
+
+	// State goroutine, which owns the world state below
+	actions       chan func()
+	updatePending atomic.Bool
+	loopDone      chan struct{}
+	snapshot      atomic.Pointer[Snapshot]
+
 	// Game state
 	state       *GameState
 	accountInfo *account.Account
@@ -47,10 +69,30 @@ type Client struct {
 	enemies     map[int32]*Enemy
 	players     map[int32]*Player
 	projectiles map[int32]*Projectile
+	containers  map[int32]*models.Container
+	portals     map[int32]*models.Portal
 	currentMap  *Map
+	questID     int32
+	queue       *QueueStatus
+
+	// Death tracking
+	deaths   []*DeathRecord
+	nextChar *charSelection
+
+	// Item management
+	inventory   *Inventory
+	trader      *Trader
+	quests      *QuestManager
+	party       *PartyManager
+	guild       *GuildManager
+	chat        *Chat
+	travel      *Travel
+	safety      *Safety
+	vault       *models.VaultRecord
+	vaultChests map[models.VaultChest]int32
 
 	// Event handling
-	events *events.EventEmitter
+	events *events.Bus
 
 	// Connection management
 	reconnectAttempts    int
@@ -58,6 +100,7 @@ type Client struct {
 	reconnectDelay       time.Duration
 	readTimeout          time.Duration
 	writeTimeout         time.Duration
+	clock                *Clock
 
 	// Logging
 	logger *logger.Logger
@@ -66,10 +109,26 @@ type Client struct {
 	nextPositions []*WorldPosData
 	moveSpeed     float32
 	lastMoveTime  time.Time
+	moveRecords   *models.MoveRecords
+
+	// Client time of the last GroundDamage sent
+	lastGroundDamage int32
 }
 
 // NewClient creates a new RotMG client instance
 func NewClient(acc *account.Account, cfg *config.Config, log *logger.Logger) *Client {
+	// Route web API calls through the account's proxy before talking to the API
+	settings := proxySettings(acc, cfg)
+	dialer, err := proxy.NewDialer(settings, 10*time.Second)
+	if err != nil {
+		log.Error("Client", "Invalid proxy for account %s: %v", acc.Alias, err)
+		return nil
+	}
+	acc.SetHTTPClient(proxy.NewHTTPClient(dialer, 30*time.Second))
+	if settings != nil {
+		log.Info("Client", "Using proxy %s for %s", settings, acc.Alias)
+	}
+
 	// First verify the account if needed
 	if true || acc.NeedAccountVerify() {
 		log.Info("Client", "Verifying account %s (token %s)", acc.Alias, acc.HwidToken)
@@ -88,55 +147,28 @@ func NewClient(acc *account.Account, cfg *config.Config, log *logger.Logger) *Cl
 		}
 	}
 
+	selector := newServerSelector(acc, cfg)
+
 	// Fetch server list using account credentials
-	servers, err := models.FetchServers(acc.Email, acc.Password)
+	servers, err := models.FetchServersWithClient(acc.HTTPClient(), acc.Email, acc.Password)
 	if err != nil {
 		log.Warning("Client", "Failed to fetch servers: %v. Using default server.", err)
 		// Use the default server instead of trying to fetch the list
 		server := models.DefaultServer
-		return createClient(acc, cfg, log, server)
+		return createClient(acc, cfg, log, server, selector)
 	}
 
-	// Get server from account preference or pick first available
-	var server *models.Server
-	if pref := acc.ServerPref; pref != "" {
-		if s, ok := servers[pref]; ok {
-			server = s
-		} else {
-			// If preferred server not found, pick first available
-			foundServer := false
-			for _, s := range servers {
-				server = s
-				foundServer = true
-				break
-			}
-
-			if foundServer {
-				log.Warning("Client", "Preferred server %s not found. Using %s instead.", pref, server.Name)
-			} else {
-				// No servers available in the map, use default
-				server = models.DefaultServer
-				log.Warning("Client", "Preferred server %s not found and no servers available. Using default server %s.",
-					pref, server.Name)
-			}
-		}
-	} else {
-		// If no preference, pick first available
-		foundServer := false
-		for _, s := range servers {
-			server = s
-			foundServer = true
-			break
-		}
-
-		if !foundServer {
-			// No servers available in the map, use default
-			server = models.DefaultServer
-			log.Warning("Client", "No servers available. Using default server %s.", server.Name)
-		}
+	// Pick a server using the configured selection strategy
+	server, err := selector.Select(servers, acc.GUID)
+	if err != nil {
+		server = models.DefaultServer
+		log.Warning("Client", "No servers available (%v). Using default server %s.", err, server.Name)
+	} else if acc.ServerPref != "" && server.Name != acc.ServerPref {
+		log.Warning("Client", "Preferred server %s not available. Using %s instead.", acc.ServerPref, server.Name)
 	}
+	acc.LastServer = server.Name
 
-	client := createClient(acc, cfg, log, server)
+	client := createClient(acc, cfg, log, server, selector)
 	if client == nil {
 		return nil
 	}
@@ -145,13 +177,62 @@ func NewClient(acc *account.Account, cfg *config.Config, log *logger.Logger) *Cl
 	return client
 }
 
+// proxySettings returns the proxy to use for an account. A proxy set on the
+// account overrides the global default from the config.
+func proxySettings(acc *account.Account, cfg *config.Config) *proxy.Settings {
+	if acc != nil && acc.Proxy != nil && acc.Proxy.Host != "" {
+		return &proxy.Settings{
+			Type:     acc.Proxy.Type,
+			Host:     acc.Proxy.Host,
+			Port:     acc.Proxy.Port,
+			Username: acc.Proxy.Username,
+			Password: acc.Proxy.Password,
+		}
+	}
+	if cfg != nil && cfg.Proxy.Enabled && cfg.Proxy.Host != "" {
+		return &proxy.Settings{
+			Type:     cfg.Proxy.Type,
+			Host:     cfg.Proxy.Host,
+			Port:     cfg.Proxy.Port,
+			Username: cfg.Proxy.Username,
+			Password: cfg.Proxy.Password,
+		}
+	}
+	return nil
+}
+
+// newServerSelector builds the server selection strategy for an account
+func newServerSelector(acc *account.Account, cfg *config.Config) models.ServerSelector {
+	if cfg.ServerSelection.FailureCooldown > 0 {
+		models.SetServerFailureCooldown(time.Duration(cfg.ServerSelection.FailureCooldown) * time.Second)
+	}
+
+	// The account preference always comes first, followed by the global list
+	preferred := make([]string, 0, len(cfg.ServerSelection.Preferred)+1)
+	if acc.ServerPref != "" {
+		preferred = append(preferred, acc.ServerPref)
+	}
+	for _, name := range cfg.ServerSelection.Preferred {
+		if name != "" && name != acc.ServerPref {
+			preferred = append(preferred, name)
+		}
+	}
+
+	selector := models.NewServerSelector(cfg.ServerSelection.Strategy, preferred)
+	if sticky, ok := selector.(*models.StickySelector); ok && acc.LastServer != "" {
+		sticky.Remember(acc.GUID, acc.LastServer)
+	}
+	return selector
+}
+
 // createClient creates a new client instance with the given server
-func createClient(acc *account.Account, cfg *config.Config, log *logger.Logger, server *models.Server) *Client {
+func createClient(acc *account.Account, cfg *config.Config, log *logger.Logger, server *models.Server, selector models.ServerSelector) *Client {
 	client := &Client{
 		accountInfo:   acc,
 		config:        cfg,
 		logger:        log,
 		server:        server,
+		selector:      selector,
 		packetHandler: packets.NewPacketHandler(),
 		versionMgr:    packets.NewVersionManager(),
 
@@ -165,7 +246,10 @@ func createClient(acc *account.Account, cfg *config.Config, log *logger.Logger,
 		enemies:     make(map[int32]*Enemy),
 		players:     make(map[int32]*Player),
 		projectiles: make(map[int32]*Projectile),
-		events:      events.NewEventEmitter(),
+		containers:  make(map[int32]*models.Container),
+		portals:     make(map[int32]*models.Portal),
+		events:      events.NewBus(),
+		actions:     make(chan func(), actionQueueSize),
 
 		// Initialize movement management
 		nextPositions: make([]*WorldPosData, 0),
@@ -177,8 +261,25 @@ func createClient(acc *account.Account, cfg *config.Config, log *logger.Logger,
 		reconnectDelay:       time.Duration(cfg.ReconnectDelay) * time.Millisecond,
 		readTimeout:          30 * time.Second,
 		writeTimeout:         10 * time.Second,
+		clock:                newClock(),
+		moveRecords:          models.NewMoveRecords(),
 	}
 
+	client.inventory = newInventory(client)
+	client.trader = newTrader(client)
+	client.quests = newQuestManager(client)
+	client.party = newPartyManager(client)
+	client.guild = newGuildManager(client)
+	client.chat = newChat(client)
+	client.travel = newTravel(client)
+	client.safety = newSafety(client)
+	client.vault = client.loadVault()
+
+	// Report subscribers that panic instead of letting them take down the client
+	client.events.SetPanicHandler(func(event *events.Event, recovered interface{}) {
+		client.logger.Error("Client", "Event handler for event %d panicked: %v", event.Type, recovered)
+	})
+
 	// Register packet handlers
 	client.registerPacketHandlers()
 	client.handlersRegistered = true
@@ -186,57 +287,76 @@ func createClient(acc *account.Account, cfg *config.Config, log *logger.Logger,
 	return client
 }
 
+// Events returns the event bus used to subscribe to game events
+func (c *Client) Events() *events.Bus {
+	return c.events
+}
+
 // emit dispatches an event to all subscribed handlers
-func (c *Client) emit(eventType events.EventType, packet interface{}, data interface{}) {
-	// Create an event with the packet
-	event := &events.Event{
+func (c *Client) emit(eventType events.EventType, packet packets.Packet, data interface{}) {
+	c.events.Publish(&events.Event{
 		Type:   eventType,
 		Client: c,
+		Packet: packet,
 		Data:   data,
+	})
+}
+
+// playerEventData snapshots the local player for event payloads
+func (c *Client) playerEventData() *events.PlayerEventData {
+	data := &events.PlayerEventData{ObjectID: c.state.ObjectID}
+	if c.state.PlayerData != nil {
+		data.Name = c.state.PlayerData.Name
+		data.Level = c.state.PlayerData.Level
+		data.HP = c.state.PlayerData.HP
+		data.MaxHP = c.state.PlayerData.MaxHP
+	}
+	if c.state.WorldPos != nil {
+		data.Position = events.Position{X: c.state.WorldPos.X, Y: c.state.WorldPos.Y}
 	}
+	return data
+}
 
-	// Handle different packet types
-	switch p := packet.(type) {
-	case *server.Goto:
-		// Create a wrapper for Goto that adapts the Write method
-		wrapper := &packetWrapper{
-			id: int32(interfaces.Goto),
-			writeFunc: func(w *packets.PacketWriter) error {
-				return p.Write(w)
-			},
-			hasNulls:   func() bool { return false },
-			packetType: interfaces.Goto,
-		}
-		event.Packet = wrapper
-	case *client.PlayerShoot:
-		// Create a wrapper for PlayerShoot that adapts the Write method
-		wrapper := &packetWrapper{
-			id: int32(interfaces.PlayerShoot),
-			writeFunc: func(w *packets.PacketWriter) error {
-				return p.Write(w)
-			},
-			hasNulls:   func() bool { return false },
-			packetType: interfaces.PlayerShoot,
-		}
-		event.Packet = wrapper
-	case packets.Packet:
-		// Use the Packet interface directly if implemented
-		event.Packet = p
-	default:
-		// For other types, just set the packet to nil
-		event.Packet = nil
+// newGameState returns the state of a client that has not joined a game yet
+func newGameState() *GameState {
+	return &GameState{
+		WorldPos:      &WorldPosData{X: 0, Y: 0},
+		PlayerData:    &PlayerData{},
+		LastUpdate:    time.Now(),
+		LastFrameTime: time.Now().UnixNano() / int64(time.Millisecond),
 	}
+}
 
-	// Emit the event
-	c.events.Emit(event)
+// waitForLoop waits until the state goroutine of the last connection ended
+func (c *Client) waitForLoop() {
+	c.mu.Lock()
+	done := c.loopDone
+	c.mu.Unlock()
+	if done != nil {
+		<-done
+	}
 }
 
 // Connect establishes a connection to the game server
 func (c *Client) Connect() error {
+	// Publish the connect event after the lock below has been released so
+	// subscribers can call back into the client
+	var connected *events.ConnectionEventData
+	defer func() {
+		if connected != nil {
+			c.emit(events.EventConnect, nil, connected)
+		}
+	}()
+
+	// The previous connection's state goroutine must be done with the state
+	if !c.connected.Load() {
+		c.waitForLoop()
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.connected {
+	if c.connected.Load() {
 		return fmt.Errorf("client already connected")
 	}
 
@@ -248,12 +368,7 @@ func (c *Client) Connect() error {
 		return fmt.Errorf("logger not initialized")
 	}
 	if c.state == nil {
-		c.state = &GameState{
-			WorldPos:      &WorldPosData{X: 0, Y: 0},
-			PlayerData:    &PlayerData{},
-			LastUpdate:    time.Now(),
-			LastFrameTime: time.Now().UnixNano() / int64(time.Millisecond),
-		}
+		c.state = newGameState()
 	}
 
 	// Initialize RC4 encryption
@@ -278,70 +393,76 @@ func (c *Client) Connect() error {
 		}
 	}
 
+	// Set up the dialer, going through a proxy if one is configured
+	if c.dialer == nil {
+		settings := proxySettings(c.accountInfo, c.config)
+		dialer, err := proxy.NewDialer(settings, c.writeTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to set up proxy: %v", err)
+		}
+		if settings != nil {
+			c.logger.Info("Client", "Connecting through proxy %s", settings)
+		}
+		c.dialer = dialer
+	}
+
+	// A game switch is only good for the connection that follows it
+	target := c.gameTarget
+	c.gameTarget = nil
+
 	var lastErr error
 	for attempt := 0; attempt <= c.maxReconnectAttempts; attempt++ {
 		if attempt > 0 {
 			c.logger.Info("Client", "Reconnection attempt %d/%d in %v...",
 				attempt, c.maxReconnectAttempts, c.reconnectDelay)
 			time.Sleep(c.reconnectDelay)
-		}
-
-		addr := fmt.Sprintf("%s:%d", c.server.Address, 2050)
-		var conn net.Conn
-		var err error
 
-		// Use proxy if configured
-		if c.accountInfo != nil && c.accountInfo.Proxy != nil {
-			proxyAddr := fmt.Sprintf("%s:%d", c.accountInfo.Proxy.Host, c.accountInfo.Proxy.Port)
-			c.logger.Info("Client", "Connecting through proxy %s", proxyAddr)
-			conn, err = net.Dial("tcp", proxyAddr)
-			if err != nil {
-				lastErr = fmt.Errorf("failed to connect to proxy: %v", err)
-				continue
-			}
-
-			// Send CONNECT request to proxy
-			connectReq := fmt.Sprintf("CONNECT %s:2050 HTTP/1.1\r\nHost: %s:2050\r\n\r\n", c.server.Address, c.server.Address)
-			if _, err := conn.Write([]byte(connectReq)); err != nil {
-				conn.Close()
-				lastErr = fmt.Errorf("failed to send CONNECT request to proxy: %v", err)
-				continue
-			}
-
-			// Read proxy response
-			resp := make([]byte, 1024)
-			n, err := conn.Read(resp)
-			if err != nil {
-				conn.Close()
-				lastErr = fmt.Errorf("failed to read proxy response: %v", err)
-				continue
+			// Move on to another server if the current one keeps failing
+			if !models.IsServerHealthy(c.server.Name) {
+				if next := c.selectServer(); next.Name != c.server.Name {
+					c.logger.Info("Client", "Server %s is failing, switching to %s", c.server.Name, next.Name)
+					c.server = next
+					if c.accountInfo != nil {
+						c.accountInfo.LastServer = next.Name
+					}
+				}
 			}
+		}
 
-			// Check for successful connection - accept both "200 OK" and "200 Connection established"
-			response := string(resp[:n])
-			if !strings.Contains(response, "200") {
-				conn.Close()
-				lastErr = fmt.Errorf("proxy connection failed: %s", response)
-				continue
+		addr := net.JoinHostPort(c.server.Address, "2050")
+		if target != nil && target.Host != "" {
+			port := "2050"
+			if target.Port != 0 {
+				port = strconv.Itoa(int(target.Port))
 			}
-		} else {
-			conn, err = net.Dial("tcp", addr)
-			if err != nil {
-				lastErr = fmt.Errorf("failed to connect: %v", err)
-				continue
+			addr = net.JoinHostPort(target.Host, port)
+		}
+		conn, err := c.dialer.Dial("tcp", addr)
+		if err != nil {
+			// Only blame the server when the proxy itself was reachable
+			if !errors.Is(err, proxy.ErrProxyUnavailable) {
+				models.MarkServerFailed(c.server.Name)
 			}
+			lastErr = fmt.Errorf("failed to connect: %v", err)
+			continue
 		}
+		models.MarkServerHealthy(c.server.Name)
 
 		// Set connection timeouts
-		tcpConn := conn.(*net.TCPConn)
-		tcpConn.SetKeepAlive(true)
-		tcpConn.SetKeepAlivePeriod(60 * time.Second)
-		tcpConn.SetReadBuffer(8192)
-		tcpConn.SetWriteBuffer(8192)
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			tcpConn.SetKeepAlive(true)
+			tcpConn.SetKeepAlivePeriod(60 * time.Second)
+			tcpConn.SetReadBuffer(8192)
+			tcpConn.SetWriteBuffer(8192)
+		}
 
+		c.sendMu.Lock()
 		c.conn = conn
-		c.connected = true
+		c.sendMu.Unlock()
+		c.connected.Store(true)
 		c.reconnectAttempts = 0
+		c.clock.Reset()
+		c.moveRecords.Clear(-1)
 
 		// Register packet handlers if not already done
 		if !c.handlersRegistered {
@@ -356,6 +477,11 @@ func (c *Client) Connect() error {
 		hello.AccessToken = c.accountInfo.AccessToken
 		hello.KeyTime = -1
 		hello.Key = []byte{}
+		if target != nil {
+			hello.GameID = target.GameId
+			hello.KeyTime = target.KeyTime
+			hello.Key = target.Key
+		}
 		hello.GameNet = "rotmg"
 		hello.PlayPlatform = "rotmg"
 		hello.PlatformToken = ""
@@ -370,9 +496,11 @@ func (c *Client) Connect() error {
 			continue
 		}
 
-		// Start packet handling goroutine
-		go c.handlePackets()
+		// Start the state goroutine
+		c.loopDone = make(chan struct{})
+		go c.handlePackets(conn, c.loopDone)
 
+		connected = c.connectionEventData(attempt)
 		return nil
 	}
 
@@ -381,8 +509,14 @@ func (c *Client) Connect() error {
 }
 
 // Send sends a packet to the server
-func (c *Client) Send(packet interface{}) error {
-	if !c.connected {
+func (c *Client) Send(packet packets.Packet) error {
+	if !c.connected.Load() {
+		return fmt.Errorf("not connected")
+	}
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if c.conn == nil {
 		return fmt.Errorf("not connected")
 	}
 
@@ -391,144 +525,52 @@ func (c *Client) Send(packet interface{}) error {
 		return fmt.Errorf("failed to set write deadline: %v", err)
 	}
 
-	var data []byte
-	var err error
-	var packetType interfaces.PacketType
-
-	// Handle different packet types
-	switch p := packet.(type) {
-	case *client.Load:
-		// Create packet header
-		writer := packets.NewPacketWriter()
-		// Write packet size (4 bytes) - will update after writing data
-		writer.WriteInt32(0)
-		// Write packet type (1 byte)
-		writer.WriteByte(byte(interfaces.Load))
-		// Write packet data
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write Load packet: %v", err)
-		}
-		// Update packet size
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = interfaces.Load
-
-	case *client.Create:
-		writer := packets.NewPacketWriter()
-		writer.WriteInt32(0)
-		writer.WriteByte(byte(interfaces.Create))
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write Create packet: %v", err)
-		}
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = interfaces.Create
-
-	case *client.Pong:
-		writer := packets.NewPacketWriter()
-		writer.WriteInt32(0)
-		writer.WriteByte(byte(interfaces.Pong))
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write Pong packet: %v", err)
-		}
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = interfaces.Pong
-
-	case *client.UpdateAck:
-		writer := packets.NewPacketWriter()
-		writer.WriteInt32(0)
-		writer.WriteByte(byte(interfaces.UpdateAck))
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write UpdateAck packet: %v", err)
-		}
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = interfaces.UpdateAck
-
-	case *client.ShootAckCounter:
-		writer := packets.NewPacketWriter()
-		writer.WriteInt32(0)
-		writer.WriteByte(byte(interfaces.ShootAckCounter))
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write ShootAckCounter packet: %v", err)
-		}
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = interfaces.ShootAckCounter
-
-	case *client.Move:
-		writer := packets.NewPacketWriter()
-		writer.WriteInt32(0)
-		writer.WriteByte(byte(interfaces.Move))
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write Move packet: %v", err)
-		}
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = interfaces.Move
-
-	case *client.GotoAck:
-		writer := packets.NewPacketWriter()
-		writer.WriteInt32(0)
-		writer.WriteByte(byte(interfaces.GotoAck))
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write GotoAck packet: %v", err)
-		}
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = interfaces.GotoAck
-
-	case packets.Packet:
-		writer := packets.NewPacketWriter()
-		writer.WriteInt32(0)
-		writer.WriteByte(byte(p.Type()))
-		if err := p.Write(writer); err != nil {
-			return fmt.Errorf("failed to write packet: %v", err)
-		}
-		data = writer.Bytes()
-		binary.BigEndian.PutUint32(data[0:4], uint32(len(data)))
-		packetType = p.Type()
-
-	default:
-		return fmt.Errorf("unsupported packet type: %T", packet)
-	}
-
+	data, err := packets.EncodePacket(packet)
 	if err != nil {
-		return fmt.Errorf("failed to encode packet: %v", err)
+		return fmt.Errorf("failed to encode %T: %v", packet, err)
 	}
 
 	// Log outgoing packet before encryption
 	c.logger.Debug("Client", "SEND [%d] Type: %d, Length: %d, Data: %#v",
-		packetType, int(packetType), len(data), packet)
-
-	// Make a copy of the data for encryption
-	encryptedData := make([]byte, len(data))
-	copy(encryptedData, data)
+		packet.Type(), packet.ID(), len(data), packet)
 
 	// Encrypt if RC4 is initialized
 	if c.rc4 != nil {
-		c.rc4.Encrypt(encryptedData)
+		c.rc4.Encrypt(data)
 	}
 
 	// Send the encrypted packet
-	_, err = c.conn.Write(encryptedData)
+	_, err = c.conn.Write(data)
 	return err
 }
 
 // Disconnect closes the connection to the game server
 func (c *Client) Disconnect() {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if !c.connected {
+	if !c.connected.Load() {
+		c.mu.Unlock()
 		return
 	}
 
 	if c.conn != nil {
 		c.conn.Close()
 	}
-	c.connected = false
+	c.connected.Store(false)
+	data := c.connectionEventData(0)
+	c.mu.Unlock()
+
+	c.emit(events.EventDisconnect, nil, data)
+	c.leaveQueue(nil)
+}
+
+// connectionEventData describes the current server for connection events
+func (c *Client) connectionEventData(attempt int) *events.ConnectionEventData {
+	data := &events.ConnectionEventData{Attempt: attempt}
+	if c.server != nil {
+		data.Server = c.server.Name
+		data.Address = c.server.Address
+	}
+	return data
 }
 
 var packetTypes = map[interfaces.PacketType]packets.Packet{
@@ -612,6 +654,33 @@ func (c *Client) registerPacketHandlers() {
 		mapInfo := packet.(*server.MapInfo)
 		c.logger.Info("Client", "MapInfo: %v", mapInfo)
 
+		// Objects from the previous map are gone
+		c.leaveQueue(mapInfo)
+		c.resetTracking()
+		c.resetMoveRecords()
+		c.currentMap = &Map{
+			Name:       mapInfo.Name,
+			Width:      mapInfo.Width,
+			Height:     mapInfo.Height,
+			Seed:       mapInfo.Seed,
+			ViewRadius: int32(mapInfo.ViewRadius),
+		}
+		if c.inVault() {
+			c.enterVault()
+		}
+		c.travel.handleMapInfo(mapInfo)
+		c.emit(events.EventMapInfo, mapInfo, &events.MapEventData{
+			Width:       mapInfo.Width,
+			Height:      mapInfo.Height,
+			Name:        mapInfo.Name,
+			DisplayName: mapInfo.DisplayName,
+			RealmName:   mapInfo.RealmName,
+			Seed:        mapInfo.Seed,
+		})
+
+		// A character picked after a death replaces the previous selection
+		c.applyCharSelection()
+
 		// First check if we have a character ID in the account config
 		if c.accountInfo != nil && c.accountInfo.CharInfo != nil && c.accountInfo.CharInfo.CharID > 0 {
 			c.logger.Info("Client", "Loading character %d from config", c.accountInfo.CharInfo.CharID)
@@ -636,9 +705,13 @@ func (c *Client) registerPacketHandlers() {
 		}
 
 		if needsNewChar {
-			c.logger.Info("Client", "Creating new character")
+			classType := c.config.Death.ClassType
+			if classType == 0 {
+				classType = config.DefaultClassType
+			}
+			c.logger.Info("Client", "Creating new character of class %d", classType)
 			create := &client.Create{
-				ClassType:    768, //wizard
+				ClassType:    classType,
 				SkinType:     0,
 				IsChallenger: false,
 				IsSeasonal:   false,
@@ -667,7 +740,13 @@ func (c *Client) registerPacketHandlers() {
 			packetPos := &WorldPosData{X: float32(aoe.Location.X), Y: float32(aoe.Location.Y)}
 			if packetPos.SquareDistanceTo(c.state.WorldPos) < aoe.Radius*aoe.Radius {
 				// Apply AoE damage
-				c.applyDamage(int32(aoe.Damage), aoe.ArmorPierce)
+				c.applyDamage(aoe, int32(aoe.Damage), aoe.ArmorPierce)
+				c.emit(events.EventPlayerDamage, aoe, &events.DamageEventData{
+					TargetID:      c.state.ObjectID,
+					Damage:        int32(aoe.Damage),
+					ArmorPiercing: aoe.ArmorPierce,
+					Position:      events.Position{X: packetPos.X, Y: packetPos.Y},
+				})
 			}
 		}
 		return nil
@@ -679,7 +758,7 @@ func (c *Client) registerPacketHandlers() {
 
 		// Send ShootAck as keep-alive response
 		shootAck := &client.ShootAckCounter{
-			Time:   int32(time.Now().UnixNano() / int64(time.Millisecond)),
+			Time:   c.clock.ClientTime(),
 			Amount: 1,
 		}
 
@@ -694,6 +773,30 @@ func (c *Client) registerPacketHandlers() {
 				c.addProjectile(int32(enemyShoot.BulletType), enemyShoot.OwnerId, int32(enemyShoot.BulletId)+int32(i), angle, startPos)
 			}
 		}
+		c.emit(events.EventEnemyShoot, enemyShoot, &events.ProjectileEventData{
+			OwnerID:      enemyShoot.OwnerId,
+			ProjectileID: int32(enemyShoot.BulletId),
+			Position:     events.Position{X: float32(enemyShoot.Location.X), Y: float32(enemyShoot.Location.Y)},
+			Damage:       int32(enemyShoot.Damage),
+		})
+		return nil
+	})
+
+	// Handle damage dealt to enemies
+	c.packetHandler.RegisterHandler(int(interfaces.Damage), func(packet packets.Packet) error {
+		c.handleDamage(packet.(*server.Damage))
+		return nil
+	})
+
+	// Handle game switches, e.g. after using a portal
+	c.packetHandler.RegisterHandler(int(interfaces.Reconnect), func(packet packets.Packet) error {
+		c.handleReconnect(packet.(*server.Reconnect))
+		return nil
+	})
+
+	// Handle quest target changes
+	c.packetHandler.RegisterHandler(int(interfaces.QuestObjectId), func(packet packets.Packet) error {
+		c.handleQuestObjectID(packet.(*server.QuestObjectId))
 		return nil
 	})
 
@@ -701,16 +804,16 @@ func (c *Client) registerPacketHandlers() {
 	c.packetHandler.RegisterHandler(int(interfaces.Ping), func(packet packets.Packet) error {
 		ping := packet.(*server.Ping)
 
-		// Create and send pong response with proper time calculation
-		currentTime := time.Now().UnixMilli()
+		// The server times our reply and reports the round trip in NewTick
 		pong := &client.Pong{
 			Serial: ping.Serial,
-			Time:   int32(currentTime % (1 << 31)), // Ensure time fits in int32
+			Time:   c.clock.ClientTime(),
 		}
 
 		if err := c.Send(pong); err != nil {
 			c.logger.Error("Client", "Failed to send Pong: %v", err)
 		}
+		c.emit(events.EventPing, ping, nil)
 		return nil
 	})
 
@@ -720,10 +823,10 @@ func (c *Client) registerPacketHandlers() {
 
 		// Update player position if provided and non-zero
 		if update.PlayerPosition != nil && (update.PlayerPosition.X != 0 || update.PlayerPosition.Y != 0) {
-			c.state.WorldPos = &WorldPosData{
+			c.setPosition(&WorldPosData{
 				X: float32(update.PlayerPosition.X),
 				Y: float32(update.PlayerPosition.Y),
-			}
+			})
 			c.logger.Debug("Client", "Updated position to X=%f, Y=%f", c.state.WorldPos.X, c.state.WorldPos.Y)
 		}
 
@@ -733,16 +836,18 @@ func (c *Client) registerPacketHandlers() {
 			c.logger.Error("Client", "Failed to send UpdateAck: %v", err)
 		}
 
+		c.updateTiles(update.Tiles)
+
 		// Process new objects
 		for _, entity := range update.NewObjs {
-			c.handleNewObject(entity)
+			c.handleNewObject(update, entity)
 		}
 
 		// Process dropped objects
 		for _, objID := range update.Drops {
-			delete(c.enemies, objID)
-			delete(c.players, objID)
+			c.handleDroppedObject(update, objID)
 		}
+		c.emit(events.EventUpdate, update, nil)
 		return nil
 	})
 
@@ -752,82 +857,61 @@ func (c *Client) registerPacketHandlers() {
 
 		// Update last frame time from server's tick time
 		c.state.LastFrameTime = int64(newTick.ServerRealTimeMs)
+		c.clock.sync(newTick.TickId, newTick.ServerRealTimeMs, newTick.ServerLastRTTMS)
+
+		// Process statuses
+		for _, status := range newTick.Statuses {
+			if int32(status.ObjectID) == c.state.ObjectID {
+				if status.Position != nil && (status.Position.X != 0 || status.Position.Y != 0) {
+					c.setPosition(&WorldPosData{X: float32(status.Position.X), Y: float32(status.Position.Y)})
+					c.logger.Debug("Client", "Updated position from status to X=%f, Y=%f", c.state.WorldPos.X, c.state.WorldPos.Y)
+				}
+				c.applyPlayerStats(newTick, status.Data)
+			} else {
+				c.handleObjectStatus(newTick, status)
+			}
+		}
 
 		// Create and send move packet with correct timing
+		now := c.clock.ClientTime()
 		movePacket := client.NewMove()
 		movePacket.TickID = newTick.TickId // Use server's tick ID
-		movePacket.Time = int32(newTick.ServerRealTimeMs)
-
-		// Add current position
-		record := dataobjects.NewLocationRecord()
-		record.Time = int32(newTick.ServerRealTimeMs)
+		movePacket.Time = now
 
 		// Only send position if we have a valid one
-		if c.state.WorldPos != nil && (c.state.WorldPos.X != 0 || c.state.WorldPos.Y != 0) {
-			record.Position = dataobjects.NewLocationWithCoords(float64(c.state.WorldPos.X), float64(c.state.WorldPos.Y))
-			c.logger.Debug("Client", "Sending Move with position X=%f, Y=%f", c.state.WorldPos.X, c.state.WorldPos.Y)
-		} else {
-			// If we don't have a valid position, don't send the move packet
+		if c.state.WorldPos == nil || (c.state.WorldPos.X == 0 && c.state.WorldPos.Y == 0) {
 			c.logger.Debug("Client", "Skipping Move packet - no valid position")
-			return nil
-		}
-
-		movePacket.Records = append(movePacket.Records, record)
-
-		if err := c.Send(movePacket); err != nil {
-			c.logger.Error("Client", "Failed to send Move response to NewTick: %v", err)
-		}
+		} else {
+			// Send the positions recorded since the last tick, ending with the current one
+			movePacket.Records = c.takeMoveRecords(now)
+			c.logger.Debug("Client", "Sending Move with %d records, position X=%f, Y=%f",
+				len(movePacket.Records), c.state.WorldPos.X, c.state.WorldPos.Y)
 
-		// Process statuses
-		for _, status := range newTick.Statuses {
-			if int32(status.ObjectID) == c.state.ObjectID {
-				if status.Position != nil && (status.Position.X != 0 || status.Position.Y != 0) {
-					c.state.WorldPos = &WorldPosData{X: float32(status.Position.X), Y: float32(status.Position.Y)}
-					c.logger.Debug("Client", "Updated position from status to X=%f, Y=%f", c.state.WorldPos.X, c.state.WorldPos.Y)
-				}
-				for _, stat := range status.Data {
-					if stat.IsStringData() {
-						c.updateStat(int32(stat.ID), 0, stat.StringValue)
-					} else {
-						c.updateStat(int32(stat.ID), int32(stat.IntValue), "")
-					}
-				}
+			if err := c.Send(movePacket); err != nil {
+				c.logger.Error("Client", "Failed to send Move response to NewTick: %v", err)
 			}
+			c.checkGroundDamage(now)
 		}
+
+		c.emit(events.EventNewTick, newTick, nil)
 		return nil
 	})
 
 	// Handle text packets
 	c.packetHandler.RegisterHandler(int(interfaces.Text), func(packet packets.Packet) error {
-		text := packet.(*server.Text)
-
-		// Determine message type and format appropriately
-		switch {
-		case text.Recipient != "": // Private message
-			if text.Name == "" {
-				// System tell (from game)
-				c.logger.Info("Client", "From: %s> %s", text.Name, text.RawText)
-			} else {
-				// Player tell
-				c.logger.Info("Client", "From %s: %s", text.Name, text.RawText)
-			}
-		case strings.HasPrefix(text.Name, "#"): // Oryx/Admin message
-			c.logger.Info("Client", "[Announcement] %s: %s", strings.TrimPrefix(text.Name, "#"), text.RawText)
-		case strings.HasPrefix(text.Name, "*"): // Guild message
-			c.logger.Info("Client", "[Guild] %s: %s", strings.TrimPrefix(text.Name, "*"), text.RawText)
-		case strings.HasPrefix(text.Name, "@"): // Party message
-			c.logger.Info("Client", "[Party] %s: %s", strings.TrimPrefix(text.Name, "@"), text.RawText)
-		case text.Name == "": // Pure server message
-			c.logger.Info("Client", "[Server] %s", text.RawText)
-		default: // Normal chat
-			c.logger.Info("Client", "<%s> %s", text.Name, text.RawText)
-		}
-
+		c.chat.handleText(packet.(*server.Text))
 		return nil
 	})
 
 	// Handle notification packets
 	c.packetHandler.RegisterHandler(int(interfaces.Notification), func(packet packets.Packet) error {
+		notification := packet.(*server.Notification)
+		c.emit(events.EventNotification, notification, &events.NotificationEventData{
+			ObjectID: notification.ObjectId,
+			Type:     int32(notification.NotificationType),
+			Message:  notification.Message,
+			Color:    notification.Color,
+		})
 		return nil
 	})
 
@@ -845,6 +929,16 @@ func (c *Client) registerPacketHandlers() {
 			shoot.BulletId, shoot.OwnerId, shoot.ContainerType,
 			shoot.StartingPos.X, shoot.StartingPos.Y,
 			shoot.Angle, shoot.Damage)
+
+		data := &events.ProjectileEventData{
+			OwnerID:      shoot.OwnerId,
+			ProjectileID: shoot.BulletId,
+			Damage:       int32(shoot.Damage),
+		}
+		if shoot.StartingPos != nil {
+			data.Position = events.Position{X: float32(shoot.StartingPos.X), Y: float32(shoot.StartingPos.Y)}
+		}
+		c.emit(events.EventPlayerShoot, shoot, data)
 		return nil
 	})
 
@@ -894,6 +988,7 @@ func (c *Client) registerPacketHandlers() {
 			c.logger.Debug("Client", "Received unknown effect type: %d", effect.EffectType)
 		}
 
+		c.emit(events.EventShowEffect, effect, nil)
 		return nil
 	})
 
@@ -958,71 +1053,224 @@ func (c *Client) registerPacketHandlers() {
 			c.accountInfo.CharInfo.CharID = createSuccess.CharId
 		}
 
+		c.emit(events.EventCreateSuccess, createSuccess, c.playerEventData())
+
+		if c.config.Quests.AutoFetch {
+			go func() {
+				if _, err := c.quests.Fetch(); err != nil {
+					c.logger.Warning("Quests", "Failed to fetch quests: %v", err)
+				}
+			}()
+		}
 		return nil
 	})
 
 	// Handle goto packets
 	c.packetHandler.RegisterHandler(int(interfaces.Goto), func(packet packets.Packet) error {
-		gotoPacket := &server.Goto{}
-		// TODO: Implement packet decoding
+		gotoPacket := packet.(*server.Goto)
 
 		// Create and send acknowledgment
 		gotoAck := client.NewGotoAck()
-		gotoAck.Time = int32(c.state.LastFrameTime)
+		gotoAck.Time = c.clock.ClientTime()
 		gotoAck.Unknown = false
 
 		if err := c.Send(gotoAck); err != nil {
 			c.logger.Error("Client", "Failed to send GotoAck: %v", err)
 		}
 
+		x, y := float32(gotoPacket.Location.X), float32(gotoPacket.Location.Y)
 		if gotoPacket.ObjectId == c.state.ObjectID {
-			// Convert Location to WorldPosData
-			pos := &WorldPosData{X: float32(gotoPacket.Location.X), Y: float32(gotoPacket.Location.Y)}
-			c.state.WorldPos = pos
-			c.emit(events.EventPlayerMove, gotoPacket, &events.PlayerEventData{
-				PlayerData: c.state.PlayerData,
-				Position:   c.state.WorldPos,
-			})
+			c.setPosition(&WorldPosData{X: x, Y: y})
+			c.emit(events.EventPlayerMove, gotoPacket, c.playerEventData())
+			return nil
 		}
+
+		// Other objects are moved by the server as well
+		now := time.Now().UnixMilli()
+		if enemy, ok := c.enemies[gotoPacket.ObjectId]; ok {
+			enemy.OnGoto(x, y, now)
+		} else if player, ok := c.players[gotoPacket.ObjectId]; ok {
+			player.OnGoto(x, y, now)
+		}
+		return nil
+	})
+
+	// Handle inventory results
+	c.packetHandler.RegisterHandler(int(interfaces.InventoryResult), func(packet packets.Packet) error {
+		c.inventory.handleResult(packet.(*server.InventoryResult))
+		return nil
+	})
+
+	// Handle death packets
+	c.packetHandler.RegisterHandler(int(interfaces.Death), func(packet packets.Packet) error {
+		return c.handleDeath(packet.(*server.Death))
+	})
+
+	// Handle quest and mission packets
+	c.packetHandler.RegisterHandler(int(interfaces.QuestFetchResponse), func(packet packets.Packet) error {
+		c.quests.handleFetchResponse(packet.(*server.QuestFetchResponse))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.QuestRedeemResponse), func(packet packets.Packet) error {
+		c.quests.handleRedeemResponse(packet.(*server.QuestRedeemResponse))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.ResetDailyQuests), func(packet packets.Packet) error {
+		c.quests.handleReset(packet.(*server.ResetDailyQuests))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.MissionProgressUpdate), func(packet packets.Packet) error {
+		c.quests.handleMissionProgress(packet.(*server.MissionProgressUpdate))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.ClaimMissionResult), func(packet packets.Packet) error {
+		c.quests.handleClaimResult(packet.(*server.ClaimMissionResult))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.Queue), func(packet packets.Packet) error {
+		c.handleQueue(packet.(*server.Queue))
+		return nil
+	})
+
+	// Handle guild packets
+	c.packetHandler.RegisterHandler(int(interfaces.InvitedToGuild), func(packet packets.Packet) error {
+		c.guild.handleInvited(packet.(*server.InvitedToGuild))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.GuildResult), func(packet packets.Packet) error {
+		c.guild.handleResult(packet.(*server.GuildResult))
+		return nil
+	})
+
+	// Handle party packets
+	c.packetHandler.RegisterHandler(int(interfaces.IncomingPartyInvite), func(packet packets.Packet) error {
+		c.party.handleInvite(packet.(*server.IncomingPartyInvite))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.IncomingPartyMemberInfo), func(packet packets.Packet) error {
+		c.party.handleMemberInfo(packet.(*server.IncomingPartyMemberInfo))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.PartyMemberAdded), func(packet packets.Packet) error {
+		c.party.handleMemberAdded(packet.(*server.PartyMemberAdded))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.PartyJoinRequestResponse), func(packet packets.Packet) error {
+		c.party.handleJoinRequestResponse(packet.(*server.PartyJoinRequestResponse))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.PartyAction), func(packet packets.Packet) error {
+		c.party.handleAction(packet.(*server.PartyAction))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.PartyList), func(packet packets.Packet) error {
+		c.party.handleList(packet.(*server.PartyList))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.PartyJoinResponse), func(packet packets.Packet) error {
+		c.party.handleJoinResponse(packet.(*server.PartyJoinResponse))
 		return nil
 	})
 
 	// Handle MultipleMissionsProgressUpdate packets
 	c.packetHandler.RegisterHandler(int(interfaces.MultipleMissionsProgressUpdate), func(packet packets.Packet) error {
-		missionUpdate := packet.(*server.MultipleMissionsProgressUpdate)
-		c.logger.Debug("Client", "Received mission progress update: %s", missionUpdate.UnknownString)
+		c.quests.handleMultipleMissionsProgress(packet.(*server.MultipleMissionsProgressUpdate))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.VaultContent), func(packet packets.Packet) error {
+		c.handleVaultContent(packet.(*server.VaultContent))
 		return nil
 	})
 
 	// Handle Trade packets
 	c.packetHandler.RegisterHandler(int(interfaces.TradeRequested), func(packet packets.Packet) error {
-		trade := packet.(*server.TradeRequested)
-		c.logger.Debug("Client", "Received trade request from: %s", trade.Name)
+		c.trader.handleRequested(packet.(*server.TradeRequested))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.TradeStart), func(packet packets.Packet) error {
+		c.trader.handleStart(packet.(*server.TradeStart))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.TradeChanged), func(packet packets.Packet) error {
+		c.trader.handleChanged(packet.(*server.TradeChanged))
+		return nil
+	})
+
+	c.packetHandler.RegisterHandler(int(interfaces.TradeAccepted), func(packet packets.Packet) error {
+		c.trader.handleAccepted(packet.(*server.TradeAccepted))
+		return nil
+	})
 
-		// For now, we'll just log the trade request
-		// You can implement trade acceptance/rejection logic here if needed
+	c.packetHandler.RegisterHandler(int(interfaces.TradeDone), func(packet packets.Packet) error {
+		c.trader.handleDone(packet.(*server.TradeDone))
 		return nil
 	})
 }
 
 // Helper methods
 
-func (c *Client) applyDamage(damage int32, armorPiercing bool) {
-	// TODO: Implement damage calculation and application
+// applyDamage lets the safety guard act on a hit before the server reports
+// the player's new health
+func (c *Client) applyDamage(packet packets.Packet, damage int32, armorPiercing bool) {
+	data := c.state.PlayerData
+	if data == nil {
+		return
+	}
+	c.safety.handleDamage(packet, damageAfterDefense(damage, data.Stats.Defense, data.Effects, armorPiercing))
 }
 
 func (c *Client) addProjectile(bulletType, ownerID, bulletID int32, angle float32, startPos *WorldPosData) {
 	// TODO: Implement projectile tracking
 }
 
-func (c *Client) updateStat(statType int32, statValue int32, stringValue string) {
+func (c *Client) updateStat(packet packets.Packet, statType int32, statValue int32, stringValue string) {
 	if c.state.PlayerData == nil {
-		c.state.PlayerData = &PlayerData{
-			Stats:     make(map[string]int32),
-			Inventory: make([]int32, 20), // 12 inventory + 8 backpack slots
+		c.state.PlayerData = &PlayerData{}
+	}
+	if c.state.PlayerData.Inventory == nil {
+		c.state.PlayerData.Inventory = make([]int32, TotalSlots) // 12 inventory + 8 backpack slots
+		for i := range c.state.PlayerData.Inventory {
+			c.state.PlayerData.Inventory[i] = EmptyItem
 		}
 	}
 
+	// Skip stats that did not change and remember the previous value for listeners
+	if c.state.rawStats == nil {
+		c.state.rawStats = make(map[int32]rawStat)
+	}
+	previous, seen := c.state.rawStats[statType]
+	current := rawStat{value: statValue, stringValue: stringValue}
+	if seen && previous == current {
+		return
+	}
+	c.state.rawStats[statType] = current
+	defer c.emit(events.EventStatChange, packet, &events.StatEventData{
+		ObjectID:       c.state.ObjectID,
+		StatType:       statType,
+		Name:           dataobjects.StatsType(statType).String(),
+		OldValue:       previous.value,
+		NewValue:       statValue,
+		OldStringValue: previous.stringValue,
+		NewStringValue: stringValue,
+	})
+
+	c.state.PlayerData.Stats.set(models.StatType(statType), statValue, stringValue)
+
 	switch models.StatType(statType) {
 	case models.MAXHPSTAT:
 		c.state.PlayerData.MaxHP = statValue
@@ -1040,18 +1288,6 @@ func (c *Client) updateStat(statType int32, statValue int32, stringValue string)
 		c.state.PlayerData.Level = statValue
 	case models.NAMESTAT:
 		c.state.PlayerData.Name = stringValue
-	case models.ATTACKSTAT:
-		c.state.PlayerData.Stats["atk"] = statValue
-	case models.DEFENSESTAT:
-		c.state.PlayerData.Stats["def"] = statValue
-	case models.SPEEDSTAT:
-		c.state.PlayerData.Stats["spd"] = statValue
-	case models.DEXTERITYSTAT:
-		c.state.PlayerData.Stats["dex"] = statValue
-	case models.VITALITYSTAT:
-		c.state.PlayerData.Stats["vit"] = statValue
-	case models.WISDOMSTAT:
-		c.state.PlayerData.Stats["wis"] = statValue
 	case models.FAMESTAT:
 		c.state.PlayerData.Fame = statValue
 	case models.CURRFAMESTAT:
@@ -1064,87 +1300,141 @@ func (c *Client) updateStat(statType int32, statValue int32, stringValue string)
 		c.state.PlayerData.GuildName = stringValue
 	case models.GUILDRANKSTAT:
 		c.state.PlayerData.GuildRank = statValue
-		/* 	case models.HEALTHPOTIONSTACKSTAT:
-		   		c.state.PlayerData.HPPots = statValue
-		   	case models.MAGICPOTIONSTACKSTAT:
-		   		c.state.PlayerData.MPPots = statValue
-		   	case models.HASBACKPACKSTAT:
-		   		c.state.PlayerData.HasBackpack = statValue == 1 */
+	case models.CONDITIONSTAT:
+		previous := c.state.PlayerData.Effects
+		c.state.PlayerData.Effects = previous.WithCondition(statValue)
+		c.emitEffectChanges(packet, c.state.ObjectID, previous, c.state.PlayerData.Effects)
+	case models.NEWCONSTAT:
+		previous := c.state.PlayerData.Effects
+		c.state.PlayerData.Effects = previous.WithNewCondition(statValue)
+		c.emitEffectChanges(packet, c.state.ObjectID, previous, c.state.PlayerData.Effects)
+	case models.HASBACKPACKSTAT:
+		if statValue == 1 {
+			c.state.PlayerData.BackpackSlots = TotalSlots - FirstBackpackSlot
+		} else {
+			c.state.PlayerData.BackpackSlots = 0
+		}
 	default:
 		// Handle inventory slots
 		statTypeEnum := models.StatType(statType)
 		if statTypeEnum >= models.INVENTORY0STAT && statTypeEnum <= models.INVENTORY11STAT {
-			slot := int(statTypeEnum - models.INVENTORY0STAT)
-			if slot >= 0 && slot < len(c.state.PlayerData.Inventory) {
-				c.state.PlayerData.Inventory[slot] = statValue
-			}
+			c.setInventorySlot(packet, int(statTypeEnum-models.INVENTORY0STAT), statValue)
 		} else if statTypeEnum >= models.BACKPACK0STAT && statTypeEnum <= models.BACKPACK7STAT {
-			slot := int(statTypeEnum - models.BACKPACK0STAT + 12) // Offset by 12 inventory slots
-			if slot >= 0 && slot < len(c.state.PlayerData.Inventory) {
-				c.state.PlayerData.Inventory[slot] = statValue
-			}
+			c.setInventorySlot(packet, int(statTypeEnum-models.BACKPACK0STAT+12), statValue) // Offset by 12 inventory slots
 		}
 	}
 }
 
-func (c *Client) handleNewObject(obj interface{}) {
-	// TODO: Implement object handling based on type
+// setInventorySlot stores an item in one of the player's slots and reports the change
+func (c *Client) setInventorySlot(packet packets.Packet, slot int, itemID int32) {
+	if slot < 0 || slot >= len(c.state.PlayerData.Inventory) {
+		return
+	}
+	old := c.state.PlayerData.Inventory[slot]
+	c.state.PlayerData.Inventory[slot] = itemID
+	if old != itemID {
+		c.emit(events.EventInventoryUpdate, packet, &events.InventoryEventData{
+			ObjectID: c.state.ObjectID,
+			SlotID:   int32(slot),
+			OldItem:  old,
+			NewItem:  itemID,
+		})
+	}
 }
 
-// GetState returns the current game state
+// GetState returns a copy of the game state from the latest snapshot
 func (c *Client) GetState() *GameState {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.state
+	snap := c.Snapshot()
+	player := snap.Player
+	pos := snap.Position
+	return &GameState{
+		ObjectID:   snap.ObjectID,
+		ClassType:  snap.ClassType,
+		WorldPos:   &pos,
+		PlayerData: &player,
+		LastUpdate: snap.Time,
+	}
 }
 
-// GetEnemy returns an enemy by ID
+// GetEnemy returns an enemy by ID from the latest snapshot
 func (c *Client) GetEnemy(id int32) *Enemy {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.enemies[id]
+	return c.Snapshot().Enemies[id]
 }
 
-// GetPlayer returns a player by ID
+// GetPlayer returns a player by ID from the latest snapshot
 func (c *Client) GetPlayer(id int32) *Player {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.players[id]
+	return c.Snapshot().Players[id]
 }
 
-// GetProjectile returns a projectile by ID
+// GetProjectile returns a projectile by ID from the latest snapshot
 func (c *Client) GetProjectile(id int32) *Projectile {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.projectiles[id]
+	return c.Snapshot().Projectiles[id]
 }
 
-// GetMap returns the current map
+// GetMap returns the current map from the latest snapshot
 func (c *Client) GetMap() *Map {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.currentMap
+	return c.Snapshot().Map
 }
 
-// GetPosition returns the client's current position
+// GetPosition returns a copy of the client's current position
 func (c *Client) GetPosition() *WorldPosData {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.state.WorldPos
+	return c.state.WorldPos.clone()
 }
 
-// SetPosition updates the client's position
+// SetPosition updates the client's position on the state goroutine
 func (c *Client) SetPosition(pos *WorldPosData) {
+	pos = pos.clone()
+	if !c.do(func() { c.setPosition(pos) }) {
+		c.logger.Warning("Client", "Dropping position update, state goroutine is busy")
+	}
+}
+
+// setPosition replaces the player's position. Only the state goroutine writes
+// the position, under c.mu so that GetPosition can read it.
+func (c *Client) setPosition(pos *WorldPosData) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.state.WorldPos = pos
 }
 
+// do queues a function to run on the state goroutine, reporting false when the
+// queue is full
+func (c *Client) do(action func()) bool {
+	select {
+	case c.actions <- action:
+		return true
+	default:
+		return false
+	}
+}
+
+// run runs a function on the state goroutine and waits until a snapshot with
+// its changes has been published. It reports false if the function could not
+// be queued or did not run in time, and must not be called on the state
+// goroutine.
+func (c *Client) run(action func()) bool {
+	done := make(chan struct{})
+	queued := c.do(func() {
+		action()
+		c.publishSnapshot()
+		close(done)
+	})
+	if !queued {
+		return false
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(runTimeout):
+		return false
+	}
+}
+
 // IsConnected returns whether the client is connected
 func (c *Client) IsConnected() bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.connected
+	return c.connected.Load()
 }
 
 // GetLogger returns the client's logger
@@ -1153,16 +1443,52 @@ func (c *Client) GetLogger() *logger.Logger {
 }
 
 // handlePackets processes incoming packets
-func (c *Client) handlePackets() {
+// receivedPacket is a decoded packet waiting for the state goroutine
+type receivedPacket struct {
+	id     int
+	packet packets.Packet
+}
+
+// handlePackets runs the state goroutine for a connection. It owns the world
+// state: every packet handler and movement step runs here, one at a time, and
+// other goroutines read the state through snapshots.
+func (c *Client) handlePackets(conn net.Conn, done chan struct{}) {
+	defer close(done)
 	defer c.Disconnect()
 
+	incoming := make(chan receivedPacket, incomingQueueSize)
+	go c.readPackets(conn, incoming)
+
+	c.publishSnapshot()
 	for {
-		if !c.connected {
+		select {
+		case received, ok := <-incoming:
+			if !ok {
+				return
+			}
+			if err := c.packetHandler.HandlePacket(received.id, received.packet); err != nil {
+				c.logger.Warning("Client", "Error handling packet: %v", err)
+				// Don't return on packet handling errors, continue processing other packets
+			}
+		case action := <-c.actions:
+			action()
+		}
+		c.publishSnapshot()
+	}
+}
+
+// readPackets reads and decodes packets from the connection until it fails,
+// then closes out
+func (c *Client) readPackets(conn net.Conn, out chan<- receivedPacket) {
+	defer close(out)
+
+	for {
+		if !c.connected.Load() {
 			return
 		}
 
 		// Set read deadline for each packet
-		if err := c.conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
+		if err := conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
 			c.logger.Warning("Client", "Failed to set read deadline: %v", err)
 			continue
 		}
@@ -1171,7 +1497,7 @@ func (c *Client) handlePackets() {
 		header := make([]byte, 5)
 		bytesRead := 0
 		for bytesRead < 5 {
-			n, err := c.conn.Read(header[bytesRead:])
+			n, err := conn.Read(header[bytesRead:])
 			if err != nil {
 				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 					continue
@@ -1203,7 +1529,7 @@ func (c *Client) handlePackets() {
 			}
 
 			chunk := make([]byte, chunkSize)
-			n, err := c.conn.Read(chunk)
+			n, err := conn.Read(chunk)
 			if err != nil {
 				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 					continue
@@ -1236,14 +1562,10 @@ func (c *Client) handlePackets() {
 		}
 
 		// Log received packet
-		c.logger.Debug("Client", "RECV [%s] Type: %d, Length: %d, Data: %+v",
+		c.logger.Debug("Client", "RECV [%d] Type: %d, Length: %d, Data: %+v",
 			interfaces.PacketType(packetId), packetId, packetLength, newPacket)
 
-		// Process the decrypted packet
-		if err := c.packetHandler.HandlePacket(int(packetId), newPacket); err != nil {
-			c.logger.Warning("Client", "Error handling packet: %v", err)
-			// Don't return on packet handling errors, continue processing other packets
-		}
+		out <- receivedPacket{id: int(packetId), packet: newPacket}
 	}
 }
 
@@ -1252,7 +1574,7 @@ func (c *Client) reconnect() {
 	defer c.mu.Unlock()
 
 	// If already disconnected, no need to proceed
-	if !c.connected {
+	if !c.connected.Load() {
 		return
 	}
 
@@ -1260,17 +1582,17 @@ func (c *Client) reconnect() {
 	c.logger.Info("Client", "Initiating reconnection sequence...")
 
 	// Properly close existing connection
+	c.connected.Store(false)
+	c.sendMu.Lock()
 	if c.conn != nil {
 		c.conn.Close()
 		c.conn = nil
 	}
-	c.connected = false
+	c.sendMu.Unlock()
 
 	// Reset game state
-	c.state = &GameState{}
-	c.enemies = make(map[int32]*Enemy)
-	c.players = make(map[int32]*Player)
-	c.projectiles = make(map[int32]*Projectile)
+	c.state = newGameState()
+	c.resetTracking()
 
 	// Check if we should attempt reconnection
 	if c.reconnectAttempts >= c.maxReconnectAttempts {
@@ -1278,54 +1600,89 @@ func (c *Client) reconnect() {
 		return
 	}
 
+	// A manual reconnection request starts a fresh series of attempts
+	if c.accountInfo != nil && c.accountInfo.Reconnect {
+		c.accountInfo.Reconnect = false
+		c.reconnectAttempts = 0
+	}
+
 	c.reconnectAttempts++
 	attemptNum := c.reconnectAttempts
+	data := c.connectionEventData(attemptNum)
 
 	// Start reconnection attempt in a goroutine
 	go func() {
+		c.emit(events.EventReconnect, nil, data)
+
 		// Wait for the configured delay
 		c.logger.Info("Client", "Waiting %v before reconnection attempt %d/%d...",
 			c.reconnectDelay, attemptNum, c.maxReconnectAttempts)
 		time.Sleep(c.reconnectDelay)
 
-		// Check if this was a manual reconnection request
-		if c.accountInfo != nil && c.accountInfo.Reconnect {
-			c.accountInfo.Reconnect = false // Reset the flag
-			c.reconnectAttempts = 0         // Reset attempts for manual reconnection
-		}
-
-		// Attempt to reconnect
+		// Attempt to reconnect, Connect resets the counter on success
 		if err := c.Connect(); err != nil {
 			c.logger.Error("Client", "Reconnection attempt %d failed: %v", attemptNum, err)
 		} else {
 			c.logger.Info("Client", "Successfully reconnected on attempt %d", attemptNum)
-			c.reconnectAttempts = 0 // Reset counter on successful connection
 		}
 	}()
 }
 
-// SwitchServer changes the client's server and attempts to connect to it
+// SwitchServer changes the client's server and attempts to connect to it. If the
+// server is unknown, the client's selection strategy picks a replacement.
 func (c *Client) SwitchServer(serverName string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	server := models.GetServer(serverName)
-	if server == nil {
-		return fmt.Errorf("unknown server: %s", serverName)
+	server, ok := models.LookupServer(serverName)
+	if !ok {
+		server = c.selectServer()
+		c.logger.Warning("Client", "Unknown server %s, using %s instead", serverName, server.Name)
 	}
+	return c.switchTo(server)
+}
 
+// switchTo moves the client to a server and connects to it
+func (c *Client) switchTo(server *models.Server) error {
 	// Update server info
+	c.mu.Lock()
+	previous := ""
+	if c.server != nil {
+		previous = c.server.Name
+	}
 	c.server = server
+	if c.accountInfo != nil {
+		c.accountInfo.LastServer = server.Name
+	}
+	data := c.connectionEventData(0)
+	data.PreviousServer = previous
+	c.mu.Unlock()
+
+	c.emit(events.EventServerSwitch, nil, data)
 
 	// Disconnect from current server if connected
-	if c.connected {
-		c.Disconnect()
-	}
+	c.Disconnect()
 
 	// Connect to new server
 	return c.Connect()
 }
 
+// selectServer picks a server from the cached list using the client's selection strategy
+func (c *Client) selectServer() *models.Server {
+	if c.selector == nil || len(models.CachedServers) == 0 {
+		return models.DefaultServer
+	}
+
+	accountKey := ""
+	if c.accountInfo != nil {
+		accountKey = c.accountInfo.GUID
+	}
+
+	server, err := c.selector.Select(models.CachedServers, accountKey)
+	if err != nil {
+		c.logger.Warning("Client", "Server selection failed: %v. Using default server.", err)
+		return models.DefaultServer
+	}
+	return server
+}
+
 // GetCurrentServer returns the current server configuration
 func (c *Client) GetCurrentServer() *models.Server {
 	c.mu.Lock()
@@ -1335,7 +1692,7 @@ func (c *Client) GetCurrentServer() *models.Server {
 
 // moveTo updates the client's position for smooth movement
 func (c *Client) moveTo(target *WorldPosData) bool {
-	if target == nil {
+	if target == nil || c.state.WorldPos == nil {
 		return false
 	}
 
@@ -1343,7 +1700,10 @@ func (c *Client) moveTo(target *WorldPosData) bool {
 	elapsed := now.Sub(c.lastMoveTime).Seconds()
 	c.lastMoveTime = now
 
-	step := float32(elapsed) * c.moveSpeed
+	step := float32(elapsed) * c.MoveSpeed()
+	if step <= 0 {
+		return false
+	}
 
 	// Calculate distance to target
 	dx := target.X - c.state.WorldPos.X
@@ -1399,96 +1759,59 @@ func (c *Client) GetNextPosition() *WorldPosData {
 	return c.nextPositions[0]
 }
 
-// Update handles client updates including movement
+// Update schedules a movement step on the state goroutine. Steps are not
+// queued up while one is still pending.
 func (c *Client) Update() {
+	if c.updatePending.Swap(true) {
+		return
+	}
+	if !c.do(c.update) {
+		c.updatePending.Store(false)
+	}
+}
+
+// update moves the player along its path, on the state goroutine
+func (c *Client) update() {
+	c.updatePending.Store(false)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if len(c.nextPositions) > 0 {
 		if moved := c.moveTo(c.nextPositions[0]); moved {
-			// Create a new Move packet
-			movePacket := client.NewMove()
-			movePacket.TickID = int32(time.Now().UnixNano() / int64(time.Millisecond))
-			movePacket.Time = int32(c.state.LastFrameTime)
-
-			// Create a location record with the current position
-			record := dataobjects.NewLocationRecord()
-			record.Time = int32(c.state.LastFrameTime)
-			record.Position = dataobjects.NewLocationWithCoords(float64(c.state.WorldPos.X), float64(c.state.WorldPos.Y))
-
-			// Add the record to the packet
-			movePacket.Records = append(movePacket.Records, record)
-
-			// Encode and send the packet
-			if c.connected {
-				// Create a wrapper that implements the packets.Packet interface
-				wrapper := &packetWrapper{
-					id:         int32(interfaces.Move),
-					writeFunc:  movePacket.Write,
-					hasNulls:   func() bool { return false },
-					packetType: interfaces.Move,
-				}
-
-				// Encode the packet
-				data, err := packets.EncodePacket(wrapper)
-				if err != nil {
-					c.logger.Error("Client", "Failed to encode Move packet: %v", err)
-					return
-				}
-
-				// Log outgoing packet for debugging
-				c.logger.Debug("Client", "Sending Move packet, data: % x", data)
-
-				// Encrypt if RC4 is initialized
-				if c.rc4 != nil {
-					c.rc4.Encrypt(data)
-					c.logger.Debug("Client", "Encrypted data: % x", data)
-				}
-
-				// Send the packet
-				if _, err := c.conn.Write(data); err != nil {
-					c.logger.Error("Client", "Failed to send Move packet: %v", err)
-				}
-			}
+			// Record the position, it is reported with the next tick's Move
+			c.moveRecords.AddRecord(int64(c.clock.ClientTime()), c.state.WorldPos.X, c.state.WorldPos.Y)
 		}
 	}
 }
 
-// packetWrapper is a helper struct to adapt client/server packets to the packets.Packet interface
-type packetWrapper struct {
-	id         int32
-	writeFunc  func(w *packets.PacketWriter) error
-	hasNulls   func() bool
-	packetType interfaces.PacketType
-}
-
-func (p *packetWrapper) ID() int32 {
-	return p.id
-}
+// takeMoveRecords returns the positions recorded since the last tick followed
+// by the current position, and starts a new batch
+func (c *Client) takeMoveRecords(now int32) []*dataobjects.LocationRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-func (p *packetWrapper) Write(w interfaces.Writer) error {
-	if pw, ok := w.(*packets.PacketWriter); ok {
-		return p.writeFunc(pw)
+	records := make([]*dataobjects.LocationRecord, 0, len(c.moveRecords.Records)+1)
+	for _, rec := range c.moveRecords.Records {
+		record := dataobjects.NewLocationRecord()
+		record.Time = int32(rec.Time)
+		record.Position = dataobjects.NewLocationWithCoords(float64(rec.X), float64(rec.Y))
+		records = append(records, record)
 	}
-	return fmt.Errorf("expected *packets.PacketWriter, got %T", w)
-}
-
-func (p *packetWrapper) Read(r interfaces.Reader) error {
-	return fmt.Errorf("read not implemented for wrapper")
-}
-
-func (p *packetWrapper) String() string {
-	return fmt.Sprintf("PacketWrapper(ID=%d, Type=%v)", p.id, p.packetType)
-}
 
-func (p *packetWrapper) HasNulls() bool {
-	return p.hasNulls()
-}
+	if c.state.WorldPos != nil {
+		record := dataobjects.NewLocationRecord()
+		record.Time = now
+		record.Position = dataobjects.NewLocationWithCoords(float64(c.state.WorldPos.X), float64(c.state.WorldPos.Y))
+		records = append(records, record)
+	}
 
-func (p *packetWrapper) Structure() string {
-	return fmt.Sprintf("PacketWrapper(ID=%d, Type=%v)", p.id, p.packetType)
+	c.moveRecords.Clear(int64(now))
+	return records
 }
 
-func (p *packetWrapper) Type() interfaces.PacketType {
-	return p.packetType
+// resetMoveRecords drops recorded positions; recording resumes at the next tick
+func (c *Client) resetMoveRecords() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.moveRecords.Clear(-1)
 }
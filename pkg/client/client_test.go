@@ -0,0 +1,102 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/interfaces"
+	"gorelay/pkg/packets/server"
+)
+
+func TestNewTickAppliesStatusesWithoutPosition(t *testing.T) {
+	const playerID, enemyID = 1, 2
+	tests := []struct {
+		name     string
+		position *WorldPosData
+		tick     *server.NewTick
+		wantMove bool
+		wantPos  WorldPosData
+	}{
+		{
+			name: "no position",
+			tick: &server.NewTick{TickId: 1, Statuses: []*dataobjects.Status{
+				{ObjectID: playerID, Data: []*dataobjects.StatData{{ID: dataobjects.StatsType(models.HPSTAT), IntValue: 42}}},
+				{ObjectID: enemyID, Data: []*dataobjects.StatData{{ID: dataobjects.StatsType(models.HPSTAT), IntValue: 42}}},
+			}},
+		},
+		{
+			name:     "position from the tick",
+			tick:     statusTick(1, playerID, 3, 42),
+			wantMove: true,
+			wantPos:  WorldPosData{X: 3, Y: 3},
+		},
+		{
+			name:     "known position",
+			position: &WorldPosData{X: 5, Y: 5},
+			tick:     statusTick(1, enemyID, 5, 42),
+			wantMove: true,
+			wantPos:  WorldPosData{X: 5, Y: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			if !c.run(func() {
+				c.state.ObjectID = playerID
+				c.state.WorldPos = tt.position
+				c.enemies[enemyID] = &Enemy{ObjectID: enemyID, HP: 100, Position: &WorldPosData{X: 1, Y: 1}, DamageTaken: make(map[int32]int32)}
+				if err := c.packetHandler.HandlePacket(int(interfaces.NewTick), tt.tick); err != nil {
+					t.Errorf("NewTick failed: %v", err)
+				}
+			}) {
+				t.Fatal("state goroutine did not handle the tick")
+			}
+
+			snap := c.Snapshot()
+			for _, status := range tt.tick.Statuses {
+				if status.ObjectID == playerID && snap.Player.HP != 42 {
+					t.Fatalf("player hp %d, want 42", snap.Player.HP)
+				}
+				if status.ObjectID == enemyID && snap.Enemies[enemyID].HP != 42 {
+					t.Fatalf("enemy hp %d, want 42", snap.Enemies[enemyID].HP)
+				}
+			}
+			if snap.Position != tt.wantPos {
+				t.Fatalf("position %+v, want %+v", snap.Position, tt.wantPos)
+			}
+
+			// Sending a Move starts a new batch of move records
+			var moved bool
+			if !c.run(func() { moved = c.moveRecords.LastClearTime >= 0 }) {
+				t.Fatal("state goroutine did not run the check")
+			}
+			if moved != tt.wantMove {
+				t.Fatalf("sent Move: %v, want %v", moved, tt.wantMove)
+			}
+		})
+	}
+}
+
+func TestStateWithoutPositionDoesNotMove(t *testing.T) {
+	c := startTestClient(t)
+	c.AddPath([]*WorldPosData{{X: 5, Y: 5}})
+	if !c.run(func() {
+		c.state.WorldPos = nil
+		c.moveRecords.Clear(0)
+		time.Sleep(10 * time.Millisecond)
+		c.update()
+		if c.state.WorldPos != nil {
+			t.Errorf("moved to %+v without a position", c.state.WorldPos)
+		}
+		if records := c.takeMoveRecords(c.clock.ClientTime()); len(records) != 0 {
+			t.Errorf("recorded %d positions without a position", len(records))
+		}
+	}) {
+		t.Fatal("state goroutine did not run the update")
+	}
+	if !c.HasNextPosition() {
+		t.Fatal("the path was dropped while waiting for a position")
+	}
+}
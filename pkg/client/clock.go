@@ -0,0 +1,146 @@
+package client
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Smoothing factors for the clock estimates, as fractions of each new sample
+const (
+	clockOffsetGain = 1.0 / 8
+	clockRTTGain    = 1.0 / 8
+	clockJitterGain = 1.0 / 16
+)
+
+// ClockStats describes the connection latency and server clock estimate
+type ClockStats struct {
+	RTT     time.Duration // last round trip time reported by the server
+	AvgRTT  time.Duration // smoothed round trip time
+	MinRTT  time.Duration
+	MaxRTT  time.Duration
+	Jitter  time.Duration // smoothed variation between consecutive round trips
+	Offset  time.Duration // estimated server time minus client time
+	Synced  bool          // whether a server tick has been seen since connecting
+	Samples int
+}
+
+// Clock is the client's time source. Client time counts milliseconds since the
+// connection was made and is used for every outgoing packet timestamp, as the
+// server expects. The offset to server time is estimated from the
+// ServerRealTimeMs and round trip time carried by NewTick packets, which the
+// server measures from our Pong replies, and is reported in the stats.
+type Clock struct {
+	mu sync.Mutex
+
+	start    time.Time
+	lastTick int32
+
+	synced bool
+	offset float64 // ms, server time minus client time
+
+	samples int
+	lastRTT float64
+	avgRTT  float64
+	minRTT  float64
+	maxRTT  float64
+	jitter  float64
+}
+
+// newClock creates a clock starting now
+func newClock() *Clock {
+	cl := &Clock{}
+	cl.Reset()
+	return cl
+}
+
+// Clock returns the client's clock
+func (c *Client) Clock() *Clock {
+	return c.clock
+}
+
+// Reset restarts client time at zero and forgets the server estimates, called
+// for every new connection
+func (cl *Clock) Reset() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.start = time.Now()
+	cl.lastTick = 0
+	cl.synced = false
+	cl.offset = 0
+	cl.samples = 0
+	cl.lastRTT, cl.avgRTT, cl.minRTT, cl.maxRTT, cl.jitter = 0, 0, 0, 0, 0
+}
+
+// ClientTime returns the milliseconds elapsed since the client connected
+func (cl *Clock) ClientTime() int32 {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.clientTime()
+}
+
+// clientTime returns the client time, cl.mu must be held
+func (cl *Clock) clientTime() int32 {
+	return int32(time.Since(cl.start).Milliseconds())
+}
+
+// LastTick returns the id of the last server tick
+func (cl *Clock) LastTick() int32 {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.lastTick
+}
+
+// Stats returns the current latency statistics
+func (cl *Clock) Stats() ClockStats {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	ms := func(v float64) time.Duration {
+		return time.Duration(v * float64(time.Millisecond))
+	}
+	return ClockStats{
+		RTT:     ms(cl.lastRTT),
+		AvgRTT:  ms(cl.avgRTT),
+		MinRTT:  ms(cl.minRTT),
+		MaxRTT:  ms(cl.maxRTT),
+		Jitter:  ms(cl.jitter),
+		Offset:  ms(cl.offset),
+		Synced:  cl.synced,
+		Samples: cl.samples,
+	}
+}
+
+// sync updates the estimates from a NewTick's server time and round trip time.
+// Ping is left out on purpose: it carries only a serial and no server time, and
+// the server already times our Pong replies into the NewTick's round trip.
+func (cl *Clock) sync(tickID, serverTime int32, rttMS uint16) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.lastTick = tickID
+
+	// The tick left the server about half a round trip ago
+	rtt := float64(rttMS)
+	sample := float64(serverTime) + rtt/2 - float64(cl.clientTime())
+	if !cl.synced {
+		cl.offset = sample
+		cl.synced = true
+	} else {
+		cl.offset += (sample - cl.offset) * clockOffsetGain
+	}
+
+	// The server reports 0 until it has timed a Pong
+	if rttMS == 0 {
+		return
+	}
+	if cl.samples == 0 {
+		cl.avgRTT, cl.minRTT, cl.maxRTT = rtt, rtt, rtt
+	} else {
+		cl.avgRTT += (rtt - cl.avgRTT) * clockRTTGain
+		cl.jitter += (math.Abs(rtt-cl.lastRTT) - cl.jitter) * clockJitterGain
+		cl.minRTT = math.Min(cl.minRTT, rtt)
+		cl.maxRTT = math.Max(cl.maxRTT, rtt)
+	}
+	cl.lastRTT = rtt
+	cl.samples++
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestClockRoundTrips(t *testing.T) {
+	ms := func(v float64) time.Duration { return time.Duration(v * float64(time.Millisecond)) }
+	tests := []struct {
+		name string
+		rtts []uint16
+		want ClockStats
+	}{
+		{name: "no pong timed yet", rtts: []uint16{0, 0}, want: ClockStats{Synced: true}},
+		{name: "first sample", rtts: []uint16{0, 100}, want: ClockStats{
+			RTT: ms(100), AvgRTT: ms(100), MinRTT: ms(100), MaxRTT: ms(100), Synced: true, Samples: 1,
+		}},
+		{name: "smoothed", rtts: []uint16{100, 60, 140}, want: ClockStats{
+			RTT:     ms(140),
+			AvgRTT:  ms(100 + (60-100)*clockRTTGain + (140-95)*clockRTTGain),
+			MinRTT:  ms(60),
+			MaxRTT:  ms(140),
+			Jitter:  ms(40*clockJitterGain + (80-40*clockJitterGain)*clockJitterGain),
+			Synced:  true,
+			Samples: 3,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := newClock()
+			for i, rtt := range tt.rtts {
+				cl.sync(int32(i+1), 0, rtt)
+			}
+			got := cl.Stats()
+			got.Offset = 0
+			if got != tt.want {
+				t.Fatalf("Stats() = %+v, want %+v", got, tt.want)
+			}
+			if tick := cl.LastTick(); tick != int32(len(tt.rtts)) {
+				t.Fatalf("LastTick() = %d, want %d", tick, len(tt.rtts))
+			}
+		})
+	}
+}
+
+func TestClockOffset(t *testing.T) {
+	cl := newClock()
+	offset := func() float64 {
+		return float64(cl.Stats().Offset) / float64(time.Millisecond)
+	}
+	near := func(got, want float64) bool {
+		// Client time moves on while the test runs
+		return math.Abs(got-want) < 20
+	}
+
+	// The first tick sets the offset, half a round trip after the server time
+	cl.sync(1, 10000, 100)
+	if got := offset(); !near(got, 10050) {
+		t.Fatalf("offset after the first tick = %v, want about 10050", got)
+	}
+
+	// Later ticks move it by a fraction of the difference
+	cl.sync(2, 10400, 100)
+	if got := offset(); !near(got, 10050+400*clockOffsetGain) {
+		t.Fatalf("offset after the second tick = %v, want about %v", got, 10050+400*clockOffsetGain)
+	}
+
+	cl.Reset()
+	if stats := cl.Stats(); stats != (ClockStats{}) || cl.LastTick() != 0 {
+		t.Fatalf("Reset kept %+v at tick %d", stats, cl.LastTick())
+	}
+	if now := cl.ClientTime(); now < 0 || now > 20 {
+		t.Fatalf("ClientTime() = %d right after Reset, want about 0", now)
+	}
+}
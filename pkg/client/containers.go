@@ -0,0 +1,178 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/xmldata"
+)
+
+// Container layout and reach
+const (
+	ContainerSlots = 8
+
+	// LootRange is how close the player must be to a container to take items from it
+	LootRange float32 = 1.0
+)
+
+// BagItem is an item lying in a container slot
+type BagItem struct {
+	Slot     int32
+	ItemType int32
+	Name     string
+}
+
+// Bag describes a container near the player
+type Bag struct {
+	ObjectID   int32
+	ObjectType int32
+	BagType    int32
+	Name       string
+	Position   *WorldPosData
+	Distance   float32
+	Items      []BagItem
+}
+
+// isContainer reports whether an object definition is a container
+func isContainer(obj *xmldata.GameObject) bool {
+	return obj.Class == "Container" || obj.BagType > 0
+}
+
+// trackContainer starts tracking a container that came into view
+func (c *Client) trackContainer(packet packets.Packet, entity *dataobjects.Entity, obj *xmldata.GameObject, pos *WorldPosData) {
+	container := &models.Container{
+		Items:   make([]int32, ContainerSlots),
+		Slots:   ContainerSlots,
+		BagType: int32(obj.BagType),
+	}
+	container.ObjectID = entity.Status.ObjectID
+	container.ObjectType = int32(entity.ObjectType)
+	container.Position.X = pos.X
+	container.Position.Y = pos.Y
+	for i := range container.Items {
+		container.Items[i] = EmptyItem
+	}
+	applyContainerStats(container, entity.Status.Data)
+
+	c.containers[container.ObjectID] = container
+	c.syncVaultChest(container)
+	c.emit(events.EventItemDrop, packet, containerEventData(container))
+}
+
+// applyContainerStats fills container slots from stat data
+func applyContainerStats(container *models.Container, stats []*dataobjects.StatData) {
+	for _, stat := range stats {
+		statType := models.StatType(stat.ID)
+		if statType >= models.INVENTORY0STAT && statType < models.INVENTORY0STAT+ContainerSlots {
+			container.Items[statType-models.INVENTORY0STAT] = int32(stat.IntValue)
+		}
+	}
+	container.UpdatedAt = time.Now()
+}
+
+// GetContainer returns a tracked container by object ID from the latest snapshot
+func (c *Client) GetContainer(id int32) *models.Container {
+	return c.Snapshot().Containers[id]
+}
+
+// NearbyBags returns the non-empty containers within radius tiles of the
+// player, closest first
+func (c *Client) NearbyBags(radius float32) []Bag {
+	snap := c.Snapshot()
+	pos := &snap.Position
+
+	bags := make([]Bag, 0)
+	for _, container := range snap.Containers {
+		containerPos := &WorldPosData{X: container.Position.X, Y: container.Position.Y}
+		distance := containerPos.DistanceTo(pos)
+		if distance > radius {
+			continue
+		}
+
+		bag := Bag{
+			ObjectID:   container.ObjectID,
+			ObjectType: container.ObjectType,
+			BagType:    container.BagType,
+			Name:       xmldata.GetObjectName(int(container.ObjectType)),
+			Position:   containerPos,
+			Distance:   distance,
+		}
+		for slot, itemType := range container.Items {
+			if itemType == EmptyItem {
+				continue
+			}
+			bag.Items = append(bag.Items, BagItem{
+				Slot:     int32(slot),
+				ItemType: itemType,
+				Name:     xmldata.GetObjectName(int(itemType)),
+			})
+		}
+		if len(bag.Items) > 0 {
+			bags = append(bags, bag)
+		}
+	}
+
+	sort.Slice(bags, func(i, j int) bool {
+		return bags[i].Distance < bags[j].Distance
+	})
+	return bags
+}
+
+// Pull moves the item in a container slot into the first free inventory slot
+func (inv *Inventory) Pull(containerID, slot int32) error {
+	snap := inv.client.Snapshot()
+	container, ok := snap.Containers[containerID]
+	if !ok {
+		return fmt.Errorf("container %d is not in view", containerID)
+	}
+	if slot < 0 || int(slot) >= len(container.Items) || container.Items[slot] == EmptyItem {
+		return fmt.Errorf("container %d slot %d is empty", containerID, slot)
+	}
+
+	containerPos := &WorldPosData{X: container.Position.X, Y: container.Position.Y}
+	if containerPos.DistanceTo(&snap.Position) > LootRange {
+		return fmt.Errorf("container %d is out of reach", containerID)
+	}
+
+	itemType := container.Items[slot]
+	if err := inv.LootFrom(containerID, slot, itemType); err != nil {
+		return err
+	}
+	inv.client.run(func() {
+		if container, ok := inv.client.containers[containerID]; ok {
+			container.Items[slot] = EmptyItem
+		}
+	})
+	return nil
+}
+
+// PullNamed takes the first item with the given name from any container in reach
+func (inv *Inventory) PullNamed(name string) error {
+	for _, bag := range inv.client.NearbyBags(LootRange) {
+		for _, item := range bag.Items {
+			if strings.EqualFold(item.Name, name) {
+				return inv.Pull(bag.ObjectID, item.Slot)
+			}
+		}
+	}
+	return fmt.Errorf("no %s within reach", name)
+}
+
+// containerEventData snapshots a container for event payloads
+func containerEventData(container *models.Container) *events.ContainerEventData {
+	items := make([]int32, len(container.Items))
+	copy(items, container.Items)
+	return &events.ContainerEventData{
+		ObjectID:   container.ObjectID,
+		ObjectType: container.ObjectType,
+		BagType:    container.BagType,
+		Items:      items,
+		Position:   events.Position{X: container.Position.X, Y: container.Position.Y},
+	}
+}
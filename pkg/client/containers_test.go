@@ -0,0 +1,143 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+	"gorelay/pkg/xmldata"
+)
+
+const testBag = 0x500
+
+// slotStats builds stat data filling container slots
+func slotStats(items map[int]int32) []*dataobjects.StatData {
+	var stats []*dataobjects.StatData
+	for slot, itemType := range items {
+		stats = append(stats, &dataobjects.StatData{
+			ID:       dataobjects.StatsType(models.INVENTORY0STAT + models.StatType(slot)),
+			IntValue: int(itemType),
+		})
+	}
+	return stats
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	useTestObjects(t, xmldata.GameObject{Type: "0x500", ID: "Brown Bag", Class: "Container", BagType: 1})
+	c := startTestClient(t)
+	got := recordEvents(c, events.EventItemDrop)
+	update := &server.Update{}
+
+	if !c.run(func() {
+		c.handleNewObject(update, &dataobjects.Entity{
+			ObjectType: testBag,
+			Status: &dataobjects.Status{
+				ObjectID: 30,
+				Position: &dataobjects.Location{X: 2, Y: 3},
+				// Slot 8 is past the end of a bag and must be ignored
+				Data: slotStats(map[int]int32{0: testStaff, 3: testRing, 8: testPotion}),
+			},
+		})
+	}) {
+		t.Fatal("state goroutine did not track the bag")
+	}
+	bag := c.GetContainer(30)
+	if bag == nil {
+		t.Fatal("bag was not tracked")
+	}
+	want := []int32{testStaff, EmptyItem, EmptyItem, testRing, EmptyItem, EmptyItem, EmptyItem, EmptyItem}
+	for slot := range want {
+		if bag.Items[slot] != want[slot] {
+			t.Fatalf("bag holds %v, want %v", bag.Items, want)
+		}
+	}
+	if len(*got) != 1 {
+		t.Fatalf("got %d item drop events, want 1", len(*got))
+	}
+	if data := (*got)[0].Data.(*events.ContainerEventData); data.BagType != 1 || data.Position.X != 2 || data.Items[3] != testRing {
+		t.Fatalf("item drop event %+v does not describe the bag", data)
+	}
+
+	if !c.run(func() {
+		c.handleObjectStatus(nil, &dataobjects.Status{ObjectID: 30, Data: slotStats(map[int]int32{0: EmptyItem})})
+	}) {
+		t.Fatal("state goroutine did not update the bag")
+	}
+	if bag := c.GetContainer(30); bag.Items[0] != EmptyItem || bag.Items[3] != testRing {
+		t.Fatalf("bag holds %v after a slot was emptied", bag.Items)
+	}
+
+	if !c.run(func() { c.handleDroppedObject(update, 30) }) {
+		t.Fatal("state goroutine did not drop the bag")
+	}
+	if c.GetContainer(30) != nil {
+		t.Fatal("bag is still tracked after leaving view")
+	}
+}
+
+// addBag tracks a container at x,0 holding items
+func addBag(c *Client, id int32, x float32, items ...int32) {
+	container := &models.Container{Items: make([]int32, ContainerSlots), Slots: ContainerSlots}
+	container.ObjectID = id
+	container.Position.X = x
+	for i := range container.Items {
+		container.Items[i] = EmptyItem
+	}
+	copy(container.Items, items)
+	c.containers[id] = container
+}
+
+func TestNearbyBags(t *testing.T) {
+	c := startTestClient(t)
+	if !c.run(func() {
+		c.setPosition(&WorldPosData{X: 0, Y: 0})
+		addBag(c, 1, 4, EmptyItem, testRing)
+		addBag(c, 2, 1, testStaff)
+		addBag(c, 3, 2)
+		addBag(c, 4, 9, testStaff)
+	}) {
+		t.Fatal("state goroutine did not run the setup")
+	}
+
+	bags := c.NearbyBags(5)
+	if len(bags) != 2 || bags[0].ObjectID != 2 || bags[1].ObjectID != 1 {
+		t.Fatalf("NearbyBags returned %+v, want bags 2 and 1 in that order", bags)
+	}
+	if items := bags[1].Items; len(items) != 1 || items[0].Slot != 1 || items[0].ItemType != testRing {
+		t.Fatalf("bag 1 lists %+v, want only the ring in slot 1", items)
+	}
+}
+
+func TestPullChecksContainer(t *testing.T) {
+	c := startTestClient(t)
+	if !c.run(func() {
+		c.setPosition(&WorldPosData{X: 0, Y: 0})
+		addBag(c, 1, 0.5, testStaff)
+		addBag(c, 2, 3, testStaff)
+	}) {
+		t.Fatal("state goroutine did not run the setup")
+	}
+
+	tests := []struct {
+		name      string
+		container int32
+		slot      int32
+		want      string
+	}{
+		{name: "not in view", container: 9, want: "not in view"},
+		{name: "empty slot", container: 1, slot: 2, want: "is empty"},
+		{name: "slot out of range", container: 1, slot: ContainerSlots, want: "is empty"},
+		{name: "out of reach", container: 2, want: "out of reach"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Inventory().Pull(tt.container, tt.slot)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Pull error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
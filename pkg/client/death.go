@@ -0,0 +1,231 @@
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorelay/pkg/account"
+	"gorelay/pkg/config"
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/server"
+)
+
+// maxDeathHistory limits how many deaths are remembered per account
+const maxDeathHistory = 50
+
+// DeathRecord describes a single character death seen by the client
+type DeathRecord struct {
+	Time      time.Time
+	AccountID string
+	CharID    int32
+	CharName  string
+	Level     int32
+	KilledBy  string
+	Stats     map[string]int64
+	RawStats  string
+}
+
+// GetDeathHistory returns the deaths recorded for this client's account, oldest first
+func (c *Client) GetDeathHistory() []*DeathRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	history := make([]*DeathRecord, len(c.deaths))
+	copy(history, c.deaths)
+	return history
+}
+
+// handleDeath records a death, notifies listeners and starts character recovery
+func (c *Client) handleDeath(death *server.Death) error {
+	// Ignore deaths for characters other than the one we are playing
+	if c.accountInfo != nil && c.accountInfo.CharInfo != nil && c.accountInfo.CharInfo.CharID > 0 &&
+		int32(death.CharId) != c.accountInfo.CharInfo.CharID {
+		c.logger.Debug("Client", "Ignoring death of character %d (playing %d)", death.CharId, c.accountInfo.CharInfo.CharID)
+		return nil
+	}
+
+	record := &DeathRecord{
+		Time:      time.Now(),
+		AccountID: death.AccountId,
+		CharID:    int32(death.CharId),
+		KilledBy:  death.KilledBy,
+		Stats:     parseDeathStats(death.Stats),
+		RawStats:  death.Stats,
+	}
+	if c.state != nil && c.state.PlayerData != nil {
+		record.CharName = c.state.PlayerData.Name
+		record.Level = c.state.PlayerData.Level
+	}
+
+	c.mu.Lock()
+	c.deaths = append(c.deaths, record)
+	if len(c.deaths) > maxDeathHistory {
+		c.deaths = c.deaths[len(c.deaths)-maxDeathHistory:]
+	}
+	c.mu.Unlock()
+
+	c.logger.Warning("Client", "Character %d (%s, level %d) was killed by %s",
+		record.CharID, record.CharName, record.Level, record.KilledBy)
+
+	c.emit(events.EventDeath, death, &events.DeathEventData{
+		AccountID: record.AccountID,
+		CharID:    record.CharID,
+		CharName:  record.CharName,
+		KilledBy:  record.KilledBy,
+		Stats:     record.Stats,
+	})
+
+	go c.recoverFromDeath(record.CharID)
+	return nil
+}
+
+// charSelection is the character to play after the next connect. It is chosen
+// off the state goroutine and applied by the MapInfo handler.
+type charSelection struct {
+	charID int32
+	chars  *account.Chars
+}
+
+// recoverFromDeath refreshes the character list and reconnects according to
+// the death policy. It runs on its own goroutine, as refreshing the list is a
+// blocking request and the dead character's connection has to end first. The
+// account is only changed by the state goroutine of the new connection.
+func (c *Client) recoverFromDeath(deadCharID int32) {
+	c.Disconnect()
+	c.waitForLoop()
+
+	policy := c.config.Death.Policy
+	if policy == config.DeathPolicyNone || c.accountInfo == nil {
+		c.logger.Info("Client", "Death recovery disabled, staying disconnected")
+		return
+	}
+
+	chars, err := c.accountInfo.FetchChars()
+	if err != nil {
+		c.logger.Error("Client", "Failed to refresh character list after death: %v", err)
+	}
+	selection, ok := selectAfterDeath(policy, chars, deadCharID)
+	if !ok {
+		c.logger.Warning("Client", "No character to play under death policy %q, staying disconnected", policy)
+		return
+	}
+	if selection.charID != 0 {
+		c.logger.Info("Client", "Reconnecting with existing character %d", selection.charID)
+	} else {
+		c.logger.Info("Client", "Reconnecting with a new character")
+	}
+
+	c.mu.Lock()
+	c.nextChar = selection
+	c.mu.Unlock()
+
+	time.Sleep(c.reconnectDelay)
+	if err := c.Connect(); err != nil {
+		c.logger.Error("Client", "Failed to reconnect after death: %v", err)
+	}
+}
+
+// selectAfterDeath picks the character to play next under a death policy. A
+// selection without a character ID makes the MapInfo handler create one. It
+// reports false if the policy leaves nothing to play.
+func selectAfterDeath(policy string, chars *account.Chars, deadCharID int32) (*charSelection, bool) {
+	// The list may still hold the dead character, never pick it again
+	charID := survivingChar(chars, deadCharID)
+	switch policy {
+	case config.DeathPolicyExisting:
+		if charID == 0 {
+			return nil, false
+		}
+		return &charSelection{charID: charID, chars: chars}, true
+	case config.DeathPolicyNew:
+		return &charSelection{}, true
+	case config.DeathPolicyAny:
+		if charID == 0 {
+			return &charSelection{}, true
+		}
+		return &charSelection{charID: charID, chars: chars}, true
+	default:
+		return nil, false
+	}
+}
+
+// applyCharSelection moves a character picked after a death into the account.
+// It must run on the state goroutine.
+func (c *Client) applyCharSelection() {
+	c.mu.Lock()
+	selection := c.nextChar
+	c.nextChar = nil
+	c.mu.Unlock()
+	if selection == nil || c.accountInfo == nil {
+		return
+	}
+
+	if c.accountInfo.CharInfo == nil {
+		c.accountInfo.CharInfo = &account.CharInfo{}
+	}
+	c.accountInfo.CharInfo.CharID = selection.charID
+	c.accountInfo.Chars = selection.chars
+	if selection.chars != nil {
+		c.accountInfo.CharInfo.NextCharID = int32(selection.chars.NextCharId)
+		c.accountInfo.CharInfo.MaxNumChars = int32(selection.chars.MaxNumChars)
+	}
+}
+
+// survivingChar returns the first listed character other than the dead one,
+// or 0 if there is none
+func survivingChar(chars *account.Chars, deadCharID int32) int32 {
+	if chars == nil {
+		return 0
+	}
+	for _, char := range chars.Characters {
+		if int32(char.ID) != deadCharID {
+			return int32(char.ID)
+		}
+	}
+	return 0
+}
+
+// parseDeathStats decodes the stats string sent with a Death packet. The server
+// has used both JSON objects and "key:value" lists, so both are accepted.
+func parseDeathStats(raw string) map[string]int64 {
+	stats := make(map[string]int64)
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return stats
+	}
+
+	if strings.HasPrefix(raw, "{") {
+		var values map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &values); err == nil {
+			for key, value := range values {
+				switch v := value.(type) {
+				case float64:
+					stats[key] = int64(v)
+				case string:
+					if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+						stats[key] = n
+					}
+				}
+			}
+			return stats
+		}
+	}
+
+	pairs := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+	for _, pair := range pairs {
+		sep := strings.IndexAny(pair, ":=")
+		if sep < 0 {
+			continue
+		}
+		key := strings.TrimSpace(pair[:sep])
+		n, err := strconv.ParseInt(strings.TrimSpace(pair[sep+1:]), 10, 64)
+		if key == "" || err != nil {
+			continue
+		}
+		stats[key] = n
+	}
+	return stats
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"testing"
+
+	"gorelay/pkg/account"
+	"gorelay/pkg/config"
+)
+
+func TestSelectAfterDeath(t *testing.T) {
+	chars := &account.Chars{Characters: []account.Char{{ID: 4}, {ID: 9}}}
+	onlyDead := &account.Chars{Characters: []account.Char{{ID: 4}}}
+
+	tests := []struct {
+		name     string
+		policy   string
+		chars    *account.Chars
+		wantOK   bool
+		wantChar int32
+	}{
+		{name: "existing picks a survivor", policy: config.DeathPolicyExisting, chars: chars, wantOK: true, wantChar: 9},
+		{name: "existing without survivors", policy: config.DeathPolicyExisting, chars: onlyDead},
+		{name: "existing without a list", policy: config.DeathPolicyExisting},
+		{name: "new ignores survivors", policy: config.DeathPolicyNew, chars: chars, wantOK: true},
+		{name: "any picks a survivor", policy: config.DeathPolicyAny, chars: chars, wantOK: true, wantChar: 9},
+		{name: "any creates without survivors", policy: config.DeathPolicyAny, chars: onlyDead, wantOK: true},
+		{name: "none", policy: config.DeathPolicyNone, chars: chars},
+		{name: "unknown", policy: "later", chars: chars},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection, ok := selectAfterDeath(tt.policy, tt.chars, 4)
+			if ok != tt.wantOK {
+				t.Fatalf("selectAfterDeath() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if selection.charID != tt.wantChar {
+				t.Fatalf("selected character %d, want %d", selection.charID, tt.wantChar)
+			}
+			if tt.wantChar == 0 && selection.chars != nil {
+				t.Fatal("a new character selection kept the character list")
+			}
+		})
+	}
+}
+
+func TestApplyCharSelection(t *testing.T) {
+	c := &Client{accountInfo: &account.Account{CharInfo: &account.CharInfo{CharID: 4}}}
+	chars := &account.Chars{NextCharId: 10, MaxNumChars: 3, Characters: []account.Char{{ID: 9}}}
+
+	c.applyCharSelection()
+	if c.accountInfo.CharInfo.CharID != 4 {
+		t.Fatal("applying without a selection changed the character")
+	}
+
+	c.nextChar = &charSelection{charID: 9, chars: chars}
+	c.applyCharSelection()
+	if c.accountInfo.CharInfo.CharID != 9 || c.accountInfo.Chars != chars || c.accountInfo.CharInfo.MaxNumChars != 3 {
+		t.Fatalf("account has character %d after applying the selection", c.accountInfo.CharInfo.CharID)
+	}
+	if c.nextChar != nil {
+		t.Fatal("selection was applied but kept")
+	}
+
+	c.nextChar = &charSelection{}
+	c.applyCharSelection()
+	if c.accountInfo.CharInfo.CharID != 0 || c.accountInfo.Chars != nil {
+		t.Fatal("new character selection kept the previous character")
+	}
+}
+
+func TestParseDeathStats(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want map[string]int64
+	}{
+		{name: "empty", raw: "  ", want: map[string]int64{}},
+		{name: "json", raw: `{"shots": 120, "tiles": "45", "note": "x"}`, want: map[string]int64{"shots": 120, "tiles": 45}},
+		{name: "pairs", raw: "shots:120, tiles=45;bad:x\nlevel: 20", want: map[string]int64{"shots": 120, "tiles": 45, "level": 20}},
+		{name: "broken json falls back to pairs", raw: "{shots:120", want: map[string]int64{"{shots": 120}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDeathStats(tt.raw)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseDeathStats() = %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Fatalf("parseDeathStats() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets"
+)
+
+// Movement speeds in tiles per second
+const (
+	minMoveSpeed     = 4.0
+	speedyMultiplier = 1.5
+)
+
+// ErrCannotUseItems is returned when a condition effect prevents using items
+var ErrCannotUseItems = errors.New("condition effects prevent using items")
+
+// HasEffect checks if the enemy has a specific status effect
+func (e *Enemy) HasEffect(effect models.ConditionEffect) bool {
+	return e.Effects.Has(effect)
+}
+
+// Effects returns the condition effects on the player
+func (c *Client) Effects() models.ConditionEffects {
+	return c.Snapshot().Player.Effects
+}
+
+// HasEffect checks if the player has a specific status effect
+func (c *Client) HasEffect(effect models.ConditionEffect) bool {
+	return c.Effects().Has(effect)
+}
+
+// CanMove reports whether condition effects allow the player to move
+func (c *Client) CanMove() bool {
+	return !c.Effects().HasAny(models.ConditionEffectParalyzed, models.ConditionEffectPetrified,
+		models.ConditionEffectStasis, models.ConditionEffectPaused)
+}
+
+// CanShoot reports whether condition effects allow the player to shoot
+func (c *Client) CanShoot() bool {
+	return !c.Effects().HasAny(models.ConditionEffectStunned, models.ConditionEffectPetrified,
+		models.ConditionEffectStasis, models.ConditionEffectPaused)
+}
+
+// CanUseItems reports whether condition effects allow the player to use items
+// and abilities
+func (c *Client) CanUseItems() bool {
+	return !c.Effects().HasAny(models.ConditionEffectSilenced, models.ConditionEffectPetrified,
+		models.ConditionEffectStasis, models.ConditionEffectPaused)
+}
+
+// MoveSpeed returns the player's movement speed in tiles per second after
+// condition effects
+func (c *Client) MoveSpeed() float32 {
+	if !c.CanMove() {
+		return 0
+	}
+	effects := c.Effects()
+	if effects.Has(models.ConditionEffectSlowed) {
+		return minMoveSpeed
+	}
+	speed := c.moveSpeed
+	if effects.HasAny(models.ConditionEffectSpeedy, models.ConditionEffectNinjaSpeedy) {
+		speed *= speedyMultiplier
+	}
+	return speed
+}
+
+// emitEffectChanges emits an event for every effect an object gained or lost
+func (c *Client) emitEffectChanges(packet packets.Packet, objectID int32, previous, current models.ConditionEffects) {
+	if previous == current {
+		return
+	}
+	gained, lost := current.Diff(previous)
+	for _, effect := range gained {
+		if objectID == c.state.ObjectID {
+			c.logger.Info("Client", "Became %s", effect)
+		}
+		c.emit(events.EventEffectGained, packet, effectEventData(objectID, effect))
+	}
+	for _, effect := range lost {
+		if objectID == c.state.ObjectID {
+			c.logger.Debug("Client", "No longer %s", effect)
+		}
+		c.emit(events.EventEffectLost, packet, effectEventData(objectID, effect))
+	}
+}
+
+// effectEventData describes a condition effect change for event payloads
+func effectEventData(objectID int32, effect models.ConditionEffect) *events.EffectEventData {
+	return &events.EffectEventData{
+		ObjectID: objectID,
+		Effect:   int32(effect),
+		Name:     effect.String(),
+	}
+}
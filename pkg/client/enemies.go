@@ -0,0 +1,211 @@
+package client
+
+import (
+	"strings"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/server"
+	"gorelay/pkg/xmldata"
+)
+
+// Damage rules and estimates
+const (
+	// minDamageFraction is the share of raw damage that always gets through defense
+	minDamageFraction = 0.15
+	exposedDefense    = 20
+	cursedMultiplier  = 1.25
+	// dpsWindow is how far back damage counts towards the damage rate
+	dpsWindow = 5 * time.Second
+)
+
+// damageSample is damage taken at a point in time
+type damageSample struct {
+	at     time.Time
+	amount int32
+}
+
+// newEnemy creates an enemy from its object definition
+func newEnemy(objectID, objectType int32, obj *xmldata.GameObject, pos *WorldPosData) *Enemy {
+	enemy := &Enemy{
+		ObjectID:    objectID,
+		ObjectType:  objectType,
+		Name:        xmldata.GetObjectName(int(objectType)),
+		Position:    pos,
+		HP:          int32(obj.MaxHitPoints),
+		MaxHP:       int32(obj.MaxHitPoints),
+		Defense:     int32(obj.Defense),
+		LastMove:    time.Now(),
+		God:         obj.God != nil,
+		Quest:       obj.Quest != nil,
+		Oryx:        obj.Oryx != nil,
+		DamageTaken: make(map[int32]int32),
+	}
+	for _, label := range strings.Split(obj.Labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			enemy.Labels = append(enemy.Labels, label)
+		}
+	}
+	return enemy
+}
+
+// HasLabel reports whether the enemy's definition carries a label
+func (e *Enemy) HasLabel(label string) bool {
+	for _, l := range e.Labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// DamageFor returns the damage a raw hit deals to the enemy
+func (e *Enemy) DamageFor(raw int32, armorPiercing bool) int32 {
+	return damageAfterDefense(raw, e.Defense, e.Effects, armorPiercing)
+}
+
+// damageAfterDefense returns the damage a raw hit deals to an object. Armor
+// piercing hits and Armor Broken ignore defense, Armored doubles it and Exposed
+// lowers it. At least 15% of the raw damage always gets through.
+func damageAfterDefense(raw, defense int32, effects models.ConditionEffects, armorPiercing bool) int32 {
+	if effects.HasAny(models.ConditionEffectInvulnerable, models.ConditionEffectInvincible, models.ConditionEffectStasis) {
+		return 0
+	}
+
+	if effects.Has(models.ConditionEffectExposed) {
+		defense -= exposedDefense
+	}
+	if effects.Has(models.ConditionEffectArmored) {
+		defense *= 2
+	}
+	if armorPiercing || effects.Has(models.ConditionEffectArmorBroken) {
+		defense = 0
+	}
+
+	damage := float64(raw - defense)
+	if min := float64(raw) * minDamageFraction; damage < min {
+		damage = min
+	}
+	if effects.Has(models.ConditionEffectCursed) {
+		damage *= cursedMultiplier
+	}
+	return int32(damage)
+}
+
+// Contribution returns the share of the damage taken that a player dealt
+func (e *Enemy) Contribution(objectID int32) float64 {
+	if e.TotalDamage == 0 {
+		return 0
+	}
+	return float64(e.DamageTaken[objectID]) / float64(e.TotalDamage)
+}
+
+// DPS returns the damage the enemy took per second over the last few seconds
+func (e *Enemy) DPS() float64 {
+	now := time.Now()
+	var total int32
+	first := now
+	for _, sample := range e.recent {
+		if now.Sub(sample.at) > dpsWindow {
+			continue
+		}
+		total += sample.amount
+		if sample.at.Before(first) {
+			first = sample.at
+		}
+	}
+	elapsed := now.Sub(first)
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+	return float64(total) / elapsed.Seconds()
+}
+
+// TimeToKill estimates how long the enemy survives at the current damage
+// rate, 0 when it is not taking damage
+func (e *Enemy) TimeToKill() time.Duration {
+	dps := e.DPS()
+	if dps <= 0 || e.HP <= 0 {
+		return 0
+	}
+	return time.Duration(float64(e.HP) / dps * float64(time.Second))
+}
+
+// recordDamage books damage dealt to the enemy by a player
+func (e *Enemy) recordDamage(sourceID, amount int32, at time.Time) {
+	if e.DamageTaken == nil {
+		e.DamageTaken = make(map[int32]int32)
+	}
+	if e.FirstHit.IsZero() {
+		e.FirstHit = at
+	}
+	e.DamageTaken[sourceID] += amount
+	e.TotalDamage += amount
+	e.LastHit = at
+
+	// Drop samples that fell out of the rate window
+	keep := e.recent[:0]
+	for _, sample := range e.recent {
+		if at.Sub(sample.at) <= dpsWindow {
+			keep = append(keep, sample)
+		}
+	}
+	e.recent = append(keep, damageSample{at: at, amount: amount})
+}
+
+// handleDamage books a Damage packet against the enemy that was hit. The
+// server sends the damage after defense, so it is applied as is; effects the
+// hit inflicted, such as Armor Broken, count for the next hits right away.
+func (c *Client) handleDamage(packet *server.Damage) {
+	enemy, ok := c.enemies[packet.TargetId]
+	if !ok || enemy.Dead {
+		return
+	}
+
+	amount := int32(packet.DamageAmount)
+	enemy.HP -= amount
+	enemy.recordDamage(packet.ObjectId, amount, time.Now())
+	for _, effect := range packet.Effects {
+		enemy.Effects = enemy.Effects.With(models.ConditionEffect(effect))
+	}
+
+	data := &events.DamageEventData{
+		TargetID:      enemy.ObjectID,
+		SourceID:      packet.ObjectId,
+		Damage:        amount,
+		ArmorPiercing: packet.ArmorPierce,
+	}
+	if enemy.Position != nil {
+		data.Position = events.Position{X: enemy.Position.X, Y: enemy.Position.Y}
+	}
+	c.emit(events.EventEnemyHit, packet, data)
+
+	if packet.Killed || enemy.HP <= 0 {
+		c.killEnemy(packet, enemy)
+	}
+}
+
+// enemyEventData describes an enemy for event payloads
+func enemyEventData(enemy *Enemy) *events.EnemyEventData {
+	data := &events.EnemyEventData{
+		ObjectID:    enemy.ObjectID,
+		ObjectType:  enemy.ObjectType,
+		HP:          enemy.HP,
+		MaxHP:       enemy.MaxHP,
+		Name:        enemy.Name,
+		God:         enemy.God,
+		Quest:       enemy.Quest,
+		TotalDamage: enemy.TotalDamage,
+	}
+	if enemy.Position != nil {
+		data.Position = events.Position{X: enemy.Position.X, Y: enemy.Position.Y}
+	}
+	if len(enemy.DamageTaken) > 0 {
+		data.DamageTaken = make(map[int32]int32, len(enemy.DamageTaken))
+		for id, amount := range enemy.DamageTaken {
+			data.DamageTaken[id] = amount
+		}
+	}
+	return data
+}
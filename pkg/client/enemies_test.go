@@ -0,0 +1,105 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/server"
+)
+
+func TestDamageAfterDefense(t *testing.T) {
+	none := models.NewConditionEffects(0, 0)
+	tests := []struct {
+		name          string
+		raw, defense  int32
+		effects       models.ConditionEffects
+		armorPiercing bool
+		want          int32
+	}{
+		{name: "no defense", raw: 100, effects: none, want: 100},
+		{name: "defense", raw: 100, defense: 30, effects: none, want: 70},
+		{name: "minimum damage", raw: 100, defense: 95, effects: none, want: 15},
+		{name: "armor piercing", raw: 100, defense: 50, effects: none, armorPiercing: true, want: 100},
+		{name: "armor broken", raw: 100, defense: 50, effects: none.With(models.ConditionEffectArmorBroken), want: 100},
+		{name: "armored", raw: 100, defense: 30, effects: none.With(models.ConditionEffectArmored), want: 40},
+		{name: "exposed", raw: 100, defense: 30, effects: none.With(models.ConditionEffectExposed), want: 90},
+		{name: "exposed below zero", raw: 100, defense: 10, effects: none.With(models.ConditionEffectExposed), want: 110},
+		{name: "cursed", raw: 100, defense: 20, effects: none.With(models.ConditionEffectCursed), want: 100},
+		{name: "cursed minimum", raw: 100, defense: 100, effects: none.With(models.ConditionEffectCursed), want: 18},
+		{name: "invulnerable", raw: 100, effects: none.With(models.ConditionEffectInvulnerable), want: 0},
+		{name: "invincible", raw: 100, effects: none.With(models.ConditionEffectInvincible), armorPiercing: true, want: 0},
+		{name: "stasis", raw: 100, effects: none.With(models.ConditionEffectStasis), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := damageAfterDefense(tt.raw, tt.defense, tt.effects, tt.armorPiercing); got != tt.want {
+				t.Fatalf("damageAfterDefense() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnemyDamageTracking(t *testing.T) {
+	const enemyID, self, other = 10, 1, 2
+	c := startTestClient(t)
+	got := recordEvents(c, events.EventEnemyHit, events.EventEnemyDeath)
+
+	hits := []struct {
+		source int32
+		amount uint16
+		wantHP int32
+	}{
+		{source: self, amount: 30, wantHP: 70},
+		{source: other, amount: 10, wantHP: 60},
+		{source: self, amount: 60, wantHP: 0},
+	}
+	if !c.run(func() {
+		c.enemies[enemyID] = &Enemy{ObjectID: enemyID, HP: 100, MaxHP: 100, Position: &WorldPosData{X: 1, Y: 1}}
+		for _, hit := range hits {
+			c.handleDamage(&server.Damage{TargetId: enemyID, ObjectId: hit.source, DamageAmount: hit.amount})
+			if enemy := c.enemies[enemyID]; enemy.HP != hit.wantHP {
+				t.Errorf("hp %d after %d damage, want %d", enemy.HP, hit.amount, hit.wantHP)
+			}
+		}
+	}) {
+		t.Fatal("state goroutine did not handle the damage")
+	}
+
+	enemy := c.Snapshot().Enemies[enemyID]
+	if enemy.TotalDamage != 100 || enemy.Contribution(self) != 0.9 || enemy.Contribution(other) != 0.1 {
+		t.Fatalf("total damage %d with shares %v and %v, want 100 split 0.9 and 0.1",
+			enemy.TotalDamage, enemy.Contribution(self), enemy.Contribution(other))
+	}
+	if !enemy.Dead {
+		t.Fatal("enemy at 0 hp is not dead")
+	}
+	want := []events.EventType{events.EventEnemyHit, events.EventEnemyHit, events.EventEnemyHit, events.EventEnemyDeath}
+	if len(*got) != len(want) {
+		t.Fatalf("got %d events, want %v", len(*got), want)
+	}
+	for i := range want {
+		if (*got)[i].Type != want[i] {
+			t.Fatalf("event %d is %v, want %v", i, (*got)[i].Type, want[i])
+		}
+	}
+}
+
+func TestEnemyDPSWindow(t *testing.T) {
+	now := time.Now()
+	e := &Enemy{HP: 500}
+	e.recordDamage(1, 1000, now.Add(-2*dpsWindow))
+	e.recordDamage(1, 100, now.Add(-2*time.Second))
+	e.recordDamage(1, 100, now)
+
+	if len(e.recent) != 2 {
+		t.Fatalf("kept %d damage samples, want the 2 inside the window", len(e.recent))
+	}
+	if dps := e.DPS(); dps < 99 || dps > 101 {
+		t.Fatalf("DPS() = %v, want 100", dps)
+	}
+	if ttk := e.TimeToKill().Round(100 * time.Millisecond); ttk != 5*time.Second {
+		t.Fatalf("TimeToKill() = %v, want 5s", ttk)
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"testing"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+)
+
+// recordEvents collects the events of the given types published by c
+func recordEvents(c *Client, types ...events.EventType) *[]*events.Event {
+	var got []*events.Event
+	for _, eventType := range types {
+		c.Events().Subscribe(eventType, func(event *events.Event) {
+			got = append(got, event)
+		})
+	}
+	return &got
+}
+
+func TestHandlersEmitEvents(t *testing.T) {
+	const playerID, enemyID = 1, 50
+	tests := []struct {
+		name   string
+		packet packets.Packet
+		want   []events.EventType
+		check  func(t *testing.T, c *Client, got []*events.Event)
+	}{
+		{
+			name:   "own teleport",
+			packet: &server.Goto{ObjectId: playerID, Location: server.Location{X: 3, Y: 4}},
+			want:   []events.EventType{events.EventPlayerMove},
+			check: func(t *testing.T, c *Client, got []*events.Event) {
+				data := got[0].Data.(*events.PlayerEventData)
+				if data.Position.X != 3 || data.Position.Y != 4 || c.state.WorldPos.X != 3 {
+					t.Errorf("teleport reported %v, want 3,4", data.Position)
+				}
+			},
+		},
+		{
+			name:   "enemy teleport",
+			packet: &server.Goto{ObjectId: enemyID, Location: server.Location{X: 7, Y: 8}},
+			check: func(t *testing.T, c *Client, got []*events.Event) {
+				if pos := c.enemies[enemyID].Position; pos.X != 7 || pos.Y != 8 {
+					t.Errorf("enemy is at %v,%v, want 7,8", pos.X, pos.Y)
+				}
+			},
+		},
+		{
+			name:   "notification",
+			packet: &server.Notification{NotificationType: server.NotificationTypeSystem, Message: "hello", ObjectId: playerID},
+			want:   []events.EventType{events.EventNotification},
+			check: func(t *testing.T, c *Client, got []*events.Event) {
+				if data := got[0].Data.(*events.NotificationEventData); data.Message != "hello" {
+					t.Errorf("notification message %q, want hello", data.Message)
+				}
+			},
+		},
+		{
+			name:   "aoe in range",
+			packet: &server.AOE{Location: &dataobjects.Location{X: 1, Y: 1}, Radius: 2, Damage: 30},
+			want:   []events.EventType{events.EventPlayerDamage},
+			check: func(t *testing.T, c *Client, got []*events.Event) {
+				if data := got[0].Data.(*events.DamageEventData); data.Damage != 30 || data.TargetID != playerID {
+					t.Errorf("damage event %+v, want 30 to the player", data)
+				}
+			},
+		},
+		{
+			name:   "aoe out of range",
+			packet: &server.AOE{Location: &dataobjects.Location{X: 20, Y: 20}, Radius: 2, Damage: 30},
+		},
+		{
+			name:   "enemy shot",
+			packet: &server.EnemyShoot{BulletId: 3, OwnerId: enemyID, Location: server.Location{X: 5, Y: 5}, Damage: 40, NumShots: 1},
+			want:   []events.EventType{events.EventEnemyShoot},
+			check: func(t *testing.T, c *Client, got []*events.Event) {
+				if data := got[0].Data.(*events.ProjectileEventData); data.OwnerID != enemyID || data.Damage != 40 {
+					t.Errorf("shot event %+v, want 40 damage from the enemy", data)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			got := recordEvents(c, events.EventPlayerMove, events.EventNotification,
+				events.EventPlayerDamage, events.EventEnemyShoot)
+			if !c.run(func() {
+				c.state.ObjectID = playerID
+				c.setPosition(&WorldPosData{X: 1, Y: 1})
+				c.enemies[enemyID] = &Enemy{ObjectID: enemyID, HP: 100, Position: &WorldPosData{X: 5, Y: 5}, DamageTaken: make(map[int32]int32)}
+				if err := c.packetHandler.HandlePacket(int(tt.packet.Type()), tt.packet); err != nil {
+					t.Errorf("handling %s failed: %v", tt.name, err)
+				}
+				// Checks run here on the state goroutine, so they report with Errorf
+				if len(*got) != len(tt.want) {
+					t.Errorf("got %d events, want %v", len(*got), tt.want)
+					return
+				}
+				for i, event := range *got {
+					if event.Type != tt.want[i] {
+						t.Errorf("event %d is %v, want %v", i, event.Type, tt.want[i])
+						return
+					}
+				}
+				if tt.check != nil {
+					tt.check(t, c, *got)
+				}
+			}) {
+				t.Fatal("state goroutine did not handle the packet")
+			}
+		})
+	}
+}
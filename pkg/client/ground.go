@@ -0,0 +1,112 @@
+package client
+
+import (
+	"math"
+	"math/rand"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/xmldata"
+)
+
+// UnknownTile is the tile type of squares the server has not sent yet
+const UnknownTile int32 = -1
+
+// groundDamageInterval is how often, in ms of client time, damaging ground
+// hurts a player standing on it
+const groundDamageInterval = 500
+
+// TileAt returns the tile type of a square, UnknownTile if it was not seen
+func (m *Map) TileAt(x, y int) int32 {
+	if y < 0 || y >= len(m.Tiles) || x < 0 || x >= len(m.Tiles[y]) {
+		return UnknownTile
+	}
+	return m.Tiles[y][x]
+}
+
+// GroundAt returns the ground definition of the square holding a world
+// position, or nil if the square was not seen
+func (m *Map) GroundAt(pos *WorldPosData) *xmldata.GroundType {
+	tile := m.TileAt(int(math.Floor(float64(pos.X))), int(math.Floor(float64(pos.Y))))
+	if tile == UnknownTile {
+		return nil
+	}
+	return xmldata.GetGroundByTypeID(int(tile))
+}
+
+// updateTiles stores the tiles sent in an Update. Changed rows are replaced
+// instead of written to, so published snapshots keep their view of the map.
+func (c *Client) updateTiles(tiles []*dataobjects.Tile) {
+	m := c.currentMap
+	if m == nil || len(tiles) == 0 || m.Width <= 0 || m.Height <= 0 {
+		return
+	}
+
+	rows := make([][]int32, m.Height)
+	copy(rows, m.Tiles)
+	replaced := make(map[int]bool)
+	for _, tile := range tiles {
+		x, y := int(tile.X), int(tile.Y)
+		if x < 0 || x >= int(m.Width) || y < 0 || y >= int(m.Height) {
+			continue
+		}
+		if !replaced[y] {
+			row := make([]int32, m.Width)
+			if rows[y] != nil {
+				copy(row, rows[y])
+			} else {
+				for i := range row {
+					row[i] = UnknownTile
+				}
+			}
+			rows[y] = row
+			replaced[y] = true
+		}
+		rows[y][x] = int32(tile.Type)
+	}
+	m.Tiles = rows
+}
+
+// checkGroundDamage reports damage from the ground under the player to the
+// server, as the game client does while standing on hazards like lava
+func (c *Client) checkGroundDamage(now int32) {
+	if c.currentMap == nil || c.state.WorldPos == nil || c.state.PlayerData == nil {
+		return
+	}
+	if now >= c.lastGroundDamage && now-c.lastGroundDamage < groundDamageInterval {
+		return
+	}
+	ground := c.currentMap.GroundAt(c.state.WorldPos)
+	if ground == nil || !ground.Damaging() {
+		return
+	}
+
+	raw := int32(ground.MaxDamage)
+	if ground.MinDamage < ground.MaxDamage {
+		raw = int32(ground.MinDamage + rand.Intn(ground.MaxDamage-ground.MinDamage+1))
+	}
+	// Ground damage ignores defense, but not invulnerability
+	damage := damageAfterDefense(raw, 0, c.state.PlayerData.Effects, true)
+	if damage <= 0 {
+		return
+	}
+	c.lastGroundDamage = now
+
+	packet := client.NewGroundDamage()
+	packet.Time = now
+	packet.Position = &client.Location{X: c.state.WorldPos.X, Y: c.state.WorldPos.Y}
+	if err := c.Send(packet); err != nil {
+		c.logger.Error("Client", "Failed to send GroundDamage: %v", err)
+		return
+	}
+	c.logger.Debug("Client", "Standing on %s, taking %d damage", ground.ID, damage)
+
+	c.emit(events.EventGroundDamage, packet, &events.DamageEventData{
+		TargetID:      c.state.ObjectID,
+		Damage:        damage,
+		ArmorPiercing: true,
+		Position:      events.Position{X: c.state.WorldPos.X, Y: c.state.WorldPos.Y},
+	})
+	c.safety.handleDamage(packet, damage)
+}
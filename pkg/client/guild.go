@@ -0,0 +1,221 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/server"
+)
+
+// Guild errors
+var (
+	ErrGuildTimeout  = errors.New("timed out waiting for guild result")
+	ErrNoGuildInvite = errors.New("no pending guild invite")
+	ErrNotInGuild    = errors.New("not in a guild")
+)
+
+// GuildInvite is an invite to join a guild
+type GuildInvite struct {
+	Inviter   string
+	GuildName string
+	Received  time.Time
+}
+
+// GuildManager performs guild actions and tracks their results. Actions block
+// until the server answers with a GuildResult, so they must not be called from
+// packet handlers or synchronous event handlers.
+type GuildManager struct {
+	Timeout time.Duration
+
+	client *Client
+
+	mu      sync.Mutex
+	invite  *GuildInvite
+	allowed map[string]bool // guilds whose invites are accepted automatically
+
+	opMu    sync.Mutex // serializes actions awaiting a result
+	results chan *server.GuildResult
+}
+
+// newGuildManager creates the guild manager for a client, accepting invites
+// from the guilds listed in the config
+func newGuildManager(c *Client) *GuildManager {
+	g := &GuildManager{
+		Timeout: 5 * time.Second,
+		client:  c,
+		allowed: make(map[string]bool),
+		results: make(chan *server.GuildResult, 1),
+	}
+	g.AllowGuild(c.config.Guild.AcceptInvitesFrom...)
+	return g
+}
+
+// Guild returns the client's guild manager
+func (c *Client) Guild() *GuildManager {
+	return c.guild
+}
+
+// AllowGuild accepts invites from the named guilds automatically
+func (g *GuildManager) AllowGuild(names ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, name := range names {
+		g.allowed[strings.ToLower(name)] = true
+	}
+}
+
+// Name returns the name of the player's guild, empty when not in one
+func (g *GuildManager) Name() string {
+	return g.client.Snapshot().Player.GuildName
+}
+
+// Rank returns the player's guild rank
+func (g *GuildManager) Rank() models.GuildRank {
+	if g.Name() == "" {
+		return models.GuildRankNoRank
+	}
+	return models.GuildRank(g.client.Snapshot().Player.GuildRank)
+}
+
+// PendingInvite returns the last guild invite that was not answered, or nil
+func (g *GuildManager) PendingInvite() *GuildInvite {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.invite == nil {
+		return nil
+	}
+	invite := *g.invite
+	return &invite
+}
+
+// Create founds a new guild
+func (g *GuildManager) Create(name string) error {
+	create := client.NewCreateGuild()
+	create.Name = name
+	return g.do(create)
+}
+
+// Invite invites a player to the guild
+func (g *GuildManager) Invite(player string) error {
+	if g.Name() == "" {
+		return ErrNotInGuild
+	}
+	invite := client.NewGuildInvite()
+	invite.Name = player
+	return g.do(invite)
+}
+
+// Accept joins the guild of the pending invite
+func (g *GuildManager) Accept() error {
+	g.mu.Lock()
+	invite := g.invite
+	g.invite = nil
+	g.mu.Unlock()
+	if invite == nil {
+		return ErrNoGuildInvite
+	}
+
+	join := client.NewJoinGuild()
+	join.GuildName = invite.GuildName
+	return g.do(join)
+}
+
+// Kick removes a member from the guild
+func (g *GuildManager) Kick(player string) error {
+	if g.Name() == "" {
+		return ErrNotInGuild
+	}
+	remove := client.NewGuildRemove()
+	remove.Name = player
+	return g.do(remove)
+}
+
+// Leave leaves the guild
+func (g *GuildManager) Leave() error {
+	return g.Kick(g.client.Snapshot().Player.Name)
+}
+
+// SetRank changes the rank of a guild member
+func (g *GuildManager) SetRank(player string, rank models.GuildRank) error {
+	if g.Name() == "" {
+		return ErrNotInGuild
+	}
+	if rank < models.GuildRankInitiate || rank > models.GuildRankFounder {
+		return fmt.Errorf("invalid guild rank %d", rank)
+	}
+	change := client.NewChangeGuildRank()
+	change.Name = player
+	change.GuildRank = byte(rank)
+	return g.do(change)
+}
+
+// do sends a guild packet and waits for the server's GuildResult
+func (g *GuildManager) do(packet packets.Packet) error {
+	g.opMu.Lock()
+	defer g.opMu.Unlock()
+
+	// Discard results for earlier, abandoned actions
+	select {
+	case <-g.results:
+	default:
+	}
+
+	if err := g.client.Send(packet); err != nil {
+		return err
+	}
+
+	select {
+	case result := <-g.results:
+		if !result.Success {
+			return fmt.Errorf("guild action failed: %s", result.ErrorText)
+		}
+		return nil
+	case <-time.After(g.Timeout):
+		return ErrGuildTimeout
+	}
+}
+
+// handleInvited records a guild invite and accepts it from allowed guilds
+func (g *GuildManager) handleInvited(packet *server.InvitedToGuild) {
+	g.mu.Lock()
+	g.invite = &GuildInvite{Inviter: packet.Name, GuildName: packet.GuildName, Received: time.Now()}
+	accept := g.allowed[strings.ToLower(packet.GuildName)]
+	g.mu.Unlock()
+
+	g.client.logger.Info("Guild", "%s invited us to %s", packet.Name, packet.GuildName)
+	g.client.emit(events.EventGuildInvite, packet, &events.GuildEventData{
+		Name:      packet.Name,
+		GuildName: packet.GuildName,
+	})
+
+	if accept {
+		go func() {
+			if err := g.Accept(); err != nil {
+				g.client.logger.Warning("Guild", "Failed to join %s: %v", packet.GuildName, err)
+				return
+			}
+			g.client.logger.Success("Guild", "Joined %s", packet.GuildName)
+		}()
+	}
+}
+
+// handleResult hands a GuildResult to the action waiting for it
+func (g *GuildManager) handleResult(packet *server.GuildResult) {
+	select {
+	case g.results <- packet:
+	default:
+		g.client.logger.Debug("Guild", "Dropping unexpected guild result")
+	}
+	g.client.emit(events.EventGuildResult, packet, &events.GuildEventData{
+		GuildName: g.Name(),
+		Success:   packet.Success,
+		Message:   packet.ErrorText,
+	})
+}
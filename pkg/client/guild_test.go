@@ -0,0 +1,168 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/server"
+)
+
+// answerGuild keeps handing result to the guild manager until stop is closed.
+// The action drains stale results before it sends, so a single answer could
+// be thrown away.
+func answerGuild(g *GuildManager, result *server.GuildResult, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		g.handleResult(result)
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGuildActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		inGuild bool
+		result  *server.GuildResult
+		action  func(g *GuildManager) error
+		wantErr string
+		want    error
+	}{
+		{
+			name:   "create",
+			result: &server.GuildResult{Success: true},
+			action: func(g *GuildManager) error { return g.Create("Testers") },
+		},
+		{
+			name:    "create rejected",
+			result:  &server.GuildResult{ErrorText: "Guild name already in use"},
+			action:  func(g *GuildManager) error { return g.Create("Testers") },
+			wantErr: "Guild name already in use",
+		},
+		{
+			name:   "no answer",
+			action: func(g *GuildManager) error { return g.Create("Testers") },
+			want:   ErrGuildTimeout,
+		},
+		{
+			name:   "invite outside a guild",
+			action: func(g *GuildManager) error { return g.Invite("Friend") },
+			want:   ErrNotInGuild,
+		},
+		{
+			name:    "invite",
+			inGuild: true,
+			result:  &server.GuildResult{Success: true},
+			action:  func(g *GuildManager) error { return g.Invite("Friend") },
+		},
+		{
+			name:   "kick outside a guild",
+			action: func(g *GuildManager) error { return g.Kick("Friend") },
+			want:   ErrNotInGuild,
+		},
+		{
+			name:    "rank too high",
+			inGuild: true,
+			action:  func(g *GuildManager) error { return g.SetRank("Friend", models.GuildRankFounder+1) },
+			wantErr: "invalid guild rank",
+		},
+		{
+			name:    "rank",
+			inGuild: true,
+			result:  &server.GuildResult{Success: true},
+			action:  func(g *GuildManager) error { return g.SetRank("Friend", models.GuildRankOfficer) },
+		},
+		{
+			name:   "accept without an invite",
+			action: func(g *GuildManager) error { return g.Accept() },
+			want:   ErrNoGuildInvite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			g := c.Guild()
+			g.Timeout = 50 * time.Millisecond
+			if tt.inGuild {
+				if !c.run(func() { c.updateStat(nil, int32(models.GUILDNAMESTAT), 0, "Testers") }) {
+					t.Fatal("state goroutine did not apply the guild name")
+				}
+			}
+
+			stop := make(chan struct{})
+			answered := make(chan struct{})
+			if tt.result != nil {
+				g.Timeout = time.Second
+				go func() {
+					defer close(answered)
+					answerGuild(g, tt.result, stop)
+				}()
+			} else {
+				close(answered)
+			}
+			err := tt.action(g)
+			close(stop)
+			<-answered
+
+			switch {
+			case tt.want != nil:
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got %v, want %v", err, tt.want)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v, want an error containing %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("action failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestGuildInvites(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    []string
+		guild      string
+		wantAccept bool
+	}{
+		{name: "unknown guild", allowed: []string{"Friends"}, guild: "Strangers"},
+		{name: "allowed guild", allowed: []string{"Friends"}, guild: "Friends", wantAccept: true},
+		{name: "allowed guild in another case", allowed: []string{"friends"}, guild: "FRIENDS", wantAccept: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			got := recordEvents(c, events.EventGuildInvite)
+			g := c.Guild()
+			g.Timeout = 10 * time.Millisecond
+			g.AllowGuild(tt.allowed...)
+
+			g.handleInvited(&server.InvitedToGuild{Name: "Inviter", GuildName: tt.guild})
+			if len(*got) != 1 {
+				t.Fatalf("got %d invite events, want 1", len(*got))
+			}
+
+			// Accepting clears the pending invite before waiting for the result
+			deadline := time.Now().Add(time.Second)
+			for tt.wantAccept && g.PendingInvite() != nil && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			invite := g.PendingInvite()
+			if accepted := invite == nil; accepted != tt.wantAccept {
+				t.Fatalf("invite accepted: %v, want %v", accepted, tt.wantAccept)
+			}
+			if invite != nil && (invite.Inviter != "Inviter" || invite.GuildName != tt.guild) {
+				t.Fatalf("pending invite %+v, want %s from Inviter", invite, tt.guild)
+			}
+		})
+	}
+}
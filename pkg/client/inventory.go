@@ -0,0 +1,419 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+	"gorelay/pkg/xmldata"
+)
+
+// Item slot layout of the player object
+const (
+	EquipmentSlots     = 4
+	FirstInventorySlot = 4
+	FirstBackpackSlot  = 12
+	TotalSlots         = 20
+
+	// EmptyItem is the item type of an empty slot
+	EmptyItem int32 = -1
+
+	// slotPollInterval is how often snapshots are checked for a confirmed change
+	slotPollInterval = 50 * time.Millisecond
+)
+
+// SlotKind describes what a slot is used for
+type SlotKind int
+
+const (
+	SlotEquipment SlotKind = iota
+	SlotInventory
+	SlotBackpack
+)
+
+// Inventory errors
+var (
+	ErrInventoryTimeout  = errors.New("timed out waiting for inventory result")
+	ErrInventoryRejected = errors.New("inventory change rejected by server")
+	ErrNoFreeSlot        = errors.New("no free inventory slot")
+)
+
+// Item describes the content of one of the player's slots
+type Item struct {
+	Slot     int32
+	Kind     SlotKind
+	ItemType int32
+	Name     string
+	SlotType int32 // Equipment slot type the item fits, 0 for none
+}
+
+// IsEmpty reports whether the slot holds no item
+func (i Item) IsEmpty() bool {
+	return i.ItemType == EmptyItem
+}
+
+// Inventory performs item operations for a client. Operations block until the
+// server confirms them, so they must not be called from packet handlers or
+// synchronous event handlers.
+type Inventory struct {
+	Timeout time.Duration
+	Retries int
+
+	client  *Client
+	opMu    sync.Mutex // serializes operations awaiting a result
+	results chan *server.InventoryResult
+}
+
+// newInventory creates the inventory manager for a client
+func newInventory(c *Client) *Inventory {
+	return &Inventory{
+		Timeout: 2 * time.Second,
+		Retries: 2,
+		client:  c,
+		results: make(chan *server.InventoryResult, 1),
+	}
+}
+
+// Inventory returns the client's inventory manager
+func (c *Client) Inventory() *Inventory {
+	return c.inventory
+}
+
+// SlotKindOf returns what a slot index is used for
+func SlotKindOf(slot int32) SlotKind {
+	switch {
+	case slot < EquipmentSlots:
+		return SlotEquipment
+	case slot < FirstBackpackSlot:
+		return SlotInventory
+	default:
+		return SlotBackpack
+	}
+}
+
+// Items returns a snapshot of every slot
+func (inv *Inventory) Items() []Item {
+	items := make([]Item, 0, TotalSlots)
+	for slot := int32(0); slot < TotalSlots; slot++ {
+		items = append(items, inv.Item(slot))
+	}
+	return items
+}
+
+// Item returns the content of a single slot
+func (inv *Inventory) Item(slot int32) Item {
+	item := Item{Slot: slot, Kind: SlotKindOf(slot), ItemType: EmptyItem}
+	data := &inv.client.Snapshot().Player
+	if slot >= 0 && int(slot) < len(data.Inventory) {
+		item.ItemType = data.Inventory[slot]
+	}
+	if item.ItemType != EmptyItem {
+		item.Name = xmldata.GetObjectName(int(item.ItemType))
+		if obj := xmldata.GetObjectByTypeID(int(item.ItemType)); obj != nil {
+			item.SlotType = int32(obj.SlotType)
+		}
+	}
+	return item
+}
+
+// Find returns the first slot holding an item with the given name
+func (inv *Inventory) Find(name string) (Item, bool) {
+	for _, item := range inv.Items() {
+		if !item.IsEmpty() && strings.EqualFold(item.Name, name) {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// FreeSlot returns the first empty inventory or backpack slot
+func (inv *Inventory) FreeSlot() (int32, bool) {
+	for slot := int32(FirstInventorySlot); slot < TotalSlots; slot++ {
+		if inv.Item(slot).IsEmpty() && inv.hasSlot(slot) {
+			return slot, true
+		}
+	}
+	return 0, false
+}
+
+// CanPlace reports whether an item type may be put into a slot. Equipment
+// slots only accept items matching the class's slot type.
+func (inv *Inventory) CanPlace(itemType, slot int32) bool {
+	if slot < 0 || slot >= TotalSlots || !inv.hasSlot(slot) {
+		return false
+	}
+	if itemType == EmptyItem || SlotKindOf(slot) != SlotEquipment {
+		return true
+	}
+	obj := xmldata.GetObjectByTypeID(int(itemType))
+	if obj == nil {
+		return false
+	}
+	slotTypes := classSlotTypes(inv.client.Snapshot().ClassType)
+	if int(slot) >= len(slotTypes) {
+		// Unknown class layout, let the server decide
+		return obj.SlotType != 0
+	}
+	return int32(obj.SlotType) == slotTypes[slot]
+}
+
+// Swap exchanges the items in two of the player's slots
+func (inv *Inventory) Swap(from, to int32) error {
+	a, b := inv.Item(from), inv.Item(to)
+	if a.IsEmpty() && b.IsEmpty() {
+		return nil
+	}
+	if !inv.CanPlace(a.ItemType, to) || !inv.CanPlace(b.ItemType, from) {
+		return fmt.Errorf("cannot swap slot %d (%s) with slot %d (%s)", from, a.Name, to, b.Name)
+	}
+
+	objectID := inv.client.Snapshot().ObjectID
+	err := inv.swap(
+		dataobjects.NewSlotObjectWithData(objectID, from, a.ItemType),
+		dataobjects.NewSlotObjectWithData(objectID, to, b.ItemType),
+	)
+	if err != nil {
+		return err
+	}
+	inv.predictSlots(map[int32]int32{from: b.ItemType, to: a.ItemType})
+	return nil
+}
+
+// Equip moves an item from the inventory into the matching equipment slot
+func (inv *Inventory) Equip(slot int32) error {
+	item := inv.Item(slot)
+	if item.IsEmpty() {
+		return fmt.Errorf("slot %d is empty", slot)
+	}
+	for equip := int32(0); equip < EquipmentSlots; equip++ {
+		if inv.CanPlace(item.ItemType, equip) {
+			return inv.Swap(slot, equip)
+		}
+	}
+	return fmt.Errorf("%s cannot be equipped", item.Name)
+}
+
+// LootFrom moves an item out of a container, such as a loot bag, into the
+// first free slot
+func (inv *Inventory) LootFrom(containerID, containerSlot, itemType int32) error {
+	slot, ok := inv.FreeSlot()
+	if !ok {
+		return ErrNoFreeSlot
+	}
+
+	err := inv.swap(
+		dataobjects.NewSlotObjectWithData(containerID, containerSlot, itemType),
+		dataobjects.NewSlotObjectWithData(inv.client.Snapshot().ObjectID, slot, EmptyItem),
+	)
+	if err != nil {
+		return err
+	}
+	inv.predictSlots(map[int32]int32{slot: itemType})
+	return nil
+}
+
+// Drop drops the item in a slot on the ground. The slot is left as it is
+// until the server's stats show it empty.
+func (inv *Inventory) Drop(slot int32) error {
+	item := inv.Item(slot)
+	if item.IsEmpty() {
+		return fmt.Errorf("slot %d is empty", slot)
+	}
+
+	objectID := inv.client.Snapshot().ObjectID
+	return inv.confirm(fmt.Sprintf("drop from slot %d", slot), func() error {
+		drop := client.NewInventoryDrop()
+		drop.Slot = dataobjects.NewSlotObjectWithData(objectID, slot, item.ItemType)
+		return inv.client.Send(drop)
+	}, func() bool {
+		return inv.Item(slot).ItemType != item.ItemType
+	})
+}
+
+// Use uses the item in a slot at the player's position
+func (inv *Inventory) Use(slot int32) error {
+	pos := inv.client.GetPosition()
+	if pos == nil {
+		pos = &WorldPosData{}
+	}
+	return inv.UseAt(slot, pos)
+}
+
+// UseAt uses the item in a slot, aiming abilities at target. Consumables are
+// resent until the server's stats show them used. Other items, like abilities,
+// stay in their slot, so nothing confirms them and they are sent once, as a
+// resend could use them twice.
+func (inv *Inventory) UseAt(slot int32, target *WorldPosData) error {
+	item := inv.Item(slot)
+	if item.IsEmpty() {
+		return fmt.Errorf("slot %d is empty", slot)
+	}
+	if !inv.client.CanUseItems() {
+		return ErrCannotUseItems
+	}
+
+	objectID := inv.client.Snapshot().ObjectID
+	send := func() error {
+		return inv.client.Send(&client.UseItem{
+			Time:       inv.client.clock.ClientTime(),
+			SlotObject: dataobjects.NewSlotObjectWithData(objectID, slot, item.ItemType),
+			ItemUsePos: dataobjects.NewLocationWithCoords(float64(target.X), float64(target.Y)),
+		})
+	}
+	if obj := xmldata.GetObjectByTypeID(int(item.ItemType)); obj == nil || obj.Consumable == nil {
+		return send()
+	}
+	return inv.confirm(fmt.Sprintf("use of slot %d", slot), send, func() bool {
+		return inv.Item(slot).ItemType != item.ItemType
+	})
+}
+
+// swap sends an InventorySwap and waits for the server to confirm it
+func (inv *Inventory) swap(slot1, slot2 *dataobjects.SlotObject) error {
+	return inv.confirm(fmt.Sprintf("swap of slot %d and %d", slot1.SlotID, slot2.SlotID), func() error {
+		packet := client.NewInventorySwap()
+		packet.Time = inv.client.clock.ClientTime()
+		packet.Position = &client.Location{}
+		if pos := inv.client.GetPosition(); pos != nil {
+			packet.Position = &client.Location{X: pos.X, Y: pos.Y}
+		}
+		packet.SlotObject1 = slot1
+		packet.SlotObject2 = slot2
+		return inv.client.Send(packet)
+	}, func() bool {
+		return inv.swapped(slot1, slot2)
+	})
+}
+
+// confirm sends an inventory change and waits until the server accepts it with
+// an InventoryResult or its stats show the change, as reported by done. The
+// change is resent on timeout.
+func (inv *Inventory) confirm(what string, send func() error, done func() bool) error {
+	inv.opMu.Lock()
+	defer inv.opMu.Unlock()
+
+	for attempt := 0; attempt <= inv.Retries; attempt++ {
+		// Discard results for earlier, abandoned attempts
+		select {
+		case <-inv.results:
+		default:
+		}
+
+		// Only the confirmation of the last attempt may have been lost, in which
+		// case the server rejects a resend of a change it already made
+		if attempt > 0 && done() {
+			return nil
+		}
+		if err := send(); err != nil {
+			return err
+		}
+
+		timeout := time.After(inv.Timeout)
+	wait:
+		for {
+			select {
+			case result := <-inv.results:
+				if !result.Result {
+					return ErrInventoryRejected
+				}
+				return nil
+			case <-time.After(slotPollInterval):
+				if done() {
+					return nil
+				}
+			case <-timeout:
+				break wait
+			}
+		}
+		inv.client.logger.Warning("Inventory", "No confirmation for %s (attempt %d/%d)",
+			what, attempt+1, inv.Retries+1)
+	}
+	return ErrInventoryTimeout
+}
+
+// swapped reports whether the latest snapshot shows the items of two slots
+// exchanged
+func (inv *Inventory) swapped(slot1, slot2 *dataobjects.SlotObject) bool {
+	item1, ok1 := inv.slotItem(slot1)
+	item2, ok2 := inv.slotItem(slot2)
+	return ok1 && ok2 && item1 == slot2.ObjectType && item2 == slot1.ObjectType
+}
+
+// slotItem returns the item in a slot of the player or a tracked container
+func (inv *Inventory) slotItem(slot *dataobjects.SlotObject) (int32, bool) {
+	snap := inv.client.Snapshot()
+	var items []int32
+	if slot.ObjectID == snap.ObjectID {
+		items = snap.Player.Inventory
+	} else if container, ok := snap.Containers[slot.ObjectID]; ok {
+		items = container.Items
+	}
+	if slot.SlotID < 0 || int(slot.SlotID) >= len(items) {
+		return 0, false
+	}
+	return items[slot.SlotID], true
+}
+
+// handleResult hands an InventoryResult to the operation waiting for it
+func (inv *Inventory) handleResult(result *server.InventoryResult) {
+	select {
+	case inv.results <- result:
+	default:
+		inv.client.logger.Debug("Inventory", "Dropping unexpected inventory result")
+	}
+}
+
+// hasSlot reports whether the player has a slot, backpack slots need a backpack
+func (inv *Inventory) hasSlot(slot int32) bool {
+	if SlotKindOf(slot) != SlotBackpack {
+		return true
+	}
+	return inv.client.Snapshot().Player.BackpackSlots > 0
+}
+
+// predictSlots applies the result of a confirmed inventory change on the state
+// goroutine, until the player's stats catch up. The slots' last stat values are
+// forgotten so the next stats replace the prediction even if they did not change.
+func (inv *Inventory) predictSlots(slots map[int32]int32) {
+	applied := inv.client.run(func() {
+		for slot, itemType := range slots {
+			inv.client.setInventorySlot(nil, int(slot), itemType)
+			delete(inv.client.state.rawStats, int32(slotStat(slot)))
+		}
+	})
+	if !applied {
+		inv.client.logger.Debug("Inventory", "Could not apply inventory change, waiting for the server")
+	}
+}
+
+// slotStat returns the stat that holds the item in a slot
+func slotStat(slot int32) models.StatType {
+	if slot < FirstBackpackSlot {
+		return models.INVENTORY0STAT + models.StatType(slot)
+	}
+	return models.BACKPACK0STAT + models.StatType(slot-FirstBackpackSlot)
+}
+
+// classSlotTypes returns the slot type of each item slot for a player class
+func classSlotTypes(classType int32) []int32 {
+	obj := xmldata.GetObjectByTypeID(int(classType))
+	if obj == nil || obj.SlotTypes == "" {
+		return nil
+	}
+	var slotTypes []int32
+	for _, field := range strings.Split(obj.SlotTypes, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil
+		}
+		slotTypes = append(slotTypes, int32(n))
+	}
+	return slotTypes
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorelay/pkg/models"
+	"gorelay/pkg/xmldata"
+)
+
+// Item types of the test objects
+const (
+	testWizard = 0x30e
+	testStaff  = 0xa00
+	testRing   = 0xa01
+	testPotion = 0xa02
+)
+
+// useTestObjects replaces the loaded game objects for the rest of the test
+func useTestObjects(t *testing.T, objects ...xmldata.GameObject) {
+	t.Helper()
+	previous := xmldata.Objects
+	xmldata.Objects = xmldata.CreateGameData(&xmldata.GameObjects{Objects: objects})
+	t.Cleanup(func() { xmldata.Objects = previous })
+}
+
+// useInventoryObjects loads a class and a few items for inventory tests
+func useInventoryObjects(t *testing.T) {
+	useTestObjects(t,
+		xmldata.GameObject{Type: "0x30e", ID: "Wizard", SlotTypes: "17,14,6,9,0,0,0,0,0,0,0,0"},
+		xmldata.GameObject{Type: "0xa00", ID: "Staff", SlotType: 17},
+		xmldata.GameObject{Type: "0xa01", ID: "Ring", SlotType: 9},
+		xmldata.GameObject{Type: "0xa02", ID: "Potion", Consumable: &struct{}{}},
+	)
+}
+
+// setSlot applies a server stat for one of the player's slots
+func setSlot(t *testing.T, c *Client, slot, itemType int32) {
+	t.Helper()
+	if !c.run(func() { c.updateStat(nil, int32(slotStat(slot)), itemType, "") }) {
+		t.Fatal("state goroutine did not apply the stat")
+	}
+}
+
+func TestSlotKindOf(t *testing.T) {
+	tests := []struct {
+		slot int32
+		want SlotKind
+	}{
+		{0, SlotEquipment},
+		{3, SlotEquipment},
+		{4, SlotInventory},
+		{11, SlotInventory},
+		{12, SlotBackpack},
+		{19, SlotBackpack},
+	}
+	for _, tt := range tests {
+		if got := SlotKindOf(tt.slot); got != tt.want {
+			t.Errorf("SlotKindOf(%d) = %d, want %d", tt.slot, got, tt.want)
+		}
+	}
+}
+
+func TestSlotStat(t *testing.T) {
+	tests := []struct {
+		slot int32
+		want models.StatType
+	}{
+		{0, models.INVENTORY0STAT},
+		{11, models.INVENTORY11STAT},
+		{12, models.BACKPACK0STAT},
+		{19, models.BACKPACK7STAT},
+	}
+	for _, tt := range tests {
+		if got := slotStat(tt.slot); got != tt.want {
+			t.Errorf("slotStat(%d) = %d, want %d", tt.slot, got, tt.want)
+		}
+	}
+}
+
+func TestCanPlace(t *testing.T) {
+	useInventoryObjects(t)
+	c := startTestClient(t)
+	if !c.run(func() { c.state.ClassType = testWizard }) {
+		t.Fatal("state goroutine did not run the setup")
+	}
+
+	tests := []struct {
+		name     string
+		itemType int32
+		slot     int32
+		backpack bool
+		want     bool
+	}{
+		{name: "weapon in weapon slot", itemType: testStaff, slot: 0, want: true},
+		{name: "weapon in ring slot", itemType: testStaff, slot: 3},
+		{name: "ring in ring slot", itemType: testRing, slot: 3, want: true},
+		{name: "potion in equipment", itemType: testPotion, slot: 1},
+		{name: "unknown item in equipment", itemType: 0xfff, slot: 0},
+		{name: "empty equipment slot", itemType: EmptyItem, slot: 2, want: true},
+		{name: "anything in inventory", itemType: testStaff, slot: 7, want: true},
+		{name: "backpack without a backpack", itemType: testPotion, slot: 12},
+		{name: "backpack with a backpack", itemType: testPotion, slot: 12, backpack: true, want: true},
+		{name: "outside the slots", itemType: testPotion, slot: TotalSlots, backpack: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasBackpack := int32(0)
+			if tt.backpack {
+				hasBackpack = 1
+			}
+			if !c.run(func() { c.updateStat(nil, int32(models.HASBACKPACKSTAT), hasBackpack, "") }) {
+				t.Fatal("state goroutine did not apply the stat")
+			}
+			if got := c.Inventory().CanPlace(tt.itemType, tt.slot); got != tt.want {
+				t.Fatalf("CanPlace(%#x, %d) = %v, want %v", tt.itemType, tt.slot, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropWaitsForServer(t *testing.T) {
+	c := startTestClient(t)
+	setSlot(t, c, 5, testStaff)
+	inv := c.Inventory()
+	inv.Timeout = 2 * time.Second
+
+	dropped := make(chan error, 1)
+	go func() { dropped <- inv.Drop(5) }()
+
+	time.Sleep(2 * slotPollInterval)
+	if item := inv.Item(5); item.ItemType != testStaff {
+		t.Fatalf("slot holds %#x before the server confirmed the drop", item.ItemType)
+	}
+	setSlot(t, c, 5, EmptyItem)
+	if err := <-dropped; err != nil {
+		t.Fatalf("Drop failed: %v", err)
+	}
+}
+
+func TestDropTimesOutWithoutChangingSlot(t *testing.T) {
+	c := startTestClient(t)
+	setSlot(t, c, 5, testStaff)
+	inv := c.Inventory()
+	inv.Timeout = 2 * slotPollInterval
+	inv.Retries = 1
+
+	if err := inv.Drop(5); !errors.Is(err, ErrInventoryTimeout) {
+		t.Fatalf("Drop error = %v, want ErrInventoryTimeout", err)
+	}
+	if item := inv.Item(5); item.ItemType != testStaff {
+		t.Fatalf("slot holds %#x after an unconfirmed drop", item.ItemType)
+	}
+}
+
+func TestUseConsumableWaitsForServer(t *testing.T) {
+	useInventoryObjects(t)
+	c := startTestClient(t)
+	setSlot(t, c, 6, testPotion)
+	inv := c.Inventory()
+	inv.Timeout = 2 * slotPollInterval
+	inv.Retries = 1
+
+	if err := inv.Use(6); !errors.Is(err, ErrInventoryTimeout) {
+		t.Fatalf("Use error = %v, want ErrInventoryTimeout", err)
+	}
+
+	inv.Timeout = 2 * time.Second
+	used := make(chan error, 1)
+	go func() { used <- inv.Use(6) }()
+	time.Sleep(2 * slotPollInterval)
+	setSlot(t, c, 6, EmptyItem)
+	if err := <-used; err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+}
+
+func TestPredictionYieldsToServerStats(t *testing.T) {
+	c := startTestClient(t)
+	setSlot(t, c, 4, testStaff)
+
+	c.Inventory().predictSlots(map[int32]int32{4: EmptyItem})
+	if item := c.Inventory().Item(4); !item.IsEmpty() {
+		t.Fatalf("slot holds %#x after the prediction", item.ItemType)
+	}
+
+	// The server still has the item, resending the same value must restore it
+	setSlot(t, c, 4, testStaff)
+	if item := c.Inventory().Item(4); item.ItemType != testStaff {
+		t.Fatalf("slot holds %#x, want the server's item", item.ItemType)
+	}
+}
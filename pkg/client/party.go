@@ -0,0 +1,568 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+)
+
+// Party errors
+var (
+	ErrNotInParty     = errors.New("not in a party")
+	ErrAlreadyInParty = errors.New("already in a party")
+	ErrPartyTimeout   = errors.New("timed out waiting for party response")
+	ErrNoPartyInvite  = errors.New("no pending party invite")
+)
+
+// partyReinviteDelay is how long to wait before inviting the same player again
+const partyReinviteDelay = 30 * time.Second
+
+// maxPartyPlayerID is the largest object ID a party action can carry, the
+// server reads it as an unsigned short
+const maxPartyPlayerID = math.MaxUint16
+
+// PartyMember is a member of the client's party
+type PartyMember struct {
+	PlayerID  int32
+	Name      string
+	ClassType int32
+	SkinID    int32
+}
+
+// Party is a snapshot of the client's party
+type Party struct {
+	ID          uint32
+	MaxSize     int
+	Description string
+	Leader      bool // the client leads the party
+	Members     []PartyMember
+}
+
+// PartyManager creates and joins parties and keeps track of their members.
+// Create and Invite block until the server answers, so they must not be called
+// from packet handlers or synchronous event handlers. Most party packet ids are
+// still placeholders, see interfaces.PacketType.
+type PartyManager struct {
+	Timeout time.Duration
+
+	client *Client
+
+	mu       sync.Mutex
+	party    *Party
+	invites  map[string]uint32        // pending invites by inviter name
+	allowed  map[string]bool          // players whose invites are accepted and who are invited on sight
+	invited  map[string]time.Time     // last invite sent per player
+	listings []*dataobjects.PartyInfo // public parties sent by the server
+	joined   chan struct{}
+	invitees chan *server.PartyJoinRequestResponse
+}
+
+// newPartyManager creates the party manager for a client
+func newPartyManager(c *Client) *PartyManager {
+	return &PartyManager{
+		Timeout:  10 * time.Second,
+		client:   c,
+		invites:  make(map[string]uint32),
+		allowed:  make(map[string]bool),
+		invited:  make(map[string]time.Time),
+		joined:   make(chan struct{}, 1),
+		invitees: make(chan *server.PartyJoinRequestResponse, 1),
+	}
+}
+
+// Party returns the client's party manager
+func (c *Client) Party() *PartyManager {
+	return c.party
+}
+
+// AllowMember makes the client accept party invites from the named players and,
+// while leading a party, invite them whenever they come into view. Use it with
+// the character names of the other accounts in a group to keep it together.
+func (p *PartyManager) AllowMember(names ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, name := range names {
+		p.allowed[strings.ToLower(name)] = true
+	}
+}
+
+// Current returns a snapshot of the party, or nil when not in one
+func (p *PartyManager) Current() *Party {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.party == nil {
+		return nil
+	}
+	party := *p.party
+	party.Members = append([]PartyMember(nil), p.party.Members...)
+	return &party
+}
+
+// Listings returns the public parties from the server's latest party list
+func (p *PartyManager) Listings() []*dataobjects.PartyInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*dataobjects.PartyInfo(nil), p.listings...)
+}
+
+// Create creates a private party and waits until the server confirms it
+func (p *PartyManager) Create(description string, maxSize byte) error {
+	p.mu.Lock()
+	inParty := p.party != nil
+	p.mu.Unlock()
+	if inParty {
+		return ErrAlreadyInParty
+	}
+
+	p.drainJoined()
+	create := client.NewPartyCreate()
+	create.Description = description
+	create.PartySizeMax = maxSize
+	if err := p.client.Send(create); err != nil {
+		return err
+	}
+	if err := p.waitJoined(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.party != nil {
+		p.party.Leader = true
+	}
+	p.mu.Unlock()
+	return nil
+}
+
+// Invite invites a player in view to the party and waits for their answer
+func (p *PartyManager) Invite(name string) error {
+	player := p.client.findPlayer(name)
+	if player == nil {
+		return fmt.Errorf("player %s is not in view", name)
+	}
+
+	p.mu.Lock()
+	inParty := p.party != nil
+	p.invited[strings.ToLower(name)] = time.Now()
+	p.mu.Unlock()
+	if !inParty {
+		return ErrNotInParty
+	}
+
+	select {
+	case <-p.invitees:
+	default:
+	}
+	request := client.NewPartyJoinRequest()
+	request.PlayerID = uint32(player.ObjectID)
+	if err := p.client.Send(request); err != nil {
+		return err
+	}
+
+	deadline := time.After(p.Timeout)
+	for {
+		select {
+		case response := <-p.invitees:
+			if !strings.EqualFold(response.Name, name) {
+				continue
+			}
+			switch response.State {
+			case server.InviteStatePending:
+				continue
+			case server.InviteStateAccepted:
+				return nil
+			default:
+				return fmt.Errorf("invite to %s failed: %s", name, inviteStateName(response.State))
+			}
+		case <-deadline:
+			return ErrPartyTimeout
+		}
+	}
+}
+
+// Accept accepts a pending invite and waits until the party is joined
+func (p *PartyManager) Accept(inviter string) error {
+	partyID, err := p.takeInvite(inviter)
+	if err != nil {
+		return err
+	}
+
+	p.drainJoined()
+	if err := p.respond(partyID, client.Accept); err != nil {
+		return err
+	}
+	return p.waitJoined()
+}
+
+// Decline declines a pending invite
+func (p *PartyManager) Decline(inviter string) error {
+	partyID, err := p.takeInvite(inviter)
+	if err != nil {
+		return err
+	}
+	return p.respond(partyID, client.Decline)
+}
+
+// Leave leaves the current party
+func (p *PartyManager) Leave() error {
+	if p.Current() == nil {
+		return ErrNotInParty
+	}
+	if err := p.sendAction(p.client.Snapshot().ObjectID, client.PartyActionLeftParty); err != nil {
+		return err
+	}
+	p.leave()
+	return nil
+}
+
+// Kick removes a member from the party
+func (p *PartyManager) Kick(name string) error {
+	member, err := p.member(name)
+	if err != nil {
+		return err
+	}
+	return p.sendAction(member.PlayerID, client.PartyActionKicked)
+}
+
+// Promote makes a member the party leader
+func (p *PartyManager) Promote(name string) error {
+	member, err := p.member(name)
+	if err != nil {
+		return err
+	}
+	if err := p.sendAction(member.PlayerID, client.PartyActionPromotedToLeader); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	if p.party != nil {
+		p.party.Leader = false
+	}
+	p.mu.Unlock()
+	return nil
+}
+
+// handleInvite records an incoming invite and accepts it from allowed players
+func (p *PartyManager) handleInvite(packet *server.IncomingPartyInvite) {
+	p.mu.Lock()
+	p.invites[strings.ToLower(packet.InviterName)] = packet.PartyId
+	accept := p.allowed[strings.ToLower(packet.InviterName)] && p.party == nil
+	p.mu.Unlock()
+
+	p.client.logger.Info("Party", "%s invited us to party %d", packet.InviterName, packet.PartyId)
+	p.client.emit(events.EventPartyInvite, packet, &events.PartyEventData{
+		PartyID: packet.PartyId,
+		Name:    packet.InviterName,
+	})
+
+	if accept {
+		go func() {
+			if err := p.Accept(packet.InviterName); err != nil {
+				p.client.logger.Warning("Party", "Failed to accept invite from %s: %v", packet.InviterName, err)
+			}
+		}()
+	}
+}
+
+// handleMemberInfo replaces the party with the member list sent by the server
+func (p *PartyManager) handleMemberInfo(packet *server.IncomingPartyMemberInfo) {
+	p.mu.Lock()
+	joined := p.party == nil || p.party.ID != packet.PartyId
+	party := &Party{
+		ID:          packet.PartyId,
+		MaxSize:     int(packet.MaxSize),
+		Description: packet.Description,
+	}
+	if !joined {
+		party.Leader = p.party.Leader
+	}
+	for _, player := range packet.PartyPlayer {
+		party.Members = append(party.Members, PartyMember{
+			PlayerID:  player.ObjectId,
+			Name:      player.Name,
+			ClassType: player.ClassType,
+		})
+	}
+	p.party = party
+	data := p.eventData()
+	p.mu.Unlock()
+
+	if joined {
+		p.client.logger.Info("Party", "Joined party %d with %d members", packet.PartyId, len(party.Members))
+		select {
+		case p.joined <- struct{}{}:
+		default:
+		}
+		p.client.emit(events.EventPartyJoined, packet, data)
+		return
+	}
+	p.client.emit(events.EventPartyUpdate, packet, data)
+}
+
+// handleMemberAdded adds a member that joined the party
+func (p *PartyManager) handleMemberAdded(packet *server.PartyMemberAdded) {
+	p.mu.Lock()
+	if p.party == nil {
+		p.mu.Unlock()
+		return
+	}
+	p.party.Members = append(p.party.Members, PartyMember{
+		PlayerID:  int32(packet.PlayerId),
+		Name:      packet.Name,
+		ClassType: int32(packet.ClassId),
+		SkinID:    int32(packet.SkinId),
+	})
+	data := p.eventData()
+	data.Name = packet.Name
+	data.PlayerID = int32(packet.PlayerId)
+	p.mu.Unlock()
+
+	p.client.logger.Info("Party", "%s joined the party", packet.Name)
+	p.client.emit(events.EventPartyUpdate, packet, data)
+}
+
+// handleJoinRequestResponse hands the state of an invite to the waiting Invite call
+func (p *PartyManager) handleJoinRequestResponse(packet *server.PartyJoinRequestResponse) {
+	select {
+	case p.invitees <- packet:
+	default:
+	}
+}
+
+// handleAction applies a kick, promotion or departure reported by the server
+func (p *PartyManager) handleAction(packet *server.PartyAction) {
+	playerID := int32(packet.PlayerId)
+	self := playerID == p.client.state.ObjectID
+
+	p.mu.Lock()
+	if p.party == nil {
+		p.mu.Unlock()
+		return
+	}
+	switch packet.ActionId {
+	case server.PartyActionKicked, server.PartyActionLeftParty:
+		if self {
+			p.party = nil
+			p.mu.Unlock()
+			p.client.logger.Info("Party", "Left the party")
+			p.client.emit(events.EventPartyLeft, packet, &events.PartyEventData{PlayerID: playerID})
+			return
+		}
+		for i, member := range p.party.Members {
+			if member.PlayerID == playerID {
+				p.party.Members = append(p.party.Members[:i], p.party.Members[i+1:]...)
+				break
+			}
+		}
+	case server.PartyActionPromotedToLeader:
+		p.party.Leader = self
+	case server.PartyActionFailed, server.PartyActionKickNotFound, server.PartyActionPromoteNotFound:
+		p.mu.Unlock()
+		p.client.logger.Warning("Party", "Party action on player %d failed (%d)", playerID, packet.ActionId)
+		return
+	}
+	data := p.eventData()
+	data.PlayerID = playerID
+	p.mu.Unlock()
+
+	p.client.emit(events.EventPartyUpdate, packet, data)
+}
+
+// handleList stores the public parties sent by the server. The list arrives in
+// numbered packets, the first one replaces the previous list.
+func (p *PartyManager) handleList(packet *server.PartyList) {
+	p.mu.Lock()
+	if packet.PacketNumber == 0 {
+		p.listings = nil
+	}
+	p.listings = append(p.listings, packet.Parties...)
+	count := len(p.listings)
+	p.mu.Unlock()
+
+	p.client.logger.Debug("Party", "Party list page %d, %d parties listed", packet.PacketNumber, count)
+}
+
+// handleJoinResponse follows the party to the server its leader plays on. The
+// server is switched from another goroutine, as the state goroutine ends with
+// the old connection.
+func (p *PartyManager) handleJoinResponse(packet *server.PartyJoinResponse) {
+	host := packet.ServerIpHost
+	if host == "" {
+		return
+	}
+	if current := p.client.GetCurrentServer(); current != nil && (current.Address == host || current.DNS == host) {
+		return
+	}
+
+	target := serverByHost(host)
+	p.client.logger.Info("Party", "Following the party to %s", target.Name)
+	go func() {
+		if err := p.client.switchTo(target); err != nil {
+			p.client.logger.Error("Party", "Failed to follow the party to %s: %v", target.Name, err)
+		}
+	}()
+}
+
+// handlePlayerSeen invites allowed players that come into view while we lead a party
+func (p *PartyManager) handlePlayerSeen(player *Player) {
+	if player.Name == "" {
+		return
+	}
+	name := strings.ToLower(player.Name)
+
+	p.mu.Lock()
+	invite := p.party != nil && p.party.Leader && p.allowed[name] &&
+		!p.isMember(player.Name) && time.Since(p.invited[name]) > partyReinviteDelay
+	if invite {
+		p.invited[name] = time.Now()
+	}
+	p.mu.Unlock()
+
+	if invite {
+		go func() {
+			if err := p.Invite(player.Name); err != nil {
+				p.client.logger.Warning("Party", "Failed to invite %s: %v", player.Name, err)
+			}
+		}()
+	}
+}
+
+// takeInvite removes and returns a pending invite
+func (p *PartyManager) takeInvite(inviter string) (uint32, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.party != nil {
+		return 0, ErrAlreadyInParty
+	}
+	partyID, ok := p.invites[strings.ToLower(inviter)]
+	if !ok {
+		return 0, ErrNoPartyInvite
+	}
+	delete(p.invites, strings.ToLower(inviter))
+	return partyID, nil
+}
+
+// respond answers a party invite
+func (p *PartyManager) respond(partyID uint32, answer client.AcceptDecline) error {
+	response := client.NewPartyInviteResponse()
+	response.PartyID = partyID
+	response.AcceptInvite = answer
+	return p.client.Send(response)
+}
+
+// sendAction asks the server to apply a party action to a player
+func (p *PartyManager) sendAction(playerID int32, action client.PartyActionID) error {
+	if playerID < 0 || playerID > maxPartyPlayerID {
+		return fmt.Errorf("player %d does not fit in a party action", playerID)
+	}
+	packet := client.NewPartyActionResult()
+	packet.PlayerID = uint16(playerID)
+	packet.ActionID = action
+	return p.client.Send(packet)
+}
+
+// member returns a party member by name
+func (p *PartyManager) member(name string) (PartyMember, error) {
+	party := p.Current()
+	if party == nil {
+		return PartyMember{}, ErrNotInParty
+	}
+	for _, member := range party.Members {
+		if strings.EqualFold(member.Name, name) {
+			return member, nil
+		}
+	}
+	return PartyMember{}, fmt.Errorf("%s is not in the party", name)
+}
+
+// isMember reports whether a player is in the party, p.mu must be held
+func (p *PartyManager) isMember(name string) bool {
+	for _, member := range p.party.Members {
+		if strings.EqualFold(member.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// leave forgets the current party
+func (p *PartyManager) leave() {
+	p.mu.Lock()
+	p.party = nil
+	p.mu.Unlock()
+}
+
+// waitJoined waits until the server sends the party member list
+func (p *PartyManager) waitJoined() error {
+	select {
+	case <-p.joined:
+		return nil
+	case <-time.After(p.Timeout):
+		return ErrPartyTimeout
+	}
+}
+
+// drainJoined discards a join signal left over from an earlier operation
+func (p *PartyManager) drainJoined() {
+	select {
+	case <-p.joined:
+	default:
+	}
+}
+
+// eventData snapshots the party for event payloads, p.mu must be held
+func (p *PartyManager) eventData() *events.PartyEventData {
+	data := &events.PartyEventData{}
+	if p.party == nil {
+		return data
+	}
+	data.PartyID = p.party.ID
+	for _, member := range p.party.Members {
+		data.Members = append(data.Members, member.Name)
+	}
+	return data
+}
+
+// findPlayer returns a player in view by name
+func (c *Client) findPlayer(name string) *Player {
+	for _, player := range c.Snapshot().Players {
+		if strings.EqualFold(player.Name, name) {
+			return player
+		}
+	}
+	return nil
+}
+
+// serverByHost returns the known server with an address or DNS name, or a
+// server for the bare host if none matches
+func serverByHost(host string) *models.Server {
+	for _, server := range models.CachedServers {
+		if server.Address == host || server.DNS == host {
+			return server
+		}
+	}
+	return &models.Server{Name: host, Address: host}
+}
+
+// inviteStateName describes an invite state for error messages
+func inviteStateName(state server.InviteState) string {
+	switch state {
+	case server.InviteStateCancelled:
+		return "cancelled"
+	case server.InviteStateDeclined:
+		return "declined"
+	case server.InviteStatePartyFull:
+		return "party is full"
+	case server.InviteStateBlacklisted:
+		return "blacklisted"
+	default:
+		return fmt.Sprintf("state %d", state)
+	}
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+)
+
+// memberNames lists the names of the current party members
+func memberNames(p *PartyManager) []string {
+	party := p.Current()
+	if party == nil {
+		return nil
+	}
+	var names []string
+	for _, member := range party.Members {
+		names = append(names, member.Name)
+	}
+	return names
+}
+
+func TestPartyMembership(t *testing.T) {
+	const self = 1
+	c := startTestClient(t)
+	got := recordEvents(c, events.EventPartyJoined, events.EventPartyUpdate, events.EventPartyLeft)
+	p := c.Party()
+
+	steps := []struct {
+		name       string
+		apply      func()
+		wantEvent  events.EventType
+		wantNames  []string
+		wantLeader bool
+	}{
+		{
+			name: "member list",
+			apply: func() {
+				p.handleMemberInfo(&server.IncomingPartyMemberInfo{PartyId: 7, MaxSize: 6, PartyPlayer: []server.PartyPlayer{
+					{Name: "Self", ObjectId: self}, {Name: "Alt", ObjectId: 2}, {Name: "Other", ObjectId: 3},
+				}})
+			},
+			wantEvent: events.EventPartyJoined,
+			wantNames: []string{"Self", "Alt", "Other"},
+		},
+		{
+			name:      "member added",
+			apply:     func() { p.handleMemberAdded(&server.PartyMemberAdded{PlayerId: 4, Name: "New"}) },
+			wantEvent: events.EventPartyUpdate,
+			wantNames: []string{"Self", "Alt", "Other", "New"},
+		},
+		{
+			name:      "member kicked",
+			apply:     func() { p.handleAction(&server.PartyAction{PlayerId: 3, ActionId: server.PartyActionKicked}) },
+			wantEvent: events.EventPartyUpdate,
+			wantNames: []string{"Self", "Alt", "New"},
+		},
+		{
+			name: "promoted",
+			apply: func() {
+				p.handleAction(&server.PartyAction{PlayerId: self, ActionId: server.PartyActionPromotedToLeader})
+			},
+			wantEvent:  events.EventPartyUpdate,
+			wantNames:  []string{"Self", "Alt", "New"},
+			wantLeader: true,
+		},
+		{
+			name: "same party list keeps the leader",
+			apply: func() {
+				p.handleMemberInfo(&server.IncomingPartyMemberInfo{PartyId: 7, PartyPlayer: []server.PartyPlayer{{Name: "Self", ObjectId: self}}})
+			},
+			wantEvent:  events.EventPartyUpdate,
+			wantNames:  []string{"Self"},
+			wantLeader: true,
+		},
+		{
+			name:      "left",
+			apply:     func() { p.handleAction(&server.PartyAction{PlayerId: self, ActionId: server.PartyActionLeftParty}) },
+			wantEvent: events.EventPartyLeft,
+		},
+	}
+	if !c.run(func() { c.state.ObjectID = self }) {
+		t.Fatal("state goroutine did not run the setup")
+	}
+	for _, step := range steps {
+		*got = nil
+		if !c.run(step.apply) {
+			t.Fatalf("%s: state goroutine did not handle the packet", step.name)
+		}
+		if len(*got) != 1 || (*got)[0].Type != step.wantEvent {
+			t.Fatalf("%s: got %d events, want one %v", step.name, len(*got), step.wantEvent)
+		}
+		names := memberNames(p)
+		if len(names) != len(step.wantNames) {
+			t.Fatalf("%s: members %v, want %v", step.name, names, step.wantNames)
+		}
+		for i := range names {
+			if names[i] != step.wantNames[i] {
+				t.Fatalf("%s: members %v, want %v", step.name, names, step.wantNames)
+			}
+		}
+		if party := p.Current(); party != nil && party.Leader != step.wantLeader {
+			t.Fatalf("%s: leader %v, want %v", step.name, party.Leader, step.wantLeader)
+		}
+	}
+}
+
+func TestPartySendActionRange(t *testing.T) {
+	p := startTestClient(t).Party()
+	tests := []struct {
+		playerID int32
+		wantErr  bool
+	}{
+		{playerID: 0},
+		{playerID: maxPartyPlayerID},
+		{playerID: -1, wantErr: true},
+		{playerID: maxPartyPlayerID + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := p.sendAction(tt.playerID, client.PartyActionKicked); (err != nil) != tt.wantErr {
+			t.Errorf("sendAction(%d) = %v, want error: %v", tt.playerID, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPartyInvites(t *testing.T) {
+	p := startTestClient(t).Party()
+	p.handleInvite(&server.IncomingPartyInvite{PartyId: 9, InviterName: "Stranger"})
+
+	if err := p.Decline("stranger"); err != nil {
+		t.Fatalf("Decline failed: %v", err)
+	}
+	if err := p.Decline("Stranger"); !errors.Is(err, ErrNoPartyInvite) {
+		t.Fatalf("second Decline = %v, want ErrNoPartyInvite", err)
+	}
+	if err := p.Leave(); !errors.Is(err, ErrNotInParty) {
+		t.Fatalf("Leave outside a party = %v, want ErrNotInParty", err)
+	}
+}
+
+func TestPartyListPages(t *testing.T) {
+	p := startTestClient(t).Party()
+	p.handleList(&server.PartyList{PacketNumber: 0, Parties: []*dataobjects.PartyInfo{{ID: 1}}})
+	p.handleList(&server.PartyList{PacketNumber: 1, Parties: []*dataobjects.PartyInfo{{ID: 2}}})
+	if listings := p.Listings(); len(listings) != 2 {
+		t.Fatalf("got %d listings after two pages, want 2", len(listings))
+	}
+	p.handleList(&server.PartyList{PacketNumber: 0, Parties: []*dataobjects.PartyInfo{{ID: 3}}})
+	if listings := p.Listings(); len(listings) != 1 || listings[0].ID != 3 {
+		t.Fatalf("a new first page did not replace the list: %v", listings)
+	}
+}
+
+func TestServerByHost(t *testing.T) {
+	previous := models.CachedServers
+	models.CachedServers = models.ServerList{
+		"USWest": {Name: "USWest", Address: "1.2.3.4", DNS: "uswest.example.com"},
+	}
+	t.Cleanup(func() { models.CachedServers = previous })
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "1.2.3.4", want: "USWest"},
+		{host: "uswest.example.com", want: "USWest"},
+		{host: "5.6.7.8", want: "5.6.7.8"},
+	}
+	for _, tt := range tests {
+		if got := serverByHost(tt.host); got.Name != tt.want {
+			t.Errorf("serverByHost(%q) = %s, want %s", tt.host, got.Name, tt.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+	"gorelay/pkg/xmldata"
+)
+
+// Portal reach and travel timing
+const (
+	// PortalRange is how close the player must be to a portal to use it
+	PortalRange float32 = 1.0
+
+	// travelPollInterval is how often walking progress is checked
+	travelPollInterval = 100 * time.Millisecond
+)
+
+// Travel errors
+var (
+	ErrTravelTimeout  = errors.New("timed out waiting for the map change")
+	ErrWalkTimeout    = errors.New("timed out walking to the portal")
+	ErrNoPortal       = errors.New("no matching portal in view")
+	ErrPortalLocked   = errors.New("portal is locked")
+	ErrPortalInactive = errors.New("portal is not active")
+)
+
+// isPortal reports whether an object definition is a portal
+func isPortal(obj *xmldata.GameObject) bool {
+	return obj.Class == "Portal" || obj.Class == "GuildHallPortal"
+}
+
+// trackPortal starts tracking a portal that came into view
+func (c *Client) trackPortal(entity *dataobjects.Entity, obj *xmldata.GameObject, pos *WorldPosData) {
+	portal := &models.Portal{
+		Name:        xmldata.GetObjectName(int(entity.ObjectType)),
+		Nexus:       obj.NexusPortal != nil,
+		Locked:      obj.LockedPortal != nil,
+		Active:      true,
+		DungeonName: obj.DungeonName,
+	}
+	portal.ObjectID = entity.Status.ObjectID
+	portal.ObjectType = int32(entity.ObjectType)
+	portal.Position.X = pos.X
+	portal.Position.Y = pos.Y
+	portal.LastMove = time.Now()
+	applyPortalStats(portal, entity.Status.Data)
+
+	c.portals[portal.ObjectID] = portal
+}
+
+// applyPortalStats applies the name and active state sent for a portal
+func applyPortalStats(portal *models.Portal, stats []*dataobjects.StatData) {
+	for _, stat := range stats {
+		switch models.StatType(stat.ID) {
+		case models.NAMESTAT:
+			if stat.StringValue != "" {
+				portal.Name = stat.StringValue
+			}
+		case models.ACTIVESTAT:
+			portal.Active = stat.IntValue != 0
+		}
+	}
+}
+
+// Portals returns the portals in view from the latest snapshot, closest first
+func (c *Client) Portals() []*models.Portal {
+	snap := c.Snapshot()
+	portals := make([]*models.Portal, 0, len(snap.Portals))
+	for _, portal := range snap.Portals {
+		portals = append(portals, portal)
+	}
+	sort.Slice(portals, func(i, j int) bool {
+		return portalDistance(portals[i], &snap.Position) < portalDistance(portals[j], &snap.Position)
+	})
+	return portals
+}
+
+// FindPortal returns the closest portal whose dungeon or name matches, or nil.
+// Names sent by the server may carry a player count, so they match by prefix.
+func (c *Client) FindPortal(name string) *models.Portal {
+	name = strings.ToLower(name)
+	for _, portal := range c.Portals() {
+		if strings.ToLower(portal.DungeonName) == name || strings.HasPrefix(strings.ToLower(portal.Name), name) {
+			return portal
+		}
+	}
+	return nil
+}
+
+// portalDistance returns the distance from a position to a portal
+func portalDistance(portal *models.Portal, pos *WorldPosData) float32 {
+	return pos.DistanceTo(&WorldPosData{X: portal.Position.X, Y: portal.Position.Y})
+}
+
+// Travel moves the player between maps. Trips block until the server sent the
+// player to the new map, so they must not be called from packet handlers or
+// synchronous event handlers.
+type Travel struct {
+	Timeout     time.Duration // for each of the Reconnect and MapInfo packets
+	WalkTimeout time.Duration
+	// Finder routes walks to portals, they go in a straight line when nil
+	Finder PathFinder
+
+	client     *Client
+	opMu       sync.Mutex // serializes trips
+	reconnects chan *server.Reconnect
+	maps       chan *server.MapInfo
+}
+
+// newTravel creates the travel manager for a client
+func newTravel(c *Client) *Travel {
+	return &Travel{
+		Timeout:     10 * time.Second,
+		WalkTimeout: 30 * time.Second,
+		client:      c,
+		reconnects:  make(chan *server.Reconnect, 1),
+		maps:        make(chan *server.MapInfo, 1),
+	}
+}
+
+// Travel returns the client's travel manager
+func (c *Client) Travel() *Travel {
+	return c.travel
+}
+
+// Enter walks to the closest portal matching a name or dungeon and uses it
+func (t *Travel) Enter(name string) error {
+	portal := t.client.FindPortal(name)
+	if portal == nil {
+		return fmt.Errorf("%w: %s", ErrNoPortal, name)
+	}
+	if err := t.WalkTo(portal.ObjectID); err != nil {
+		return err
+	}
+	return t.UsePortal(portal.ObjectID)
+}
+
+// WalkTo moves the player next to a portal
+func (t *Travel) WalkTo(objectID int32) error {
+	portal, ok := t.client.Snapshot().Portals[objectID]
+	if !ok {
+		return ErrNoPortal
+	}
+	target := &WorldPosData{X: portal.Position.X, Y: portal.Position.Y}
+
+	path := []*WorldPosData{target}
+	if t.Finder != nil {
+		if pos := t.client.GetPosition(); pos != nil {
+			if found := t.Finder.FindPathWorld(pos, target); len(found) > 0 {
+				path = append(found, target)
+			}
+		}
+	}
+	t.client.ClearPath()
+	t.client.AddPath(path)
+
+	deadline := time.Now().Add(t.WalkTimeout)
+	for time.Now().Before(deadline) {
+		if _, ok := t.client.Snapshot().Portals[objectID]; !ok {
+			t.client.ClearPath()
+			return ErrNoPortal
+		}
+		if pos := t.client.GetPosition(); pos != nil && pos.DistanceTo(target) <= PortalRange {
+			return nil
+		}
+		time.Sleep(travelPollInterval)
+	}
+	t.client.ClearPath()
+	return ErrWalkTimeout
+}
+
+// UsePortal enters a portal in reach and waits until the new map is loaded
+func (t *Travel) UsePortal(objectID int32) error {
+	snap := t.client.Snapshot()
+	portal, ok := snap.Portals[objectID]
+	if !ok {
+		return ErrNoPortal
+	}
+	if portal.Locked {
+		return ErrPortalLocked
+	}
+	if !portal.Active {
+		return ErrPortalInactive
+	}
+	if portalDistance(portal, &snap.Position) > PortalRange {
+		return fmt.Errorf("portal %s is out of reach", portal.Name)
+	}
+
+	t.client.logger.Info("Travel", "Entering %s", portal.Name)
+	return t.travel(&client.UsePortal{ObjectId: objectID})
+}
+
+// Escape returns to the nexus and waits until it is loaded
+func (t *Travel) Escape() error {
+	t.client.logger.Info("Travel", "Returning to the nexus")
+	return t.travel(client.NewEscape())
+}
+
+// GoToQuestRoom goes to the daily quest room and waits until it is loaded
+func (t *Travel) GoToQuestRoom() error {
+	t.client.logger.Info("Travel", "Going to the quest room")
+	return t.travel(client.NewGoToQuestRoom())
+}
+
+// travel sends a packet that moves the player to another game and waits for
+// the server's Reconnect and the new map's MapInfo
+func (t *Travel) travel(packet packets.Packet) error {
+	t.opMu.Lock()
+	defer t.opMu.Unlock()
+
+	// Discard packets of earlier, abandoned trips
+	select {
+	case <-t.reconnects:
+	default:
+	}
+	select {
+	case <-t.maps:
+	default:
+	}
+
+	if err := t.client.Send(packet); err != nil {
+		return err
+	}
+
+	var reconnect *server.Reconnect
+	select {
+	case reconnect = <-t.reconnects:
+	case <-time.After(t.Timeout):
+		return ErrTravelTimeout
+	}
+
+	select {
+	case mapInfo := <-t.maps:
+		t.client.logger.Info("Travel", "Arrived in %s", mapInfo.Name)
+		return nil
+	case <-time.After(t.Timeout):
+		return fmt.Errorf("%w: no map after reconnecting to %s", ErrTravelTimeout, reconnect.Name)
+	}
+}
+
+// handleReconnect hands a Reconnect to the trip waiting for it
+func (t *Travel) handleReconnect(packet *server.Reconnect) {
+	select {
+	case t.reconnects <- packet:
+	default:
+	}
+}
+
+// handleMapInfo hands a MapInfo to the trip waiting for it
+func (t *Travel) handleMapInfo(packet *server.MapInfo) {
+	select {
+	case t.maps <- packet:
+	default:
+	}
+}
+
+// handleReconnect connects to the game the server sent the player to. The
+// connection is replaced from another goroutine, as the state goroutine ends
+// with the old connection.
+func (c *Client) handleReconnect(packet *server.Reconnect) {
+	c.logger.Info("Client", "Server sent us to %s (game %d)", packet.Name, packet.GameId)
+
+	c.mu.Lock()
+	c.gameTarget = packet
+	c.mu.Unlock()
+	c.travel.handleReconnect(packet)
+
+	go func() {
+		c.Disconnect()
+		if err := c.Connect(); err != nil {
+			c.logger.Error("Client", "Failed to join %s: %v", packet.Name, err)
+		}
+	}()
+}
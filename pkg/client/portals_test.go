@@ -0,0 +1,173 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+)
+
+// newTestPortal builds an active portal at x,0
+func newTestPortal(id int32, name, dungeon string, x float32) *models.Portal {
+	portal := &models.Portal{Name: name, DungeonName: dungeon, Active: true}
+	portal.ObjectID = id
+	portal.Position.X = x
+	return portal
+}
+
+// addPortals puts the player at 0,0 among the given portals
+func addPortals(t *testing.T, c *Client, portals ...*models.Portal) {
+	t.Helper()
+	if !c.run(func() {
+		c.setPosition(&WorldPosData{})
+		for _, portal := range portals {
+			c.portals[portal.ObjectID] = portal
+		}
+	}) {
+		t.Fatal("state goroutine did not add the portals")
+	}
+}
+
+func TestApplyPortalStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		stats      []*dataobjects.StatData
+		wantName   string
+		wantActive bool
+	}{
+		{name: "no stats", wantName: "Portal", wantActive: true},
+		{
+			name:       "name with player count",
+			stats:      []*dataobjects.StatData{{ID: dataobjects.StatsType(models.NAMESTAT), StringValue: "Snake Pit (3)"}},
+			wantName:   "Snake Pit (3)",
+			wantActive: true,
+		},
+		{
+			name:       "empty name",
+			stats:      []*dataobjects.StatData{{ID: dataobjects.StatsType(models.NAMESTAT)}},
+			wantName:   "Portal",
+			wantActive: true,
+		},
+		{
+			name:     "closed",
+			stats:    []*dataobjects.StatData{{ID: dataobjects.StatsType(models.ACTIVESTAT), IntValue: 0}},
+			wantName: "Portal",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal := &models.Portal{Name: "Portal", Active: true}
+			applyPortalStats(portal, tt.stats)
+			if portal.Name != tt.wantName || portal.Active != tt.wantActive {
+				t.Fatalf("portal %q active %v, want %q active %v", portal.Name, portal.Active, tt.wantName, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestFindPortal(t *testing.T) {
+	c := startTestClient(t)
+	addPortals(t, c,
+		newTestPortal(1, "Snake Pit (3)", "Snake Pit", 8),
+		newTestPortal(2, "Snake Pit (0)", "Snake Pit", 3),
+		newTestPortal(3, "Realm Portal", "", 5),
+	)
+
+	tests := []struct {
+		name string
+		want int32
+	}{
+		{name: "snake pit", want: 2},
+		{name: "SNAKE", want: 2},
+		{name: "realm", want: 3},
+		{name: "Pit"},
+	}
+	for _, tt := range tests {
+		portal := c.FindPortal(tt.name)
+		switch {
+		case tt.want == 0 && portal != nil:
+			t.Errorf("FindPortal(%q) = %q, want none", tt.name, portal.Name)
+		case tt.want != 0 && (portal == nil || portal.ObjectID != tt.want):
+			t.Errorf("FindPortal(%q) = %v, want portal %d", tt.name, portal, tt.want)
+		}
+	}
+}
+
+func TestUsePortalChecks(t *testing.T) {
+	locked := newTestPortal(2, "Locked", "", 0.5)
+	locked.Locked = true
+	inactive := newTestPortal(3, "Closed", "", 0.5)
+	inactive.Active = false
+
+	c := startTestClient(t)
+	addPortals(t, c, newTestPortal(1, "Far", "", 5), locked, inactive)
+
+	tests := []struct {
+		name     string
+		objectID int32
+		want     error
+	}{
+		{name: "not in view", objectID: 9, want: ErrNoPortal},
+		{name: "locked", objectID: 2, want: ErrPortalLocked},
+		{name: "inactive", objectID: 3, want: ErrPortalInactive},
+	}
+	for _, tt := range tests {
+		if err := c.Travel().UsePortal(tt.objectID); !errors.Is(err, tt.want) {
+			t.Errorf("%s: UsePortal() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if err := c.Travel().UsePortal(1); err == nil {
+		t.Error("used a portal out of reach")
+	}
+}
+
+func TestTravelWaitsForTheNewMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		reconnect bool
+		mapInfo   bool
+		wantErr   error
+	}{
+		{name: "arrived", reconnect: true, mapInfo: true},
+		{name: "no reconnect", wantErr: ErrTravelTimeout},
+		{name: "no map", reconnect: true, wantErr: ErrTravelTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			tr := c.Travel()
+			tr.Timeout = 50 * time.Millisecond
+
+			// The trip drains stale packets before it sends, so keep answering
+			stop := make(chan struct{})
+			answered := make(chan struct{})
+			go func() {
+				defer close(answered)
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					if tt.reconnect {
+						tr.handleReconnect(&server.Reconnect{Name: "Nexus"})
+					}
+					if tt.mapInfo {
+						tr.handleMapInfo(&server.MapInfo{Name: "Nexus"})
+					}
+					time.Sleep(time.Millisecond)
+				}
+			}()
+			err := tr.Escape()
+			close(stop)
+			<-answered
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Escape() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
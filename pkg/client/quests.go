@@ -0,0 +1,395 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+	"gorelay/pkg/xmldata"
+)
+
+// Quest errors
+var (
+	ErrQuestTimeout  = errors.New("timed out waiting for quest response")
+	ErrQuestNotFound = errors.New("quest not found")
+)
+
+// Quest is a daily quest together with what the player still needs for it
+type Quest struct {
+	*dataobjects.QuestData
+	Ready   bool    // the inventory holds every required item
+	Missing []int32 // required item types not in the inventory
+}
+
+// Mission is the last progress reported for a mission
+type Mission struct {
+	ID         int32
+	Progress   int32
+	Objectives []int32
+	UpdatedAt  time.Time
+}
+
+// QuestManager fetches daily quests, tracks mission progress and redeems or
+// claims rewards. Fetch, Redeem and ClaimMission block until the server
+// answers, so they must not be called from packet handlers or synchronous
+// event handlers.
+type QuestManager struct {
+	Timeout time.Duration
+
+	client *Client
+
+	mu               sync.Mutex
+	quests           []*dataobjects.QuestData
+	missions         map[int32]*Mission
+	nextRefreshPrice int16
+	fetchedAt        time.Time
+
+	opMu     sync.Mutex // serializes operations awaiting a response
+	fetched  chan *server.QuestFetchResponse
+	redeemed chan *server.QuestRedeemResponse
+	claimed  chan *server.ClaimMissionResult
+}
+
+// newQuestManager creates the quest manager for a client
+func newQuestManager(c *Client) *QuestManager {
+	return &QuestManager{
+		Timeout:  5 * time.Second,
+		client:   c,
+		missions: make(map[int32]*Mission),
+		fetched:  make(chan *server.QuestFetchResponse, 1),
+		redeemed: make(chan *server.QuestRedeemResponse, 1),
+		claimed:  make(chan *server.ClaimMissionResult, 1),
+	}
+}
+
+// Quests returns the client's quest manager
+func (c *Client) Quests() *QuestManager {
+	return c.quests
+}
+
+// Quests returns the last fetched quests with their requirements checked
+// against the current inventory
+func (q *QuestManager) Quests() []Quest {
+	q.mu.Lock()
+	data := append([]*dataobjects.QuestData(nil), q.quests...)
+	q.mu.Unlock()
+
+	quests := make([]Quest, 0, len(data))
+	for _, quest := range data {
+		_, missing := q.findRequirements(quest)
+		quests = append(quests, Quest{
+			QuestData: quest,
+			Ready:     !quest.Completed && len(missing) == 0,
+			Missing:   missing,
+		})
+	}
+	return quests
+}
+
+// Missions returns the progress of every mission seen this session
+func (q *QuestManager) Missions() []Mission {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	missions := make([]Mission, 0, len(q.missions))
+	for _, mission := range q.missions {
+		copied := *mission
+		copied.Objectives = append([]int32(nil), mission.Objectives...)
+		missions = append(missions, copied)
+	}
+	return missions
+}
+
+// Fetch asks the server for the current daily quests
+func (q *QuestManager) Fetch() ([]Quest, error) {
+	q.opMu.Lock()
+	defer q.opMu.Unlock()
+
+	select {
+	case <-q.fetched:
+	default:
+	}
+	if err := q.client.Send(client.NewQuestFetchAsk()); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-q.fetched:
+		return q.Quests(), nil
+	case <-time.After(q.Timeout):
+		return nil, ErrQuestTimeout
+	}
+}
+
+// Redeem hands in the items required by a quest
+func (q *QuestManager) Redeem(questID string) error {
+	quest := q.quest(questID)
+	if quest == nil {
+		return ErrQuestNotFound
+	}
+	if quest.Completed {
+		return fmt.Errorf("quest %s is already completed", quest.Name)
+	}
+	slots, missing := q.findRequirements(quest)
+	if len(missing) > 0 {
+		return fmt.Errorf("quest %s is missing %s", quest.Name, itemNames(missing))
+	}
+
+	q.opMu.Lock()
+	defer q.opMu.Unlock()
+
+	select {
+	case <-q.redeemed:
+	default:
+	}
+	redeem := client.NewQuestRedeem()
+	redeem.QuestID = quest.ID
+	redeem.Slots = slots
+	redeem.ItemIDs = append([]int32(nil), quest.Requirements...)
+	if err := q.client.Send(redeem); err != nil {
+		return err
+	}
+
+	select {
+	case result := <-q.redeemed:
+		if !result.Success {
+			return fmt.Errorf("quest %s was not redeemed: %s", quest.Name, result.Message)
+		}
+		q.markCompleted(quest.ID)
+		return nil
+	case <-time.After(q.Timeout):
+		return ErrQuestTimeout
+	}
+}
+
+// RedeemReady redeems every quest whose requirements are in the inventory and
+// returns the IDs of the quests redeemed
+func (q *QuestManager) RedeemReady() ([]string, error) {
+	var redeemed []string
+	for _, quest := range q.Quests() {
+		if !quest.Ready {
+			continue
+		}
+		if err := q.Redeem(quest.ID); err != nil {
+			return redeemed, err
+		}
+		redeemed = append(redeemed, quest.ID)
+	}
+	return redeemed, nil
+}
+
+// ClaimMission claims the reward of a completed mission
+func (q *QuestManager) ClaimMission(missionID int32, missionType, category byte, subCategory uint16) error {
+	q.opMu.Lock()
+	defer q.opMu.Unlock()
+
+	select {
+	case <-q.claimed:
+	default:
+	}
+	claim := client.NewClaimMission()
+	claim.MissionID = missionID
+	claim.MissionType = missionType
+	claim.Category = category
+	claim.SubCategory = subCategory
+	if err := q.client.Send(claim); err != nil {
+		return err
+	}
+
+	select {
+	case result := <-q.claimed:
+		if !result.Success {
+			return fmt.Errorf("mission %d was not claimed: %s", missionID, result.Message)
+		}
+		return nil
+	case <-time.After(q.Timeout):
+		return ErrQuestTimeout
+	}
+}
+
+// ClaimDailyReward claims a daily login reward. The server does not answer
+// this packet, rewards arrive as regular inventory or gift chest updates.
+func (q *QuestManager) ClaimDailyReward(claimKey, claimType string) error {
+	claim := client.NewClaimDailyReward()
+	claim.ClaimKey = claimKey
+	claim.ClaimType = claimType
+	return q.client.Send(claim)
+}
+
+// quest returns a fetched quest by ID
+func (q *QuestManager) quest(id string) *dataobjects.QuestData {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, quest := range q.quests {
+		if quest.ID == id {
+			return quest
+		}
+	}
+	return nil
+}
+
+// markCompleted marks a fetched quest completed. Quest data is shared with
+// readers and the fetch packet, so the list and entry are replaced, not
+// modified.
+func (q *QuestManager) markCompleted(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	quests := append([]*dataobjects.QuestData(nil), q.quests...)
+	for i, quest := range quests {
+		if quest.ID == id {
+			copied := *quest
+			copied.Completed = true
+			quests[i] = &copied
+		}
+	}
+	q.quests = quests
+}
+
+// findRequirements picks an inventory slot for every required item and
+// returns the required item types that could not be found
+func (q *QuestManager) findRequirements(quest *dataobjects.QuestData) ([]int32, []int32) {
+	items := q.client.inventory.Items()
+	used := make(map[int32]bool)
+
+	var slots, missing []int32
+	for _, required := range quest.Requirements {
+		found := false
+		for _, item := range items {
+			if item.Kind == SlotEquipment || used[item.Slot] || item.ItemType != required {
+				continue
+			}
+			used[item.Slot] = true
+			slots = append(slots, item.Slot)
+			found = true
+			break
+		}
+		if !found {
+			missing = append(missing, required)
+		}
+	}
+	return slots, missing
+}
+
+// handleFetchResponse stores fetched quests and redeems ready ones if configured
+func (q *QuestManager) handleFetchResponse(packet *server.QuestFetchResponse) {
+	q.mu.Lock()
+	q.quests = packet.Quests
+	q.nextRefreshPrice = packet.NextRefreshPrice
+	q.fetchedAt = time.Now()
+	q.mu.Unlock()
+
+	q.client.logger.Debug("Quests", "Received %d quests", len(packet.Quests))
+	select {
+	case q.fetched <- packet:
+	default:
+	}
+	q.client.emit(events.EventQuestsFetched, packet, q.eventData())
+
+	if q.client.config.Quests.AutoRedeem {
+		go func() {
+			redeemed, err := q.RedeemReady()
+			if err != nil {
+				q.client.logger.Warning("Quests", "Failed to redeem quests: %v", err)
+			}
+			for _, id := range redeemed {
+				q.client.logger.Success("Quests", "Redeemed quest %s", id)
+			}
+		}()
+	}
+}
+
+// handleRedeemResponse hands a QuestRedeemResponse to the waiting Redeem call
+func (q *QuestManager) handleRedeemResponse(packet *server.QuestRedeemResponse) {
+	select {
+	case q.redeemed <- packet:
+	default:
+	}
+	q.client.emit(events.EventQuestRedeemed, packet, &events.QuestEventData{
+		Success: packet.Success,
+		Message: packet.Message,
+	})
+}
+
+// handleMissionProgress records mission progress
+func (q *QuestManager) handleMissionProgress(packet *server.MissionProgressUpdate) {
+	q.recordMission(packet, packet.MissionId, packet.Progress, packet.Objectives)
+}
+
+// handleMultipleMissionsProgress passes the progress of several missions on
+// to listeners. The payload format is not known yet, so it is not decoded and
+// listeners get the raw string.
+func (q *QuestManager) handleMultipleMissionsProgress(packet *server.MultipleMissionsProgressUpdate) {
+	q.client.logger.Debug("Quests", "Multiple missions progress: %s", packet.UnknownString)
+	q.client.emit(events.EventMissionProgress, packet, &events.MissionEventData{
+		Raw: packet.UnknownString,
+	})
+}
+
+// recordMission stores the progress of a mission and notifies listeners
+func (q *QuestManager) recordMission(packet packets.Packet, id, progress int32, objectives []int32) {
+	q.mu.Lock()
+	q.missions[id] = &Mission{
+		ID:         id,
+		Progress:   progress,
+		Objectives: append([]int32(nil), objectives...),
+		UpdatedAt:  time.Now(),
+	}
+	q.mu.Unlock()
+
+	q.client.emit(events.EventMissionProgress, packet, &events.MissionEventData{
+		MissionID:  id,
+		Progress:   progress,
+		Objectives: append([]int32(nil), objectives...),
+	})
+}
+
+// handleClaimResult hands a ClaimMissionResult to the waiting ClaimMission call
+func (q *QuestManager) handleClaimResult(packet *server.ClaimMissionResult) {
+	select {
+	case q.claimed <- packet:
+	default:
+	}
+	q.client.emit(events.EventMissionClaimed, packet, &events.MissionEventData{
+		Success: packet.Success,
+		Message: packet.Message,
+	})
+}
+
+// handleReset refreshes the quests after the daily reset if configured
+func (q *QuestManager) handleReset(packet *server.ResetDailyQuests) {
+	q.mu.Lock()
+	q.quests = nil
+	q.mu.Unlock()
+
+	q.client.logger.Info("Quests", "Daily quests were reset")
+	if q.client.config.Quests.AutoFetch {
+		go func() {
+			if _, err := q.Fetch(); err != nil {
+				q.client.logger.Warning("Quests", "Failed to fetch quests: %v", err)
+			}
+		}()
+	}
+}
+
+// eventData summarizes the fetched quests for event payloads
+func (q *QuestManager) eventData() *events.QuestEventData {
+	data := &events.QuestEventData{Success: true}
+	for _, quest := range q.Quests() {
+		summary := events.QuestSummary{
+			ID:        quest.ID,
+			Name:      quest.Name,
+			Completed: quest.Completed,
+			Ready:     quest.Ready,
+		}
+		for _, item := range quest.Requirements {
+			summary.Requirements = append(summary.Requirements, xmldata.GetObjectName(int(item)))
+		}
+		data.Quests = append(data.Quests, summary)
+	}
+	return data
+}
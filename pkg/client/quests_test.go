@@ -0,0 +1,80 @@
+package client
+
+import (
+	"testing"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+)
+
+func TestQuestReadiness(t *testing.T) {
+	c := startTestClient(t)
+	setSlot(t, c, 0, testStaff) // equipped items are never handed in
+	setSlot(t, c, 4, testRing)
+	setSlot(t, c, 9, testPotion)
+
+	tests := []struct {
+		name        string
+		quest       dataobjects.QuestData
+		wantReady   bool
+		wantMissing int
+	}{
+		{name: "items in inventory", quest: dataobjects.QuestData{Requirements: []int32{testRing, testPotion}}, wantReady: true},
+		{name: "equipped item", quest: dataobjects.QuestData{Requirements: []int32{testStaff}}, wantMissing: 1},
+		{name: "second copy missing", quest: dataobjects.QuestData{Requirements: []int32{testRing, testRing}}, wantMissing: 1},
+		{name: "already completed", quest: dataobjects.QuestData{Requirements: []int32{testRing}, Completed: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quest := tt.quest
+			c.Quests().handleFetchResponse(&server.QuestFetchResponse{Quests: []*dataobjects.QuestData{&quest}})
+			got := c.Quests().Quests()[0]
+			if got.Ready != tt.wantReady || len(got.Missing) != tt.wantMissing {
+				t.Fatalf("quest ready %v missing %v, want ready %v with %d missing",
+					got.Ready, got.Missing, tt.wantReady, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestMarkCompletedKeepsFetchedData(t *testing.T) {
+	q := startTestClient(t).Quests()
+	packet := &server.QuestFetchResponse{Quests: []*dataobjects.QuestData{{ID: "a"}, {ID: "b"}}}
+	q.handleFetchResponse(packet)
+	before := q.Quests()
+
+	q.markCompleted("b")
+	if packet.Quests[1].Completed || before[1].Completed {
+		t.Fatal("markCompleted changed quest data shared with the packet or earlier readers")
+	}
+	after := q.Quests()
+	if after[0].Completed || !after[1].Completed {
+		t.Fatalf("completed flags %v %v, want only b completed", after[0].Completed, after[1].Completed)
+	}
+}
+
+func TestMissionProgress(t *testing.T) {
+	c := startTestClient(t)
+	got := recordEvents(c, events.EventMissionProgress)
+	q := c.Quests()
+
+	objectives := []int32{1, 2}
+	q.handleMissionProgress(&server.MissionProgressUpdate{MissionId: 5, Progress: 3, Objectives: objectives})
+	objectives[0] = 9
+	missions := q.Missions()
+	if len(missions) != 1 || missions[0].ID != 5 || missions[0].Progress != 3 || missions[0].Objectives[0] != 1 {
+		t.Fatalf("missions %+v, want mission 5 at progress 3", missions)
+	}
+
+	q.handleMultipleMissionsProgress(&server.MultipleMissionsProgressUpdate{UnknownString: `{"x":1}`})
+	if len(q.Missions()) != 1 {
+		t.Fatal("undecoded mission update was recorded as a mission")
+	}
+	if len(*got) != 2 {
+		t.Fatalf("got %d mission events, want 2", len(*got))
+	}
+	if data := (*got)[1].Data.(*events.MissionEventData); data.Raw != `{"x":1}` || data.MissionID != 0 {
+		t.Fatalf("multiple missions event %+v, want only the raw payload", data)
+	}
+}
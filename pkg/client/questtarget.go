@@ -0,0 +1,104 @@
+package client
+
+import (
+	"errors"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/server"
+)
+
+// Quest target errors
+var (
+	ErrNoQuest = errors.New("quest target is not in view")
+	ErrNoPath  = errors.New("no path to target")
+)
+
+// QuestTarget describes the object the server points the quest arrow at
+type QuestTarget struct {
+	ObjectID   int32
+	ObjectType int32
+	Name       string
+	Position   *WorldPosData
+	Distance   float32 // from the player, in tiles
+	Enemy      *Enemy  // nil while the object is not in view
+}
+
+// PathFinder finds a walkable path between two world positions. It is
+// implemented by the pathfinding service's Pathfinder.
+type PathFinder interface {
+	FindPathWorld(start, end *WorldPosData) []*WorldPosData
+}
+
+// QuestID returns the object ID of the current quest, or 0 if there is none
+func (c *Client) QuestID() int32 {
+	return c.Snapshot().QuestID
+}
+
+// QuestTarget returns the current quest resolved from the tracked objects, or
+// nil if the server has not pointed us at a quest
+func (c *Client) QuestTarget() *QuestTarget {
+	snap := c.Snapshot()
+	if snap.QuestID == 0 {
+		return nil
+	}
+
+	target := &QuestTarget{ObjectID: snap.QuestID}
+	if enemy, ok := snap.Enemies[snap.QuestID]; ok {
+		target.Enemy = enemy
+		target.ObjectType = enemy.ObjectType
+		target.Name = enemy.Name
+		target.Position = enemy.Position
+		if enemy.Position != nil && !snap.Position.IsZero() {
+			target.Distance = snap.Position.DistanceTo(enemy.Position)
+		}
+	}
+	return target
+}
+
+// PathToQuest replaces the movement path with a path towards the quest
+func (c *Client) PathToQuest(finder PathFinder) error {
+	target := c.QuestTarget()
+	if target == nil || target.Position == nil {
+		return ErrNoQuest
+	}
+	pos := c.GetPosition()
+	if pos == nil {
+		return ErrNoPath
+	}
+
+	path := finder.FindPathWorld(pos, target.Position)
+	if len(path) == 0 {
+		return ErrNoPath
+	}
+	c.ClearPath()
+	c.AddPath(path)
+	c.logger.Info("Client", "Moving to quest %s (%.1f tiles, %d steps)", target.Name, target.Distance, len(path))
+	return nil
+}
+
+// handleQuestObjectID records the object the quest arrow now points at
+func (c *Client) handleQuestObjectID(packet *server.QuestObjectId) {
+	if packet.ObjectId == c.questID {
+		return
+	}
+	c.questID = packet.ObjectId
+
+	data := &events.QuestTargetEventData{ObjectID: packet.ObjectId}
+	if enemy, ok := c.enemies[packet.ObjectId]; ok {
+		data.ObjectType = enemy.ObjectType
+		data.Name = enemy.Name
+		if enemy.Position != nil {
+			data.Position = events.Position{X: enemy.Position.X, Y: enemy.Position.Y}
+			if c.state.WorldPos != nil && !c.state.WorldPos.IsZero() {
+				data.Distance = c.state.WorldPos.DistanceTo(enemy.Position)
+			}
+		}
+	}
+
+	if data.Name != "" {
+		c.logger.Info("Client", "Quest changed to %s (%d), %.1f tiles away", data.Name, data.ObjectID, data.Distance)
+	} else {
+		c.logger.Info("Client", "Quest changed to object %d", data.ObjectID)
+	}
+	c.emit(events.EventQuestChanged, packet, data)
+}
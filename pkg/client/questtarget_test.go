@@ -0,0 +1,126 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/server"
+)
+
+// fakeFinder returns a fixed path and records what it was asked for
+type fakeFinder struct {
+	path       []*WorldPosData
+	start, end *WorldPosData
+}
+
+func (f *fakeFinder) FindPathWorld(start, end *WorldPosData) []*WorldPosData {
+	f.start, f.end = start, end
+	return f.path
+}
+
+// setQuest points the quest arrow at objectID with the player at 1,1 and a
+// quest enemy at 4,5 when inView is set
+func setQuest(t *testing.T, c *Client, objectID int32, inView bool) {
+	t.Helper()
+	if !c.run(func() {
+		c.setPosition(&WorldPosData{X: 1, Y: 1})
+		if inView {
+			c.enemies[objectID] = &Enemy{ObjectID: objectID, ObjectType: 0x0d01, Name: "Boss", Position: &WorldPosData{X: 4, Y: 5}}
+		}
+		c.handleQuestObjectID(&server.QuestObjectId{ObjectId: objectID})
+	}) {
+		t.Fatal("state goroutine did not set the quest")
+	}
+}
+
+func TestQuestTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		questID   int32
+		inView    bool
+		wantNil   bool
+		wantName  string
+		wantDist  float32
+		wantEnemy bool
+	}{
+		{name: "no quest", wantNil: true},
+		{name: "out of view", questID: 7},
+		{name: "in view", questID: 7, inView: true, wantName: "Boss", wantDist: 5, wantEnemy: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			setQuest(t, c, tt.questID, tt.inView)
+
+			target := c.QuestTarget()
+			if (target == nil) != tt.wantNil {
+				t.Fatalf("QuestTarget() = %+v, want nil: %v", target, tt.wantNil)
+			}
+			if target == nil {
+				return
+			}
+			if target.ObjectID != tt.questID || target.Name != tt.wantName || target.Distance != tt.wantDist {
+				t.Fatalf("target %d %q at %v tiles, want %d %q at %v", target.ObjectID, target.Name, target.Distance,
+					tt.questID, tt.wantName, tt.wantDist)
+			}
+			if (target.Enemy != nil) != tt.wantEnemy {
+				t.Fatalf("target enemy %v, want one: %v", target.Enemy, tt.wantEnemy)
+			}
+		})
+	}
+}
+
+func TestQuestChangedEvents(t *testing.T) {
+	c := startTestClient(t)
+	got := recordEvents(c, events.EventQuestChanged)
+	setQuest(t, c, 7, true)
+	setQuest(t, c, 7, true)
+	setQuest(t, c, 8, false)
+
+	if len(*got) != 2 {
+		t.Fatalf("got %d quest events, want one per change", len(*got))
+	}
+	first := (*got)[0].Data.(*events.QuestTargetEventData)
+	if first.ObjectID != 7 || first.Name != "Boss" || first.Distance != 5 {
+		t.Fatalf("first quest event %+v, want Boss 5 tiles away", first)
+	}
+	if second := (*got)[1].Data.(*events.QuestTargetEventData); second.ObjectID != 8 || second.Name != "" {
+		t.Fatalf("second quest event %+v, want unnamed object 8", second)
+	}
+}
+
+func TestPathToQuest(t *testing.T) {
+	path := []*WorldPosData{{X: 2, Y: 2}, {X: 4, Y: 5}}
+	tests := []struct {
+		name    string
+		inView  bool
+		path    []*WorldPosData
+		wantErr error
+	}{
+		{name: "quest out of view", path: path, wantErr: ErrNoQuest},
+		{name: "no path", inView: true, wantErr: ErrNoPath},
+		{name: "path", inView: true, path: path},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			setQuest(t, c, 7, tt.inView)
+			c.AddPath([]*WorldPosData{{X: 9, Y: 9}})
+
+			finder := &fakeFinder{path: tt.path}
+			if err := c.PathToQuest(finder); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PathToQuest() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if *finder.start != (WorldPosData{X: 1, Y: 1}) || *finder.end != (WorldPosData{X: 4, Y: 5}) {
+				t.Fatalf("path asked from %+v to %+v", finder.start, finder.end)
+			}
+			if next := c.GetNextPosition(); next == nil || *next != *path[0] {
+				t.Fatalf("next position %+v, want the first step of the new path", next)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"time"
+
+	"gorelay/pkg/config"
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/server"
+)
+
+// QueueStatus describes the client's place in a server queue
+type QueueStatus struct {
+	Server      string
+	Position    int
+	MaxPosition int
+	EnteredAt   time.Time
+	UpdatedAt   time.Time
+
+	// Rate is how many positions the queue advanced per minute, 0 until known
+	Rate float64
+	// ETA estimates the time left in the queue from Rate, 0 until known
+	ETA time.Duration
+
+	startPosition int
+}
+
+// Waited returns how long the client has been in the queue
+func (q *QueueStatus) Waited() time.Duration {
+	return q.UpdatedAt.Sub(q.EnteredAt)
+}
+
+// QueueStatus returns the client's queue status, or nil when not queued
+func (c *Client) QueueStatus() *QueueStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.queue == nil {
+		return nil
+	}
+	status := *c.queue
+	return &status
+}
+
+// CancelQueue leaves the server queue
+func (c *Client) CancelQueue() error {
+	if err := c.Send(client.NewQueueCancel()); err != nil {
+		return err
+	}
+	c.leaveQueue(nil)
+	return nil
+}
+
+// handleQueue tracks the queue position and applies the configured queue policy
+func (c *Client) handleQueue(packet *server.Queue) {
+	now := time.Now()
+
+	c.mu.Lock()
+	status := c.queue
+	if status == nil {
+		status = &QueueStatus{
+			EnteredAt:     now,
+			startPosition: int(packet.CurrentPosition),
+		}
+		if c.server != nil {
+			status.Server = c.server.Name
+		}
+		c.queue = status
+	}
+	status.Position = int(packet.CurrentPosition)
+	status.MaxPosition = int(packet.MaxPosition)
+	status.UpdatedAt = now
+	if advanced := status.startPosition - status.Position; advanced > 0 {
+		status.Rate = float64(advanced) / status.Waited().Minutes()
+		status.ETA = time.Duration(float64(status.Position) / status.Rate * float64(time.Minute))
+	}
+	snapshot := *status
+	c.mu.Unlock()
+
+	c.logger.Debug("Client", "Queue position %d/%d on %s", snapshot.Position, snapshot.MaxPosition, snapshot.Server)
+	c.emit(events.EventQueueUpdate, packet, queueEventData(&snapshot))
+
+	c.applyQueuePolicy(&snapshot)
+}
+
+// applyQueuePolicy cancels the queue once the configured wait is exceeded
+func (c *Client) applyQueuePolicy(status *QueueStatus) {
+	policy := c.config.Queue.Policy
+	if policy == "" || policy == config.QueuePolicyWait {
+		return
+	}
+
+	maxWait := time.Duration(c.config.Queue.MaxWait) * time.Second
+	tooLong := maxWait > 0 && status.Waited() >= maxWait
+	tooFar := c.config.Queue.MaxPosition > 0 && status.Position > c.config.Queue.MaxPosition
+	if !tooLong && !tooFar {
+		return
+	}
+
+	if err := c.CancelQueue(); err != nil {
+		c.logger.Error("Client", "Failed to cancel queue: %v", err)
+		return
+	}
+
+	switch policy {
+	case config.QueuePolicySwitch:
+		models.MarkServerFailed(status.Server)
+		next := c.selectServer()
+		c.logger.Warning("Client", "Queue on %s at position %d after %v, switching to %s",
+			status.Server, status.Position, status.Waited().Round(time.Second), next.Name)
+		go func() {
+			if err := c.SwitchServer(next.Name); err != nil {
+				c.logger.Error("Client", "Failed to switch server after queue: %v", err)
+			}
+		}()
+	case config.QueuePolicyGiveUp:
+		c.logger.Warning("Client", "Giving up queue on %s at position %d after %v",
+			status.Server, status.Position, status.Waited().Round(time.Second))
+		go c.Disconnect()
+	}
+}
+
+// leaveQueue forgets the queue status once the client got in or cancelled
+func (c *Client) leaveQueue(packet packets.Packet) {
+	c.mu.Lock()
+	status := c.queue
+	c.queue = nil
+	c.mu.Unlock()
+	if status == nil {
+		return
+	}
+
+	data := queueEventData(status)
+	data.Position = 0
+	c.emit(events.EventQueueLeft, packet, data)
+}
+
+// queueEventData describes a queue status for event payloads
+func queueEventData(status *QueueStatus) *events.QueueEventData {
+	return &events.QueueEventData{
+		Server:      status.Server,
+		Position:    status.Position,
+		MaxPosition: status.MaxPosition,
+		Waited:      status.Waited(),
+		ETA:         status.ETA,
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"gorelay/pkg/config"
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/server"
+)
+
+func TestQueueTracksPosition(t *testing.T) {
+	c := startTestClient(t)
+	got := recordEvents(c, events.EventQueueUpdate, events.EventQueueLeft)
+
+	c.handleQueue(&server.Queue{CurrentPosition: 100, MaxPosition: 200})
+	status := c.QueueStatus()
+	if status == nil || status.Position != 100 || status.MaxPosition != 200 || status.Rate != 0 {
+		t.Fatalf("queue status %+v, want position 100/200 with no rate", status)
+	}
+
+	// Pretend we entered two minutes ago and advanced 20 positions since
+	c.mu.Lock()
+	c.queue.EnteredAt = c.queue.EnteredAt.Add(-2 * time.Minute)
+	c.mu.Unlock()
+	c.handleQueue(&server.Queue{CurrentPosition: 80, MaxPosition: 200})
+	status = c.QueueStatus()
+	if status.Rate < 9.9 || status.Rate > 10.1 {
+		t.Fatalf("queue rate %v per minute, want 10", status.Rate)
+	}
+	if eta := status.ETA.Round(time.Minute); eta != 8*time.Minute {
+		t.Fatalf("queue ETA %v, want 8m", eta)
+	}
+
+	c.leaveQueue(nil)
+	if c.QueueStatus() != nil {
+		t.Fatal("queue status kept after leaving the queue")
+	}
+	want := []events.EventType{events.EventQueueUpdate, events.EventQueueUpdate, events.EventQueueLeft}
+	if len(*got) != len(want) {
+		t.Fatalf("got %d queue events, want %v", len(*got), want)
+	}
+	for i := range want {
+		if (*got)[i].Type != want[i] {
+			t.Fatalf("event %d is %v, want %v", i, (*got)[i].Type, want[i])
+		}
+	}
+}
+
+func TestQueuePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		maxWait     int
+		maxPosition int
+		waited      time.Duration
+		position    uint16
+		wantCancel  bool
+	}{
+		{name: "wait ignores limits", policy: config.QueuePolicyWait, maxPosition: 10, position: 50},
+		{name: "no policy", maxPosition: 10, position: 50},
+		{name: "give up too far", policy: config.QueuePolicyGiveUp, maxPosition: 10, position: 50, wantCancel: true},
+		{name: "give up close enough", policy: config.QueuePolicyGiveUp, maxPosition: 10, position: 10},
+		{name: "give up waited too long", policy: config.QueuePolicyGiveUp, maxWait: 60, waited: 2 * time.Minute, position: 5, wantCancel: true},
+		{name: "give up within the wait", policy: config.QueuePolicyGiveUp, maxWait: 600, waited: 2 * time.Minute, position: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			c.handleQueue(&server.Queue{CurrentPosition: tt.position + 1})
+			c.mu.Lock()
+			c.queue.EnteredAt = c.queue.EnteredAt.Add(-tt.waited)
+			c.mu.Unlock()
+
+			c.config.Queue.Policy = tt.policy
+			c.config.Queue.MaxWait = tt.maxWait
+			c.config.Queue.MaxPosition = tt.maxPosition
+			c.handleQueue(&server.Queue{CurrentPosition: tt.position})
+
+			if cancelled := c.QueueStatus() == nil; cancelled != tt.wantCancel {
+				t.Fatalf("queue cancelled: %v, want %v", cancelled, tt.wantCancel)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+)
+
+// NexusMapName is the name of the map escapes lead to
+const NexusMapName = "Nexus"
+
+// Potion stacks are used through pseudo slots of the player object
+const (
+	healthPotionSlot int32 = 254
+	magicPotionSlot  int32 = 255
+	healthPotionType int32 = 0xa22
+	magicPotionType  int32 = 0xa23
+)
+
+// Safety timing, the server takes a moment to confirm potions and escapes
+const (
+	potionCooldown = 500 * time.Millisecond
+	escapeRetry    = 2 * time.Second
+)
+
+// safeMaps are maps without enemies, where the guard does nothing
+var safeMaps = map[string]bool{
+	NexusMapName:       true,
+	VaultMapName:       true,
+	"Guild Hall":       true,
+	"Daily Quest Room": true,
+	"Pet Yard":         true,
+}
+
+// Safety watches the player's health and mana. It drinks potions when they run
+// low and escapes to the nexus before the player dies. Thresholds are fractions
+// of the maximum, 0 turns an action off. Checks run on the state goroutine
+// whenever the player's stats change or damage is taken.
+type Safety struct {
+	NexusThreshold float32
+	HealThreshold  float32
+	ManaThreshold  float32
+
+	client     *Client
+	lastHeal   time.Time
+	lastMana   time.Time
+	lastEscape time.Time
+}
+
+// newSafety creates the safety guard for a client from the config thresholds
+func newSafety(c *Client) *Safety {
+	return &Safety{
+		NexusThreshold: c.config.AutoNexusThreshold,
+		HealThreshold:  c.config.AutoHealThreshold,
+		ManaThreshold:  c.config.AutoHealMP,
+		client:         c,
+	}
+}
+
+// Safety returns the client's safety guard
+func (c *Client) Safety() *Safety {
+	return c.safety
+}
+
+// check acts on the player's current health and mana
+func (s *Safety) check(packet packets.Packet) {
+	if data := s.client.state.PlayerData; data != nil {
+		s.checkHP(packet, data.HP)
+	}
+}
+
+// handleDamage acts on health the player is about to lose, before the server
+// reports it with the next stats
+func (s *Safety) handleDamage(packet packets.Packet, damage int32) {
+	if data := s.client.state.PlayerData; data != nil && damage > 0 {
+		s.checkHP(packet, data.HP-damage)
+	}
+}
+
+// checkHP drinks potions and escapes as the thresholds demand, taking hp as
+// the player's health
+func (s *Safety) checkHP(packet packets.Packet, hp int32) {
+	c := s.client
+	data := c.state.PlayerData
+	if data.MaxHP <= 0 || data.HP <= 0 || (c.currentMap != nil && safeMaps[c.currentMap.Name]) {
+		return
+	}
+
+	now := time.Now()
+	health := float32(hp) / float32(data.MaxHP)
+	if health < s.HealThreshold && data.Stats.HealthPotions > 0 && now.Sub(s.lastHeal) >= potionCooldown {
+		if s.drink(healthPotionSlot, healthPotionType) {
+			s.lastHeal = now
+			s.fire(packet, events.SafetyHeal, hp, s.HealThreshold)
+		}
+	}
+	if health < s.NexusThreshold && now.Sub(s.lastEscape) >= escapeRetry {
+		if err := c.Send(client.NewEscape()); err != nil {
+			c.logger.Error("Safety", "Failed to escape: %v", err)
+		} else {
+			s.lastEscape = now
+			s.fire(packet, events.SafetyNexus, hp, s.NexusThreshold)
+		}
+	}
+
+	if data.MaxMP <= 0 {
+		return
+	}
+	mana := float32(data.MP) / float32(data.MaxMP)
+	if mana < s.ManaThreshold && data.Stats.MagicPotions > 0 && now.Sub(s.lastMana) >= potionCooldown {
+		if s.drink(magicPotionSlot, magicPotionType) {
+			s.lastMana = now
+			s.fire(packet, events.SafetyMana, hp, s.ManaThreshold)
+		}
+	}
+}
+
+// drink uses a potion from one of the potion stacks
+func (s *Safety) drink(slot, itemType int32) bool {
+	c := s.client
+	if !c.CanUseItems() || c.state.WorldPos == nil {
+		return false
+	}
+	use := &client.UseItem{
+		Time:       c.clock.ClientTime(),
+		SlotObject: dataobjects.NewSlotObjectWithData(c.state.ObjectID, slot, itemType),
+		ItemUsePos: dataobjects.NewLocationWithCoords(float64(c.state.WorldPos.X), float64(c.state.WorldPos.Y)),
+	}
+	if err := c.Send(use); err != nil {
+		c.logger.Error("Safety", "Failed to drink potion: %v", err)
+		return false
+	}
+	return true
+}
+
+// fire logs an action taken by the guard and notifies listeners
+func (s *Safety) fire(packet packets.Packet, action events.SafetyAction, hp int32, threshold float32) {
+	c := s.client
+	data := c.state.PlayerData
+	switch action {
+	case events.SafetyNexus:
+		c.logger.Warning("Safety", "HP at %d/%d, below %.0f%%, escaping to the nexus", hp, data.MaxHP, threshold*100)
+	case events.SafetyHeal:
+		c.logger.Info("Safety", "HP at %d/%d, drinking a health potion", hp, data.MaxHP)
+	case events.SafetyMana:
+		c.logger.Info("Safety", "MP at %d/%d, drinking a magic potion", data.MP, data.MaxMP)
+	}
+	c.emit(events.EventSafetyTriggered, packet, &events.SafetyEventData{
+		Action:    action,
+		HP:        hp,
+		MaxHP:     data.MaxHP,
+		MP:        data.MP,
+		MaxMP:     data.MaxMP,
+		Threshold: threshold,
+	})
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"testing"
+
+	"gorelay/pkg/events"
+)
+
+func TestSafetyCheckHP(t *testing.T) {
+	tests := []struct {
+		name    string
+		hp, mp  int32
+		potions int32
+		mapName string
+		noPos   bool
+		want    []events.SafetyAction
+	}{
+		{name: "healthy", hp: 90, mp: 90, potions: 1},
+		{name: "low hp", hp: 45, mp: 90, potions: 1, want: []events.SafetyAction{events.SafetyHeal}},
+		{name: "low hp without potions", hp: 45, mp: 90},
+		{name: "very low hp", hp: 15, mp: 90, potions: 1, want: []events.SafetyAction{events.SafetyHeal, events.SafetyNexus}},
+		{name: "very low hp without potions", hp: 15, mp: 90, want: []events.SafetyAction{events.SafetyNexus}},
+		{name: "low mp", hp: 90, mp: 10, potions: 1, want: []events.SafetyAction{events.SafetyMana}},
+		{name: "everything low", hp: 15, mp: 10, potions: 1, want: []events.SafetyAction{events.SafetyHeal, events.SafetyNexus, events.SafetyMana}},
+		{name: "no position to drink at", hp: 15, mp: 10, potions: 1, noPos: true, want: []events.SafetyAction{events.SafetyNexus}},
+		{name: "safe map", hp: 15, mp: 10, potions: 1, mapName: NexusMapName},
+		{name: "dead", hp: 0, mp: 10, potions: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startTestClient(t)
+			got := recordEvents(c, events.EventSafetyTriggered)
+			s := c.Safety()
+			s.HealThreshold, s.NexusThreshold, s.ManaThreshold = 0.5, 0.2, 0.3
+
+			if !c.run(func() {
+				c.currentMap = &Map{Name: "Realm of the Mad God"}
+				if tt.mapName != "" {
+					c.currentMap.Name = tt.mapName
+				}
+				c.state.WorldPos = &WorldPosData{X: 1, Y: 1}
+				if tt.noPos {
+					c.state.WorldPos = nil
+				}
+				data := c.state.PlayerData
+				data.HP, data.MaxHP, data.MP, data.MaxMP = tt.hp, 100, tt.mp, 100
+				data.Stats.HealthPotions, data.Stats.MagicPotions = tt.potions, tt.potions
+
+				s.checkHP(nil, tt.hp)
+				// Potions and escapes wait for their cooldown before firing again
+				s.checkHP(nil, tt.hp)
+			}) {
+				t.Fatal("state goroutine did not run the check")
+			}
+
+			if len(*got) != len(tt.want) {
+				t.Fatalf("got %d safety events, want %v", len(*got), tt.want)
+			}
+			for i, want := range tt.want {
+				if action := (*got)[i].Data.(*events.SafetyEventData).Action; action != want {
+					t.Fatalf("action %d is %s, want %s", i, action, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSafetyHandleDamageChecksAhead(t *testing.T) {
+	c := startTestClient(t)
+	got := recordEvents(c, events.EventSafetyTriggered)
+	s := c.Safety()
+	s.NexusThreshold = 0.2
+
+	if !c.run(func() {
+		c.state.WorldPos = &WorldPosData{X: 1, Y: 1}
+		data := c.state.PlayerData
+		data.HP, data.MaxHP = 50, 100
+
+		// 30 damage leaves 20%, which is not below the threshold
+		s.handleDamage(nil, 30)
+		s.handleDamage(nil, 35)
+	}) {
+		t.Fatal("state goroutine did not run the check")
+	}
+	if len(*got) != 1 {
+		t.Fatalf("got %d safety events, want one escape", len(*got))
+	}
+	if data := (*got)[0].Data.(*events.SafetyEventData); data.Action != events.SafetyNexus || data.HP != 15 {
+		t.Fatalf("safety event %+v, want an escape at 15 hp", data)
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"time"
+
+	"gorelay/pkg/models"
+)
+
+// Snapshot is an immutable view of the client's world state. The state
+// goroutine publishes a new snapshot after every packet and movement step, so
+// all values in one snapshot are consistent with each other. Snapshots must
+// not be modified.
+type Snapshot struct {
+	Time     time.Time
+	Tick     int32
+	ObjectID int32
+	// ClassType is the object type of the player's class
+	ClassType int32
+	Position  WorldPosData
+	Player    PlayerData
+	Map       *Map
+	// QuestID is the object ID of the current quest, 0 if there is none
+	QuestID int32
+
+	Enemies     map[int32]*Enemy
+	Players     map[int32]*Player
+	Projectiles map[int32]*Projectile
+	Containers  map[int32]*models.Container
+	Portals     map[int32]*models.Portal
+}
+
+// emptySnapshot is returned until the first snapshot has been published
+var emptySnapshot = &Snapshot{
+	Enemies:     map[int32]*Enemy{},
+	Players:     map[int32]*Player{},
+	Projectiles: map[int32]*Projectile{},
+	Containers:  map[int32]*models.Container{},
+	Portals:     map[int32]*models.Portal{},
+}
+
+// Snapshot returns the latest view of the world state. It is safe to call from
+// any goroutine. Synchronous event handlers see the state before the packet
+// that raised the event; the event data carries the change itself.
+func (c *Client) Snapshot() *Snapshot {
+	if snap := c.snapshot.Load(); snap != nil {
+		return snap
+	}
+	return emptySnapshot
+}
+
+// publishSnapshot copies the world state into a new snapshot. It must only be
+// called on the state goroutine.
+func (c *Client) publishSnapshot() {
+	snap := &Snapshot{
+		Time:        time.Now(),
+		Tick:        c.clock.LastTick(),
+		QuestID:     c.questID,
+		Enemies:     make(map[int32]*Enemy, len(c.enemies)),
+		Players:     make(map[int32]*Player, len(c.players)),
+		Projectiles: make(map[int32]*Projectile, len(c.projectiles)),
+		Containers:  make(map[int32]*models.Container, len(c.containers)),
+		Portals:     make(map[int32]*models.Portal, len(c.portals)),
+	}
+
+	if state := c.state; state != nil {
+		snap.ObjectID = state.ObjectID
+		snap.ClassType = state.ClassType
+		if state.WorldPos != nil {
+			snap.Position = *state.WorldPos
+		}
+		if state.PlayerData != nil {
+			snap.Player = state.PlayerData.clone()
+		}
+	}
+
+	if c.currentMap != nil {
+		m := *c.currentMap
+		snap.Map = &m
+	}
+	for id, enemy := range c.enemies {
+		snap.Enemies[id] = enemy.clone()
+	}
+	for id, player := range c.players {
+		snap.Players[id] = player.clone()
+	}
+	for id, projectile := range c.projectiles {
+		snap.Projectiles[id] = projectile.clone()
+	}
+	for id, container := range c.containers {
+		copied := *container
+		copied.Items = append([]int32(nil), container.Items...)
+		snap.Containers[id] = &copied
+	}
+	for id, portal := range c.portals {
+		copied := *portal
+		snap.Portals[id] = &copied
+	}
+
+	c.snapshot.Store(snap)
+}
+
+// clone returns a deep copy of the player data
+func (d *PlayerData) clone() PlayerData {
+	copied := *d
+	copied.Inventory = append([]int32(nil), d.Inventory...)
+	copied.Potions = append([]PotionData(nil), d.Potions...)
+	return copied
+}
+
+// clone returns a deep copy of the enemy
+func (e *Enemy) clone() *Enemy {
+	copied := *e
+	copied.Position = e.Position.clone()
+	copied.DamageTaken = make(map[int32]int32, len(e.DamageTaken))
+	for k, v := range e.DamageTaken {
+		copied.DamageTaken[k] = v
+	}
+	copied.recent = append([]damageSample(nil), e.recent...)
+	return &copied
+}
+
+// clone returns a deep copy of the player
+func (p *Player) clone() *Player {
+	copied := *p
+	copied.Position = p.Position.clone()
+	copied.Stats = make(map[string]int32, len(p.Stats))
+	for k, v := range p.Stats {
+		copied.Stats[k] = v
+	}
+	copied.Equipment = make(map[int32]int32, len(p.Equipment))
+	for k, v := range p.Equipment {
+		copied.Equipment[k] = v
+	}
+	return &copied
+}
+
+// clone returns a deep copy of the projectile
+func (p *Projectile) clone() *Projectile {
+	copied := *p
+	copied.StartPos = p.StartPos.clone()
+	copied.Position = p.Position.clone()
+	return &copied
+}
+
+// clone returns a copy of a position, or nil
+func (p *WorldPosData) clone() *WorldPosData {
+	if p == nil {
+		return nil
+	}
+	copied := *p
+	return &copied
+}
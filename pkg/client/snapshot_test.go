@@ -0,0 +1,167 @@
+package client
+
+import (
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gorelay/pkg/account"
+	"gorelay/pkg/config"
+	"gorelay/pkg/logger"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/interfaces"
+	"gorelay/pkg/packets/server"
+)
+
+// startTestClient returns a client whose state goroutine runs on one end of a
+// pipe. Packets the client sends are discarded. The state goroutine stops
+// when the test ends.
+func startTestClient(t *testing.T) *Client {
+	t.Helper()
+	log, err := logger.New(filepath.Join(t.TempDir(), "client.log"), false)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	c := createClient(&account.Account{Alias: "test"}, &config.Config{}, log, models.DefaultServer, nil)
+
+	conn, remote := net.Pipe()
+	go io.Copy(io.Discard, remote)
+
+	done := make(chan struct{})
+	c.conn = conn
+	c.loopDone = done
+	c.connected.Store(true)
+	go c.handlePackets(conn, done)
+
+	t.Cleanup(func() {
+		c.Disconnect()
+		<-done
+		remote.Close()
+		log.Close()
+	})
+	return c
+}
+
+// statusTick builds a NewTick moving an object and setting its health
+func statusTick(tick, objectID int32, x float64, hp int) *server.NewTick {
+	return &server.NewTick{
+		TickId: tick,
+		Statuses: []*dataobjects.Status{{
+			ObjectID: objectID,
+			Position: &dataobjects.Location{X: x, Y: x},
+			Data:     []*dataobjects.StatData{{ID: dataobjects.StatsType(models.HPSTAT), IntValue: hp}},
+		}},
+	}
+}
+
+func TestSnapshotConsistentUnderConcurrentUpdates(t *testing.T) {
+	c := startTestClient(t)
+	const playerID = 1
+	if !c.run(func() { c.state.ObjectID = playerID }) {
+		t.Fatal("state goroutine did not run the setup")
+	}
+
+	stop := make(chan struct{})
+	errs := make(chan string, 8)
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				// Every update adds one enemy and moves the player to x = the
+				// enemy count, with the same health on the player and enemies
+				snap := c.Snapshot()
+				if snap.ObjectID != playerID {
+					continue
+				}
+				if int(snap.Position.X) != len(snap.Enemies) {
+					errs <- "snapshot mixes the position and the enemies of different updates"
+					return
+				}
+				for _, enemy := range snap.Enemies {
+					if enemy.HP != snap.Player.HP {
+						errs <- "snapshot mixes enemy and player health of different updates"
+						return
+					}
+					// Unless positions are copied this read races with the update
+					_ = enemy.Position.X
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 100; i++ {
+		i := i
+		ok := c.run(func() {
+			id := int32(1000 + i)
+			c.enemies[id] = &Enemy{
+				ObjectID:    id,
+				Position:    &WorldPosData{X: float32(i), Y: float32(i)},
+				DamageTaken: make(map[int32]int32),
+			}
+			handle := func(tick *server.NewTick) {
+				if err := c.packetHandler.HandlePacket(int(interfaces.NewTick), tick); err != nil {
+					t.Errorf("NewTick failed: %v", err)
+				}
+			}
+			handle(statusTick(int32(i), playerID, float64(i), i))
+			for enemyID := range c.enemies {
+				handle(statusTick(int32(i), enemyID, float64(i), i))
+			}
+		})
+		if !ok {
+			t.Fatalf("state goroutine did not run update %d", i)
+		}
+	}
+	close(stop)
+	readers.Wait()
+
+	select {
+	case err := <-errs:
+		t.Fatal(err)
+	default:
+	}
+
+	snap := c.Snapshot()
+	if len(snap.Enemies) != 100 || snap.Player.HP != 100 || snap.Position.X != 100 {
+		t.Fatalf("final snapshot has %d enemies, hp %d at x %v, want 100 of each",
+			len(snap.Enemies), snap.Player.HP, snap.Position.X)
+	}
+}
+
+func TestSnapshotIsNotChangedByLaterUpdates(t *testing.T) {
+	c := startTestClient(t)
+	if !c.run(func() {
+		c.enemies[7] = &Enemy{ObjectID: 7, HP: 100, Position: &WorldPosData{X: 1, Y: 1}, DamageTaken: make(map[int32]int32)}
+	}) {
+		t.Fatal("state goroutine did not run the setup")
+	}
+	before := c.Snapshot()
+
+	if !c.run(func() {
+		c.handleObjectStatus(nil, &dataobjects.Status{
+			ObjectID: 7,
+			Position: &dataobjects.Location{X: 5, Y: 5},
+			Data:     []*dataobjects.StatData{{ID: dataobjects.StatsType(models.HPSTAT), IntValue: 40}},
+		})
+	}) {
+		t.Fatal("state goroutine did not run the update")
+	}
+
+	if enemy := before.Enemies[7]; enemy.HP != 100 || enemy.Position.X != 1 {
+		t.Fatalf("old snapshot changed to hp %d at x %v", enemy.HP, enemy.Position.X)
+	}
+	if enemy := c.Snapshot().Enemies[7]; enemy.HP != 40 {
+		t.Fatalf("new snapshot has hp %d, want 40", enemy.HP)
+	}
+}
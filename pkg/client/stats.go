@@ -0,0 +1,312 @@
+package client
+
+import (
+	"gorelay/pkg/models"
+)
+
+// PlayerStats holds every stat the server sends for the player. Inventory and
+// backpack slots are kept in PlayerData.Inventory instead.
+type PlayerStats struct {
+	// Health, mana and experience
+	MaxHP        int32
+	HP           int32
+	MaxMP        int32
+	MP           int32
+	Size         int32
+	Level        int32
+	Exp          int32
+	NextLevelExp int32
+
+	// Base stats, including boosts
+	Attack    int32
+	Defense   int32
+	Speed     int32
+	Dexterity int32
+	Vitality  int32
+	Wisdom    int32
+
+	// Boosts from equipment and effects
+	MaxHPBoost     int32
+	MaxMPBoost     int32
+	AttackBoost    int32
+	DefenseBoost   int32
+	SpeedBoost     int32
+	DexterityBoost int32
+	VitalityBoost  int32
+	WisdomBoost    int32
+
+	// Exaltation bonuses
+	ExaltedHP             int32
+	ExaltedMP             int32
+	ExaltedAttack         int32
+	ExaltedDefense        int32
+	ExaltedSpeed          int32
+	ExaltedDexterity      int32
+	ExaltedVitality       int32
+	ExaltedWisdom         int32
+	ExaltationBonusDamage int32
+	ExaltationICReduction int32
+
+	// Potions and backpack
+	HealthPotions int32
+	MagicPotions  int32
+	PotionTypes   [3]int32
+	PotionBelt    int32
+	HasBackpack   bool
+
+	// Currency, fame and rank
+	Credits            int32
+	Fame               int32
+	CurrentFame        int32
+	NextClassQuestFame int32
+	NumStars           int32
+	LegendaryRank      int32
+	FortuneTokens      int32
+	SupporterPoints    int32
+	Supporter          int32
+	ChallengerStarBg   int32
+	ForgeFire          int32
+
+	// Appearance
+	Texture    int32
+	Tex1       int32
+	Tex2       int32
+	AltTexture int32
+
+	// Merchandise, set on merchant objects
+	MerchandiseType     int32
+	MerchandisePrice    int32
+	MerchandiseCurrency int32
+	MerchandiseCount    int32
+	MerchandiseMinsLeft int32
+	MerchandiseDiscount int32
+	MerchandiseRankReq  int32
+
+	// Identity and guild
+	Name           string
+	NameChosen     bool
+	AccountID      string
+	OwnerAccountID string
+	GraveAccountID string
+	GuildName      string
+	GuildRank      int32
+	RankRequired   int32
+
+	// Conditions, decoded in PlayerData.Effects
+	Condition    int32
+	NewCondition int32
+
+	// Pet
+	PetInstanceID      int32
+	PetName            string
+	PetType            int32
+	PetRarity          int32
+	PetMaxAbilityPower int32
+	PetFamily          int32
+	PetAbilityPoints   [3]int32
+	PetAbilityPowers   [3]int32
+	PetAbilityTypes    [3]int32
+
+	// Timers and other state
+	Active              int32
+	Connect             int32
+	SinkLevel           int32
+	Breath              int32
+	XPBoosted           bool
+	XPTimer             int32
+	LootDropTimer       int32
+	LootTierTimer       int32
+	ProjectileSpeedMult int32
+	ProjectileLifeMult  int32
+	OpenedAtTimestamp   int32
+}
+
+// set stores a stat value, ignoring inventory slots and unknown stats
+func (s *PlayerStats) set(statType models.StatType, value int32, stringValue string) {
+	switch statType {
+	case models.MAXHPSTAT:
+		s.MaxHP = value
+	case models.HPSTAT:
+		s.HP = value
+	case models.SIZESTAT:
+		s.Size = value
+	case models.MAXMPSTAT:
+		s.MaxMP = value
+	case models.MPSTAT:
+		s.MP = value
+	case models.NEXTLEVELEXPSTAT:
+		s.NextLevelExp = value
+	case models.EXPSTAT:
+		s.Exp = value
+	case models.LEVELSTAT:
+		s.Level = value
+	case models.ATTACKSTAT:
+		s.Attack = value
+	case models.DEFENSESTAT:
+		s.Defense = value
+	case models.SPEEDSTAT:
+		s.Speed = value
+	case models.TEXTURESTAT:
+		s.Texture = value
+	case models.VITALITYSTAT:
+		s.Vitality = value
+	case models.WISDOMSTAT:
+		s.Wisdom = value
+	case models.DEXTERITYSTAT:
+		s.Dexterity = value
+	case models.CONDITIONSTAT:
+		s.Condition = value
+	case models.NUMSTARSSTAT:
+		s.NumStars = value
+	case models.NAMESTAT:
+		s.Name = stringValue
+	case models.TEX1STAT:
+		s.Tex1 = value
+	case models.TEX2STAT:
+		s.Tex2 = value
+	case models.MERCHANDISETYPESTAT:
+		s.MerchandiseType = value
+	case models.CREDITSSTAT:
+		s.Credits = value
+	case models.MERCHANDISEPRICESTAT:
+		s.MerchandisePrice = value
+	case models.ACTIVESTAT:
+		s.Active = value
+	case models.ACCOUNTIDSTAT:
+		s.AccountID = stringValue
+	case models.FAMESTAT:
+		s.Fame = value
+	case models.MERCHANDISECURRENCYSTAT:
+		s.MerchandiseCurrency = value
+	case models.CONNECTSTAT:
+		s.Connect = value
+	case models.MERCHANDISECOUNTSTAT:
+		s.MerchandiseCount = value
+	case models.MERCHANDISEMINSLEFTSTAT:
+		s.MerchandiseMinsLeft = value
+	case models.MERCHANDISEDISCOUNTSTAT:
+		s.MerchandiseDiscount = value
+	case models.MERCHANDISERANKREQSTAT:
+		s.MerchandiseRankReq = value
+	case models.MAXHPBOOSTSTAT:
+		s.MaxHPBoost = value
+	case models.MAXMPBOOSTSTAT:
+		s.MaxMPBoost = value
+	case models.ATTACKBOOSTSTAT:
+		s.AttackBoost = value
+	case models.DEFENSEBOOSTSTAT:
+		s.DefenseBoost = value
+	case models.SPEEDBOOSTSTAT:
+		s.SpeedBoost = value
+	case models.VITALITYBOOSTSTAT:
+		s.VitalityBoost = value
+	case models.WISDOMBOOSTSTAT:
+		s.WisdomBoost = value
+	case models.DEXTERITYBOOSTSTAT:
+		s.DexterityBoost = value
+	case models.OWNERACCOUNTIDSTAT:
+		s.OwnerAccountID = stringValue
+	case models.RANKREQUIREDSTAT:
+		s.RankRequired = value
+	case models.NAMECHOSENSTAT:
+		s.NameChosen = value != 0
+	case models.CURRFAMESTAT:
+		s.CurrentFame = value
+	case models.NEXTCLASSQUESTFAMESTAT:
+		s.NextClassQuestFame = value
+	case models.LEGENDARYRANKSTAT:
+		s.LegendaryRank = value
+	case models.SINKLEVELSTAT:
+		s.SinkLevel = value
+	case models.ALTTEXTURESTAT:
+		s.AltTexture = value
+	case models.GUILDNAMESTAT:
+		s.GuildName = stringValue
+	case models.GUILDRANKSTAT:
+		s.GuildRank = value
+	case models.BREATHSTAT:
+		s.Breath = value
+	case models.XPBOOSTEDSTAT:
+		s.XPBoosted = value != 0
+	case models.XPTIMERSTAT:
+		s.XPTimer = value
+	case models.LDTIMERSTAT:
+		s.LootDropTimer = value
+	case models.LTTIMERSTAT:
+		s.LootTierTimer = value
+	case models.HEALTHPOTIONSTACKSTAT:
+		s.HealthPotions = value
+	case models.MAGICPOTIONSTACKSTAT:
+		s.MagicPotions = value
+	case models.HASBACKPACKSTAT:
+		s.HasBackpack = value == 1
+	case models.PETINSTANCEIDSTAT:
+		s.PetInstanceID = value
+	case models.PETNAMESTAT:
+		s.PetName = stringValue
+	case models.PETTYPESTAT:
+		s.PetType = value
+	case models.PETRARITYSTAT:
+		s.PetRarity = value
+	case models.PETMAXABILITYPOWERSTAT:
+		s.PetMaxAbilityPower = value
+	case models.PETFAMILYSTAT:
+		s.PetFamily = value
+	case models.PETFIRSTABILITYPOINTSTAT, models.PETSECONDABILITYPOINTSTAT, models.PETTHIRDABILITYPOINTSTAT:
+		s.PetAbilityPoints[statType-models.PETFIRSTABILITYPOINTSTAT] = value
+	case models.PETFIRSTABILITYPOWERSTAT, models.PETSECONDABILITYPOWERSTAT, models.PETTHIRDABILITYPOWERSTAT:
+		s.PetAbilityPowers[statType-models.PETFIRSTABILITYPOWERSTAT] = value
+	case models.PETFIRSTABILITYTYPESTAT, models.PETSECONDABILITYTYPESTAT, models.PETTHIRDABILITYTYPESTAT:
+		s.PetAbilityTypes[statType-models.PETFIRSTABILITYTYPESTAT] = value
+	case models.NEWCONSTAT:
+		s.NewCondition = value
+	case models.FORTUNETOKENSTAT:
+		s.FortuneTokens = value
+	case models.SUPPORTERPOINTSSTAT:
+		s.SupporterPoints = value
+	case models.SUPPORTERSTAT:
+		s.Supporter = value
+	case models.CHALLENGERSTARBGSTAT:
+		s.ChallengerStarBg = value
+	case models.PROJECTILESPEEDMULT:
+		s.ProjectileSpeedMult = value
+	case models.PROJECTILELIFEMULT:
+		s.ProjectileLifeMult = value
+	case models.OPENEDATTIMESTAMP:
+		s.OpenedAtTimestamp = value
+	case models.EXALTEDATK:
+		s.ExaltedAttack = value
+	case models.EXALTEDDEFENSE:
+		s.ExaltedDefense = value
+	case models.EXALTEDSPD:
+		s.ExaltedSpeed = value
+	case models.EXALTEDVIT:
+		s.ExaltedVitality = value
+	case models.EXALTEDWIS:
+		s.ExaltedWisdom = value
+	case models.EXALTEDDEX:
+		s.ExaltedDexterity = value
+	case models.EXALTEDHP:
+		s.ExaltedHP = value
+	case models.EXALTEDMP:
+		s.ExaltedMP = value
+	case models.EXALTATIONBONUSDMG:
+		s.ExaltationBonusDamage = value
+	case models.EXALTATIONICREDUCTION:
+		s.ExaltationICReduction = value
+	case models.GRAVEACCOUNTID:
+		s.GraveAccountID = stringValue
+	case models.POTIONONETYPE, models.POTIONTWOTYPE, models.POTIONTHREETYPE:
+		s.PotionTypes[statType-models.POTIONONETYPE] = value
+	case models.POTIONBELT:
+		s.PotionBelt = value
+	case models.FORGEFIRE:
+		s.ForgeFire = value
+	}
+}
+
+// Stats returns the player's stats from the latest snapshot, safe to read from
+// any goroutine
+func (c *Client) Stats() PlayerStats {
+	return c.Snapshot().Player.Stats
+}
@@ -3,6 +3,8 @@ package client
 import (
 	"fmt"
 	"math"
+
+	"gorelay/pkg/models"
 )
 
 // Common direction constants
@@ -250,8 +252,11 @@ type PlayerData struct {
 	Inventory     []int32
 	Potions       []PotionData
 
-	// Character stats
-	Stats map[string]int32
+	// Every stat sent by the server, read through Client.Stats
+	Stats PlayerStats
+
+	// Condition effects from the CONDITIONSTAT and NEWCONSTAT
+	Effects models.ConditionEffects
 }
 
 type PotionData struct {
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/xmldata"
+)
+
+// defaultViewRadius is used when the map did not report a view radius
+const defaultViewRadius = 15
+
+// handleNewObject starts tracking an object that came into view
+func (c *Client) handleNewObject(packet packets.Packet, entity *dataobjects.Entity) {
+	if entity == nil || entity.Status == nil {
+		return
+	}
+	status := entity.Status
+
+	// Our own player object carries the class and initial character stats
+	if status.ObjectID == c.state.ObjectID {
+		c.state.ClassType = int32(entity.ObjectType)
+		c.applyPlayerStats(packet, status.Data)
+		return
+	}
+
+	pos := objectPosition(entity)
+	obj := xmldata.GetObjectByTypeID(int(entity.ObjectType))
+	if obj == nil {
+		return
+	}
+
+	switch {
+	case isContainer(obj):
+		c.trackContainer(packet, entity, obj, pos)
+	case isPortal(obj):
+		c.trackPortal(entity, obj, pos)
+	case obj.Enemy != nil:
+		enemy := newEnemy(status.ObjectID, int32(entity.ObjectType), obj, pos)
+		applyEnemyStats(enemy, status.Data)
+		c.enemies[enemy.ObjectID] = enemy
+		c.emit(events.EventNewEnemy, packet, enemyEventData(enemy))
+	case obj.Class == "Player":
+		player := &Player{
+			ObjectID:  status.ObjectID,
+			Position:  pos,
+			Class:     int32(entity.ObjectType),
+			Stats:     make(map[string]int32),
+			Equipment: make(map[int32]int32),
+			LastMove:  time.Now(),
+		}
+		applyOtherPlayerStats(player, status.Data)
+		c.players[player.ObjectID] = player
+		c.party.handlePlayerSeen(player)
+	}
+}
+
+// handleObjectStatus applies a NewTick status to a tracked object
+func (c *Client) handleObjectStatus(packet packets.Packet, status *dataobjects.Status) {
+	if status == nil {
+		return
+	}
+
+	if enemy, ok := c.enemies[status.ObjectID]; ok {
+		if status.Position != nil && (status.Position.X != 0 || status.Position.Y != 0) {
+			enemy.OnGoto(float32(status.Position.X), float32(status.Position.Y), time.Now().UnixMilli())
+		}
+		oldHP, oldEffects := enemy.HP, enemy.Effects
+		applyEnemyStats(enemy, status.Data)
+		if enemy.HP != oldHP {
+			c.emit(events.EventEnemyUpdate, packet, enemyEventData(enemy))
+		}
+		c.emitEffectChanges(packet, enemy.ObjectID, oldEffects, enemy.Effects)
+		if enemy.HP <= 0 && !enemy.Dead {
+			c.killEnemy(packet, enemy)
+		}
+		return
+	}
+
+	if container, ok := c.containers[status.ObjectID]; ok {
+		applyContainerStats(container, status.Data)
+		c.syncVaultChest(container)
+		return
+	}
+
+	if portal, ok := c.portals[status.ObjectID]; ok {
+		applyPortalStats(portal, status.Data)
+		return
+	}
+
+	if player, ok := c.players[status.ObjectID]; ok {
+		if status.Position != nil && (status.Position.X != 0 || status.Position.Y != 0) {
+			player.OnGoto(float32(status.Position.X), float32(status.Position.Y), time.Now().UnixMilli())
+		}
+		oldEffects := player.Effects
+		applyOtherPlayerStats(player, status.Data)
+		c.emitEffectChanges(packet, player.ObjectID, oldEffects, player.Effects)
+	}
+}
+
+// handleDroppedObject stops tracking an object that left view. The server only
+// drops visible enemies when they die, so an enemy removed while still well
+// inside the view radius is treated as killed.
+func (c *Client) handleDroppedObject(packet packets.Packet, objectID int32) {
+	if enemy, ok := c.enemies[objectID]; ok {
+		delete(c.enemies, objectID)
+		if !enemy.Dead && c.insideView(enemy.Position) {
+			c.killEnemy(packet, enemy)
+		}
+	}
+	delete(c.players, objectID)
+	delete(c.containers, objectID)
+	delete(c.portals, objectID)
+}
+
+// killEnemy marks an enemy dead and notifies listeners
+func (c *Client) killEnemy(packet packets.Packet, enemy *Enemy) {
+	enemy.Dead = true
+	if enemy.HP > 0 {
+		enemy.HP = 0
+	}
+	c.emit(events.EventEnemyDeath, packet, enemyEventData(enemy))
+}
+
+// resetTracking forgets every tracked object, e.g. after a map change
+func (c *Client) resetTracking() {
+	c.enemies = make(map[int32]*Enemy)
+	c.players = make(map[int32]*Player)
+	c.projectiles = make(map[int32]*Projectile)
+	c.containers = make(map[int32]*models.Container)
+	c.portals = make(map[int32]*models.Portal)
+	c.questID = 0
+}
+
+// insideView reports whether a position is within the view radius, leaving a
+// margin for objects that were just about to leave it
+func (c *Client) insideView(pos *WorldPosData) bool {
+	if pos == nil || c.state.WorldPos == nil || c.state.WorldPos.IsZero() {
+		return false
+	}
+	radius := float32(defaultViewRadius)
+	if c.currentMap != nil && c.currentMap.ViewRadius > 0 {
+		radius = float32(c.currentMap.ViewRadius)
+	}
+	margin := radius - 2
+	return pos.SquareDistanceTo(c.state.WorldPos) < margin*margin
+}
+
+// applyPlayerStats applies a list of stats to our own player
+func (c *Client) applyPlayerStats(packet packets.Packet, stats []*dataobjects.StatData) {
+	for _, stat := range stats {
+		if stat.IsStringData() {
+			c.updateStat(packet, int32(stat.ID), 0, stat.StringValue)
+		} else {
+			c.updateStat(packet, int32(stat.ID), int32(stat.IntValue), "")
+		}
+	}
+	c.safety.check(packet)
+}
+
+// applyEnemyStats applies the stats the client tracks for enemies
+func applyEnemyStats(enemy *Enemy, stats []*dataobjects.StatData) {
+	for _, stat := range stats {
+		switch models.StatType(stat.ID) {
+		case models.HPSTAT:
+			enemy.HP = int32(stat.IntValue)
+		case models.MAXHPSTAT:
+			enemy.MaxHP = int32(stat.IntValue)
+		case models.DEFENSESTAT:
+			enemy.Defense = int32(stat.IntValue)
+		case models.CONDITIONSTAT:
+			enemy.Effects = enemy.Effects.WithCondition(int32(stat.IntValue))
+		case models.NEWCONSTAT:
+			enemy.Effects = enemy.Effects.WithNewCondition(int32(stat.IntValue))
+		}
+	}
+}
+
+// applyOtherPlayerStats applies the stats the client tracks for other players
+func applyOtherPlayerStats(player *Player, stats []*dataobjects.StatData) {
+	for _, stat := range stats {
+		statType := models.StatType(stat.ID)
+		switch {
+		case statType == models.NAMESTAT:
+			player.Name = stat.StringValue
+		case statType == models.LEVELSTAT:
+			player.Level = int32(stat.IntValue)
+		case statType == models.FAMESTAT:
+			player.Fame = int32(stat.IntValue)
+		case statType == models.GUILDNAMESTAT:
+			player.Guild = stat.StringValue
+		case statType == models.CONDITIONSTAT:
+			player.Effects = player.Effects.WithCondition(int32(stat.IntValue))
+		case statType == models.NEWCONSTAT:
+			player.Effects = player.Effects.WithNewCondition(int32(stat.IntValue))
+		case statType >= models.INVENTORY0STAT && statType <= models.INVENTORY0STAT+3:
+			player.Equipment[int32(statType-models.INVENTORY0STAT)] = int32(stat.IntValue)
+		}
+	}
+}
+
+// objectPosition returns the best known position of a new object
+func objectPosition(entity *dataobjects.Entity) *WorldPosData {
+	if entity.Status.Position != nil && (entity.Status.Position.X != 0 || entity.Status.Position.Y != 0) {
+		return &WorldPosData{X: float32(entity.Status.Position.X), Y: float32(entity.Status.Position.Y)}
+	}
+	if entity.Position != nil {
+		return &WorldPosData{X: float32(entity.Position.X), Y: float32(entity.Position.Y)}
+	}
+	return &WorldPosData{}
+}
@@ -0,0 +1,529 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorelay/pkg/events"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+	"gorelay/pkg/xmldata"
+)
+
+// TradeState is the state of the trade protocol
+type TradeState int
+
+const (
+	TradeIdle       TradeState = iota // no trade in progress
+	TradeRequesting                   // RequestTrade sent, waiting for TradeStart
+	TradeOpen                         // trade window open, offers may change
+	TradeAccepting                    // AcceptTrade sent, waiting for TradeDone
+)
+
+func (s TradeState) String() string {
+	switch s {
+	case TradeIdle:
+		return "idle"
+	case TradeRequesting:
+		return "requesting"
+	case TradeOpen:
+		return "open"
+	case TradeAccepting:
+		return "accepting"
+	default:
+		return fmt.Sprintf("TradeState(%d)", int(s))
+	}
+}
+
+// Trade errors
+var (
+	ErrTradeBusy     = errors.New("a trade is already in progress")
+	ErrTradeTimeout  = errors.New("trade timed out")
+	ErrTradeMismatch = errors.New("partner offer does not match the expected items")
+)
+
+// TradeResult is the outcome reported by TradeDone
+type TradeResult struct {
+	Success     bool
+	Code        int32
+	Description string
+}
+
+// TradeSession is a snapshot of the current trade window
+type TradeSession struct {
+	State           TradeState
+	Partner         string
+	PartnerObjectID int32
+	MyItems         []*dataobjects.Item
+	PartnerItems    []*dataobjects.Item
+	MyOffers        []bool
+	PartnerOffers   []bool
+	PartnerAccepted bool
+}
+
+// Trader runs the trade protocol for a client. Trade blocks until the trade
+// finishes, so it must not be called from packet handlers or synchronous event
+// handlers.
+type Trader struct {
+	Timeout     time.Duration // how long to wait for each step of a trade
+	AcceptDelay time.Duration // how long offers must be stable before accepting
+
+	client *Client
+
+	mu         sync.Mutex
+	session    TradeSession
+	passive    bool // session was opened by the partner
+	lastChange time.Time
+	allowed    map[string]bool
+
+	opMu    sync.Mutex // serializes Trade calls
+	changed chan struct{}
+	done    chan *TradeResult
+}
+
+// newTrader creates the trade manager for a client
+func newTrader(c *Client) *Trader {
+	return &Trader{
+		Timeout:     30 * time.Second,
+		AcceptDelay: 3 * time.Second,
+		client:      c,
+		allowed:     make(map[string]bool),
+		changed:     make(chan struct{}, 1),
+		done:        make(chan *TradeResult, 1),
+	}
+}
+
+// Trader returns the client's trade manager
+func (c *Client) Trader() *Trader {
+	return c.trader
+}
+
+// AllowPartner makes the client open trades requested by the named players and
+// accept trades from them that only give items to us
+func (t *Trader) AllowPartner(names ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, name := range names {
+		t.allowed[strings.ToLower(name)] = true
+	}
+}
+
+// Session returns a snapshot of the current trade
+func (t *Trader) Session() TradeSession {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session := t.session
+	session.MyItems = append([]*dataobjects.Item(nil), t.session.MyItems...)
+	session.PartnerItems = append([]*dataobjects.Item(nil), t.session.PartnerItems...)
+	session.MyOffers = append([]bool(nil), t.session.MyOffers...)
+	session.PartnerOffers = append([]bool(nil), t.session.PartnerOffers...)
+	return session
+}
+
+// Trade offers the items in the given inventory slots to partner and completes
+// the trade only if the partner offers exactly the expected item types back.
+// Pass no expected items to give the items away.
+func (t *Trader) Trade(partner string, slots []int32, expect []int32) (*TradeResult, error) {
+	t.opMu.Lock()
+	defer t.opMu.Unlock()
+
+	t.mu.Lock()
+	if t.session.State != TradeIdle {
+		t.mu.Unlock()
+		return nil, ErrTradeBusy
+	}
+	t.session = TradeSession{State: TradeRequesting, Partner: partner}
+	t.passive = false
+	t.mu.Unlock()
+	t.drain()
+
+	request := client.NewRequestTrade()
+	request.Name = partner
+	if err := t.client.Send(request); err != nil {
+		t.reset()
+		return nil, err
+	}
+
+	// Wait for the partner to open the trade window
+	result, err := t.waitFor(func(s *TradeSession) bool { return s.State == TradeOpen })
+	if err != nil {
+		t.Cancel()
+		return nil, err
+	}
+	if result != nil {
+		return result, nil
+	}
+
+	session := t.Session()
+	if !strings.EqualFold(session.Partner, partner) {
+		t.Cancel()
+		return nil, fmt.Errorf("trade opened with %s instead of %s", session.Partner, partner)
+	}
+
+	offers, err := buildOffers(session.MyItems, slots)
+	if err != nil {
+		t.Cancel()
+		return nil, err
+	}
+	if err := t.client.Send(&client.ChangeTrade{Offers: offers}); err != nil {
+		t.Cancel()
+		return nil, err
+	}
+	t.mu.Lock()
+	t.session.MyOffers = offers
+	t.lastChange = time.Now()
+	t.mu.Unlock()
+
+	deadline := time.Now().Add(t.Timeout)
+	for {
+		// Wait until the partner offers what we expect and the offer has settled
+		result, err := t.waitUntil(deadline, func(s *TradeSession) bool {
+			return s.State == TradeOpen && VerifyOffer(s, expect) == nil
+		})
+		if result != nil {
+			return result, nil
+		}
+		if err != nil {
+			t.Cancel()
+			if errors.Is(err, ErrTradeTimeout) {
+				latest := t.Session()
+				return nil, fmt.Errorf("%w: %v", ErrTradeMismatch, VerifyOffer(&latest, expect))
+			}
+			return nil, err
+		}
+		t.waitSettled()
+
+		// The partner may have changed the offer while we waited
+		t.mu.Lock()
+		if t.session.State != TradeOpen || VerifyOffer(&t.session, expect) != nil {
+			t.mu.Unlock()
+			continue
+		}
+		accept := &client.AcceptTrade{
+			MyOffers:   append([]bool(nil), t.session.MyOffers...),
+			YourOffers: append([]bool(nil), t.session.PartnerOffers...),
+		}
+		t.session.State = TradeAccepting
+		t.mu.Unlock()
+
+		if err := t.client.Send(accept); err != nil {
+			t.Cancel()
+			return nil, err
+		}
+
+		result, reopened, err := t.awaitDone(deadline)
+		if err != nil {
+			t.Cancel()
+			return nil, err
+		}
+		if reopened {
+			// Offers changed after we accepted, verify again
+			continue
+		}
+		return result, nil
+	}
+}
+
+// Cancel aborts the current trade
+func (t *Trader) Cancel() error {
+	t.mu.Lock()
+	state := t.session.State
+	t.mu.Unlock()
+	if state == TradeIdle {
+		return nil
+	}
+	t.reset()
+	return t.client.Send(&client.CancelTrade{})
+}
+
+// VerifyOffer checks that the partner's selected items are exactly the expected item types
+func VerifyOffer(session *TradeSession, expect []int32) error {
+	var offered []int32
+	for i, selected := range session.PartnerOffers {
+		if selected && i < len(session.PartnerItems) {
+			offered = append(offered, session.PartnerItems[i].ItemItem)
+		}
+	}
+
+	want := append([]int32(nil), expect...)
+	sort.Slice(offered, func(i, j int) bool { return offered[i] < offered[j] })
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+	if len(offered) != len(want) {
+		return fmt.Errorf("partner offers %s, expected %s", itemNames(offered), itemNames(want))
+	}
+	for i := range want {
+		if offered[i] != want[i] {
+			return fmt.Errorf("partner offers %s, expected %s", itemNames(offered), itemNames(want))
+		}
+	}
+	return nil
+}
+
+// buildOffers selects our trade slots, checking that each holds a tradable item
+func buildOffers(items []*dataobjects.Item, slots []int32) ([]bool, error) {
+	offers := make([]bool, len(items))
+	for _, slot := range slots {
+		if slot < 0 || int(slot) >= len(items) {
+			return nil, fmt.Errorf("trade slot %d out of range", slot)
+		}
+		item := items[slot]
+		if item.ItemItem == EmptyItem {
+			return nil, fmt.Errorf("trade slot %d is empty", slot)
+		}
+		if !item.Tradable {
+			return nil, fmt.Errorf("%s in slot %d is not tradable", xmldata.GetObjectName(int(item.ItemItem)), slot)
+		}
+		offers[slot] = true
+	}
+	return offers, nil
+}
+
+// handleRequested handles TradeRequested, opening trades with allowed partners
+func (t *Trader) handleRequested(packet *server.TradeRequested) {
+	t.client.emit(events.EventTradeRequested, packet, &events.TradeEventData{Partner: packet.Name})
+
+	t.mu.Lock()
+	allowed := t.allowed[strings.ToLower(packet.Name)]
+	idle := t.session.State == TradeIdle
+	if allowed && idle {
+		t.session = TradeSession{State: TradeRequesting, Partner: packet.Name}
+		t.passive = true
+	}
+	t.mu.Unlock()
+
+	if !allowed || !idle {
+		t.client.logger.Debug("Trade", "Ignoring trade request from %s", packet.Name)
+		return
+	}
+
+	t.client.logger.Info("Trade", "Accepting trade request from %s", packet.Name)
+	request := client.NewRequestTrade()
+	request.Name = packet.Name
+	if err := t.client.Send(request); err != nil {
+		t.client.logger.Error("Trade", "Failed to answer trade request: %v", err)
+		t.reset()
+	}
+}
+
+// handleStart handles TradeStart, opening the trade window
+func (t *Trader) handleStart(packet *server.TradeStart) {
+	t.mu.Lock()
+	passive := t.passive || t.session.State == TradeIdle
+	t.session = TradeSession{
+		State:           TradeOpen,
+		Partner:         packet.YourName,
+		PartnerObjectID: packet.PartnerObjectId,
+		MyItems:         packet.MyItems,
+		PartnerItems:    packet.YourItems,
+		MyOffers:        make([]bool, len(packet.MyItems)),
+		PartnerOffers:   make([]bool, len(packet.YourItems)),
+	}
+	t.passive = passive
+	t.lastChange = time.Now()
+	t.mu.Unlock()
+
+	t.client.logger.Info("Trade", "Trade started with %s", packet.YourName)
+	t.client.emit(events.EventTradeStart, packet, &events.TradeEventData{Partner: packet.YourName})
+	t.notify()
+}
+
+// handleChanged handles TradeChanged, the partner changing their offer
+func (t *Trader) handleChanged(packet *server.TradeChanged) {
+	t.mu.Lock()
+	t.session.PartnerOffers = packet.Offers
+	t.session.PartnerAccepted = false
+	if t.session.State == TradeAccepting {
+		t.session.State = TradeOpen
+	}
+	t.lastChange = time.Now()
+	t.mu.Unlock()
+	t.notify()
+}
+
+// handleAccepted handles TradeAccepted. Passive trades from allowed partners
+// are accepted when we give nothing away.
+func (t *Trader) handleAccepted(packet *server.TradeAccepted) {
+	t.mu.Lock()
+	t.session.PartnerAccepted = true
+	t.session.PartnerOffers = packet.YourOffers
+	autoAccept := t.passive && t.session.State == TradeOpen &&
+		t.allowed[strings.ToLower(t.session.Partner)] && !anySelected(packet.MyOffers)
+	if autoAccept {
+		t.session.State = TradeAccepting
+	}
+	t.mu.Unlock()
+	t.notify()
+
+	if autoAccept {
+		accept := &client.AcceptTrade{MyOffers: packet.MyOffers, YourOffers: packet.YourOffers}
+		if err := t.client.Send(accept); err != nil {
+			t.client.logger.Error("Trade", "Failed to accept trade: %v", err)
+		}
+	}
+}
+
+// handleDone handles TradeDone, finishing the trade
+func (t *Trader) handleDone(packet *server.TradeDone) {
+	result := &TradeResult{
+		Success:     server.TradeResult(packet.Code) == server.TradeResultSuccess,
+		Code:        packet.Code,
+		Description: packet.Description,
+	}
+
+	t.mu.Lock()
+	partner := t.session.Partner
+	t.mu.Unlock()
+	t.reset()
+
+	if result.Success {
+		t.client.logger.Success("Trade", "Trade with %s completed", partner)
+	} else {
+		t.client.logger.Warning("Trade", "Trade with %s ended: %s", partner, packet.Description)
+	}
+
+	select {
+	case t.done <- result:
+	default:
+	}
+	t.client.emit(events.EventTradeDone, packet, &events.TradeEventData{
+		Partner:     partner,
+		Success:     result.Success,
+		Description: result.Description,
+	})
+}
+
+// waitFor waits up to Timeout for the session to satisfy cond
+func (t *Trader) waitFor(cond func(*TradeSession) bool) (*TradeResult, error) {
+	return t.waitUntil(time.Now().Add(t.Timeout), cond)
+}
+
+// waitUntil waits until deadline for the session to satisfy cond. If the trade
+// completes successfully meanwhile, its result is returned instead.
+func (t *Trader) waitUntil(deadline time.Time, cond func(*TradeSession) bool) (*TradeResult, error) {
+	for {
+		t.mu.Lock()
+		ok := cond(&t.session)
+		state := t.session.State
+		t.mu.Unlock()
+		if ok {
+			return nil, nil
+		}
+		if state == TradeIdle {
+			// TradeDone resets the session before handing over its result
+			select {
+			case result := <-t.done:
+				return tradeEnded(result)
+			default:
+			}
+			return nil, errors.New("trade was cancelled")
+		}
+
+		select {
+		case <-t.changed:
+		case result := <-t.done:
+			return tradeEnded(result)
+		case <-time.After(time.Until(deadline)):
+			return nil, ErrTradeTimeout
+		}
+	}
+}
+
+// awaitDone waits for TradeDone after accepting. It reports reopened when the
+// partner changed the offer, which puts the trade back in TradeOpen; other
+// notifications, like the partner accepting, are not a reason to stop waiting.
+func (t *Trader) awaitDone(deadline time.Time) (result *TradeResult, reopened bool, err error) {
+	for {
+		select {
+		case result := <-t.done:
+			return result, false, nil
+		case <-t.changed:
+			t.mu.Lock()
+			state := t.session.State
+			t.mu.Unlock()
+			if state == TradeOpen {
+				return nil, true, nil
+			}
+		case <-time.After(time.Until(deadline)):
+			return nil, false, ErrTradeTimeout
+		}
+	}
+}
+
+// tradeEnded turns the result of a trade that finished while waiting into the
+// result or error to report
+func tradeEnded(result *TradeResult) (*TradeResult, error) {
+	if result.Success {
+		return result, nil
+	}
+	return nil, fmt.Errorf("trade ended: %s", result.Description)
+}
+
+// waitSettled waits until offers have not changed for AcceptDelay
+func (t *Trader) waitSettled() {
+	for {
+		t.mu.Lock()
+		wait := time.Until(t.lastChange.Add(t.AcceptDelay))
+		t.mu.Unlock()
+		if wait <= 0 {
+			return
+		}
+		time.Sleep(wait)
+	}
+}
+
+// notify wakes a waiting Trade call
+func (t *Trader) notify() {
+	select {
+	case t.changed <- struct{}{}:
+	default:
+	}
+}
+
+// drain discards signals left over from an earlier trade
+func (t *Trader) drain() {
+	for {
+		select {
+		case <-t.changed:
+		case <-t.done:
+		default:
+			return
+		}
+	}
+}
+
+// reset returns the trader to idle
+func (t *Trader) reset() {
+	t.mu.Lock()
+	t.session = TradeSession{State: TradeIdle}
+	t.passive = false
+	t.mu.Unlock()
+}
+
+func anySelected(offers []bool) bool {
+	for _, selected := range offers {
+		if selected {
+			return true
+		}
+	}
+	return false
+}
+
+func itemNames(items []int32) string {
+	if len(items) == 0 {
+		return "nothing"
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		name := xmldata.GetObjectName(int(item))
+		if name == "" {
+			name = fmt.Sprintf("item %d", item)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+)
+
+// tradeItems builds a trade window side holding the given item types
+func tradeItems(itemTypes ...int32) []*dataobjects.Item {
+	items := make([]*dataobjects.Item, len(itemTypes))
+	for i, itemType := range itemTypes {
+		items[i] = &dataobjects.Item{ItemItem: itemType, Tradable: true}
+	}
+	return items
+}
+
+// waitSession waits until the trader's session satisfies cond
+func waitSession(t *testing.T, tr *Trader, cond func(TradeSession) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond(tr.Session()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("trade session stuck in %+v", tr.Session())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// startTrade runs Trade in the background, giving slot 0 for expect
+func startTrade(t *testing.T, tr *Trader, timeout time.Duration, expect ...int32) <-chan error {
+	t.Helper()
+	tr.Timeout = timeout
+	tr.AcceptDelay = 0
+	errs := make(chan error, 1)
+	go func() {
+		result, err := tr.Trade("Partner", []int32{0}, expect)
+		if err == nil && !result.Success {
+			err = errors.New("trade result was not successful")
+		}
+		errs <- err
+	}()
+	waitSession(t, tr, func(s TradeSession) bool { return s.State == TradeRequesting })
+	tr.handleStart(&server.TradeStart{
+		MyItems:   tradeItems(testStaff, EmptyItem),
+		YourName:  "Partner",
+		YourItems: tradeItems(testRing, testPotion),
+	})
+	waitSession(t, tr, func(s TradeSession) bool { return len(s.MyOffers) > 0 && s.MyOffers[0] })
+	return errs
+}
+
+func TestTradeCompletes(t *testing.T) {
+	tr := startTestClient(t).Trader()
+	errs := startTrade(t, tr, 2*time.Second, testRing)
+
+	tr.handleChanged(&server.TradeChanged{Offers: []bool{true, false}})
+	waitSession(t, tr, func(s TradeSession) bool { return s.State == TradeAccepting })
+	tr.handleDone(&server.TradeDone{Code: int32(server.TradeResultSuccess)})
+
+	if err := <-errs; err != nil {
+		t.Fatalf("Trade failed: %v", err)
+	}
+	if state := tr.Session().State; state != TradeIdle {
+		t.Fatalf("trade ended in state %s, want idle", state)
+	}
+}
+
+func TestTradeVerifiesAgainAfterOfferChanges(t *testing.T) {
+	tr := startTestClient(t).Trader()
+	errs := startTrade(t, tr, 2*time.Second, testRing)
+
+	tr.handleChanged(&server.TradeChanged{Offers: []bool{true, false}})
+	waitSession(t, tr, func(s TradeSession) bool { return s.State == TradeAccepting })
+
+	// Swapping the ring for the potion after we accepted reopens the trade
+	tr.handleChanged(&server.TradeChanged{Offers: []bool{false, true}})
+	waitSession(t, tr, func(s TradeSession) bool { return s.State == TradeOpen })
+	time.Sleep(20 * time.Millisecond)
+	if state := tr.Session().State; state != TradeOpen {
+		t.Fatalf("accepted a changed offer, state %s", state)
+	}
+
+	tr.handleChanged(&server.TradeChanged{Offers: []bool{true, false}})
+	waitSession(t, tr, func(s TradeSession) bool { return s.State == TradeAccepting })
+	tr.handleDone(&server.TradeDone{Code: int32(server.TradeResultSuccess)})
+	if err := <-errs; err != nil {
+		t.Fatalf("Trade failed: %v", err)
+	}
+}
+
+func TestTradeRejectsWrongOffer(t *testing.T) {
+	tr := startTestClient(t).Trader()
+	errs := startTrade(t, tr, 100*time.Millisecond, testRing)
+
+	tr.handleChanged(&server.TradeChanged{Offers: []bool{false, true}})
+	if err := <-errs; !errors.Is(err, ErrTradeMismatch) {
+		t.Fatalf("Trade error = %v, want ErrTradeMismatch", err)
+	}
+	if state := tr.Session().State; state != TradeIdle {
+		t.Fatalf("mismatched trade left the session %s", state)
+	}
+}
+
+func TestTradePartnerCancels(t *testing.T) {
+	tr := startTestClient(t).Trader()
+	errs := startTrade(t, tr, 2*time.Second, testRing)
+
+	tr.handleDone(&server.TradeDone{Code: int32(server.TradeResultSuccess) + 1, Description: "cancelled"})
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Fatalf("Trade error = %v, want the partner's cancellation", err)
+	}
+}
+
+func TestPassiveTradeAutoAccept(t *testing.T) {
+	tests := []struct {
+		name       string
+		partner    string
+		myOffers   []bool
+		wantOpened bool
+		wantAccept bool
+	}{
+		{name: "gift from allowed partner", partner: "Friend", myOffers: []bool{false}, wantOpened: true, wantAccept: true},
+		{name: "allowed partner asks for items", partner: "Friend", myOffers: []bool{true}, wantOpened: true},
+		{name: "stranger", partner: "Stranger", myOffers: []bool{false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := startTestClient(t).Trader()
+			tr.AllowPartner("friend")
+
+			tr.handleRequested(&server.TradeRequested{Name: tt.partner})
+			opened := tr.Session().State == TradeRequesting
+			if opened != tt.wantOpened {
+				t.Fatalf("request opened a trade: %v, want %v", opened, tt.wantOpened)
+			}
+			if !opened {
+				return
+			}
+
+			tr.handleStart(&server.TradeStart{
+				MyItems:   tradeItems(testStaff),
+				YourName:  tt.partner,
+				YourItems: tradeItems(testRing),
+			})
+			tr.handleAccepted(&server.TradeAccepted{MyOffers: tt.myOffers, YourOffers: []bool{true}})
+			if accepted := tr.Session().State == TradeAccepting; accepted != tt.wantAccept {
+				t.Fatalf("trade accepted: %v, want %v", accepted, tt.wantAccept)
+			}
+		})
+	}
+}
+
+func TestVerifyOffer(t *testing.T) {
+	session := &TradeSession{PartnerItems: tradeItems(testRing, testPotion, testRing)}
+	tests := []struct {
+		name    string
+		offers  []bool
+		expect  []int32
+		wantErr bool
+	}{
+		{name: "exact items in any order", offers: []bool{true, true, false}, expect: []int32{testPotion, testRing}},
+		{name: "duplicates", offers: []bool{true, false, true}, expect: []int32{testRing, testRing}},
+		{name: "nothing for nothing", offers: []bool{false, false, false}},
+		{name: "missing item", offers: []bool{true, false, false}, expect: []int32{testRing, testPotion}, wantErr: true},
+		{name: "extra item", offers: []bool{true, true, false}, expect: []int32{testRing}, wantErr: true},
+		{name: "wrong item", offers: []bool{false, true, false}, expect: []int32{testRing}, wantErr: true},
+		{name: "offer past the items", offers: []bool{false, false, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session.PartnerOffers = tt.offers
+			if err := VerifyOffer(session, tt.expect); (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyOffer() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildOffers(t *testing.T) {
+	items := tradeItems(testStaff, EmptyItem, testRing)
+	items[2].Tradable = false
+
+	tests := []struct {
+		name    string
+		slots   []int32
+		want    []bool
+		wantErr string
+	}{
+		{name: "one item", slots: []int32{0}, want: []bool{true, false, false}},
+		{name: "nothing", want: []bool{false, false, false}},
+		{name: "empty slot", slots: []int32{1}, wantErr: "is empty"},
+		{name: "soulbound item", slots: []int32{2}, wantErr: "not tradable"},
+		{name: "out of range", slots: []int32{3}, wantErr: "out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offers, err := buildOffers(items, tt.slots)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("buildOffers() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildOffers() failed: %v", err)
+			}
+			for i := range tt.want {
+				if offers[i] != tt.want[i] {
+					t.Fatalf("buildOffers() = %v, want %v", offers, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"time"
+
+	"gorelay/pkg/models"
 )
 
 // GameObject represents a game object with its properties
@@ -66,11 +68,21 @@ type ConditionEffect struct {
 // GameState represents the current state of the game
 type GameState struct {
 	ObjectID      int32
+	ClassType     int32
 	WorldPos      *WorldPosData
 	PlayerData    *PlayerData
 	GameID        int32
 	LastUpdate    time.Time
 	LastFrameTime int64
+
+	// Last raw value received for each stat, used to report changes
+	rawStats map[int32]rawStat
+}
+
+// rawStat is a stat value as sent by the server
+type rawStat struct {
+	value       int32
+	stringValue string
 }
 
 // Projectile represents an active projectile in the game
@@ -91,6 +103,7 @@ type Projectile struct {
 type Enemy struct {
 	ObjectID   int32
 	ObjectType int32
+	Name       string
 	Position   *WorldPosData
 	HP         int32
 	MaxHP      int32
@@ -98,7 +111,19 @@ type Enemy struct {
 	Dead       bool
 	LastMove   time.Time
 	LastHit    time.Time
-	Effects    []int32
+	Effects    models.ConditionEffects
+
+	// Flags and labels from the object definition
+	God    bool
+	Quest  bool
+	Oryx   bool
+	Labels []string
+
+	// Damage taken, by the object ID of the player that dealt it
+	DamageTaken map[int32]int32
+	TotalDamage int32
+	FirstHit    time.Time
+	recent      []damageSample
 }
 
 // OnGoto updates the enemy's position
@@ -108,18 +133,9 @@ func (e *Enemy) OnGoto(x, y float32, timestamp int64) {
 	e.LastMove = time.Unix(0, timestamp*int64(time.Millisecond))
 }
 
-// OnDamage handles damage taken by the enemy
+// OnDamage handles raw damage taken by the enemy, applying its defense
 func (e *Enemy) OnDamage(damage int32, armorPiercing bool) {
-	if armorPiercing {
-		e.HP -= damage
-	} else {
-		defense := float32(e.Defense)
-		actualDamage := float32(damage) - defense
-		if actualDamage < 1 {
-			actualDamage = 1
-		}
-		e.HP -= int32(actualDamage)
-	}
+	e.HP -= e.DamageFor(damage, armorPiercing)
 	e.LastHit = time.Now()
 }
 
@@ -136,7 +152,7 @@ type Player struct {
 	Guild      string
 	LastMove   time.Time
 	LastAction time.Time
-	Effects    []int32
+	Effects    models.ConditionEffects
 }
 
 // OnGoto updates the player's position
@@ -152,22 +168,18 @@ func (p *Player) OnAction() {
 }
 
 // HasEffect checks if the player has a specific status effect
-func (p *Player) HasEffect(effect int32) bool {
-	for _, e := range p.Effects {
-		if e == effect {
-			return true
-		}
-	}
-	return false
+func (p *Player) HasEffect(effect models.ConditionEffect) bool {
+	return p.Effects.Has(effect)
 }
 
 // Map represents the current game map
 type Map struct {
-	Name   string
-	Width  int32
-	Height int32
-	Tiles  [][]int32 // Just store tile types as integers
-	Seed   int32
+	Name       string
+	Width      int32
+	Height     int32
+	Tiles      [][]int32 // Just store tile types as integers
+	Seed       int32
+	ViewRadius int32
 }
 
 // StatData represents a stat update from the server
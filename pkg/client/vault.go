@@ -0,0 +1,206 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/server"
+)
+
+// VaultMapName is the name of the map holding the account's storage
+const VaultMapName = "Vault"
+
+// ErrNotInVault is returned by storage operations outside the vault
+var ErrNotInVault = errors.New("not in the vault")
+
+// loadVault restores the account's vault record from the shared storage
+func (c *Client) loadVault() *models.VaultRecord {
+	alias := c.accountInfo.Alias
+	if storage := models.GetVaultStorage(); storage != nil {
+		if record := storage.Get(alias); record != nil {
+			return record
+		}
+	}
+	return &models.VaultRecord{Alias: alias}
+}
+
+// Vault returns a copy of the account's mirrored storage
+func (c *Client) Vault() *models.VaultRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.vault.Clone()
+}
+
+// handleVaultContent mirrors the storage contents sent when entering the vault
+func (c *Client) handleVaultContent(packet *server.VaultContent) {
+	c.mu.Lock()
+	c.vaultChests = map[models.VaultChest]int32{
+		models.ChestVault:    packet.VaultChestObjectId,
+		models.ChestMaterial: packet.MaterialChestObjectID,
+		models.ChestGift:     packet.GiftChestObjectId,
+		models.ChestPotion:   packet.PotionStorageObjectId,
+		models.ChestSpoils:   packet.SeasonalSpoilChestObjectId,
+	}
+	record := c.vault
+	record.Vault = packet.VaultContents
+	record.Material = packet.MaterialContents
+	record.Gift = packet.GiftContents
+	record.Potions = packet.PotionContents
+	record.Spoils = packet.SeasonalSpoilContent
+	record.VaultUpgradeCost = packet.VaultUpgradeCost
+	record.MaterialUpgradeCost = packet.MaterialUpgradeCost
+	record.PotionUpgradeCost = packet.PotionUpgradeCost
+	record.CurrentPotionMax = packet.CurrentPotionMax
+	record.NextPotionMax = packet.NextPotionMax
+	record.VaultEnchants = packet.VaultChestEnchants
+	record.GiftEnchants = packet.GiftChestEnchants
+	record.SpoilsEnchants = packet.SpoilsChestEnchants
+	c.mu.Unlock()
+
+	c.logger.Debug("Client", "Vault content: %d vault, %d material, %d gift, %d potion items",
+		len(packet.VaultContents), len(packet.MaterialContents), len(packet.GiftContents), len(packet.PotionContents))
+	c.saveVault()
+}
+
+// inVault reports whether the client is in the vault map, on the state goroutine
+func (c *Client) inVault() bool {
+	return c.currentMap != nil && c.currentMap.Name == VaultMapName
+}
+
+// enterVault forgets the classic chests of the previous visit
+func (c *Client) enterVault() {
+	c.mu.Lock()
+	c.vault.Chests = make(map[int32][]int32)
+	c.mu.Unlock()
+}
+
+// syncVaultChest mirrors a storage chest seen in the vault map
+func (c *Client) syncVaultChest(container *models.Container) {
+	if !c.inVault() || container.BagType > 0 {
+		return
+	}
+
+	c.mu.Lock()
+	items := append([]int32(nil), container.Items...)
+	reported := false
+	for chest, id := range c.vaultChests {
+		if id == container.ObjectID {
+			// Chests reported by VaultContent may hold more items than the
+			// object shows, only the shown slots are replaced
+			contents := append([]int32(nil), c.vault.Contents(chest)...)
+			for len(contents) < len(items) {
+				contents = append(contents, EmptyItem)
+			}
+			copy(contents, items)
+			c.vault.SetContents(chest, contents)
+			reported = true
+			break
+		}
+	}
+	if !reported {
+		if c.vault.Chests == nil {
+			c.vault.Chests = make(map[int32][]int32)
+		}
+		c.vault.Chests[container.ObjectID] = items
+	}
+	c.mu.Unlock()
+
+	c.saveVault()
+}
+
+// saveVault stores the vault record in the shared storage. The file is written
+// on another goroutine, as this is called from packet handlers.
+func (c *Client) saveVault() {
+	storage := models.GetVaultStorage()
+	if storage == nil {
+		return
+	}
+	record := c.Vault()
+	storage.Store(record)
+	go func() {
+		if err := storage.Flush(record.Alias); err != nil {
+			c.logger.Error("Client", "Failed to save vault: %v", err)
+		}
+	}()
+}
+
+// Deposit moves the item in an inventory slot into the first free slot of a
+// storage chest. Classic chests are containers, use Pull to take items out
+// of them.
+func (inv *Inventory) Deposit(slot int32, chest models.VaultChest) error {
+	item := inv.Item(slot)
+	if item.IsEmpty() {
+		return fmt.Errorf("slot %d is empty", slot)
+	}
+	chestID, contents, err := inv.vaultChest(chest)
+	if err != nil {
+		return err
+	}
+
+	index := -1
+	for i, itemType := range contents {
+		if itemType == EmptyItem {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("%s chest is full", chest)
+	}
+
+	err = inv.swap(
+		dataobjects.NewSlotObjectWithData(inv.client.Snapshot().ObjectID, slot, item.ItemType),
+		dataobjects.NewSlotObjectWithData(chestID, int32(index), EmptyItem),
+	)
+	if err != nil {
+		return err
+	}
+	inv.predictSlots(map[int32]int32{slot: EmptyItem})
+	inv.client.setVaultSlot(chest, index, item.ItemType)
+	return nil
+}
+
+// Withdraw moves an item out of a storage chest into the first free slot
+func (inv *Inventory) Withdraw(chest models.VaultChest, index int32) error {
+	chestID, contents, err := inv.vaultChest(chest)
+	if err != nil {
+		return err
+	}
+	if index < 0 || int(index) >= len(contents) || contents[index] == EmptyItem {
+		return fmt.Errorf("%s chest slot %d is empty", chest, index)
+	}
+
+	if err := inv.LootFrom(chestID, index, contents[index]); err != nil {
+		return err
+	}
+	inv.client.setVaultSlot(chest, int(index), EmptyItem)
+	return nil
+}
+
+// vaultChest returns the object ID and contents of a storage chest
+func (inv *Inventory) vaultChest(chest models.VaultChest) (int32, []int32, error) {
+	if m := inv.client.Snapshot().Map; m == nil || m.Name != VaultMapName {
+		return 0, nil, ErrNotInVault
+	}
+
+	inv.client.mu.Lock()
+	defer inv.client.mu.Unlock()
+	chestID, ok := inv.client.vaultChests[chest]
+	if !ok || chestID == 0 {
+		return 0, nil, fmt.Errorf("no %s chest in this vault", chest)
+	}
+	contents := append([]int32(nil), inv.client.vault.Contents(chest)...)
+	return chestID, contents, nil
+}
+
+// setVaultSlot records a change to a storage chest made by this client
+func (c *Client) setVaultSlot(chest models.VaultChest, index int, itemType int32) {
+	c.mu.Lock()
+	if contents := c.vault.Contents(chest); index < len(contents) {
+		contents[index] = itemType
+	}
+	c.mu.Unlock()
+	c.saveVault()
+}
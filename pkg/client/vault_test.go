@@ -0,0 +1,47 @@
+package client
+
+import (
+	"testing"
+
+	"gorelay/pkg/models"
+	"gorelay/pkg/packets/server"
+)
+
+func TestVaultMirrorsChests(t *testing.T) {
+	c := startTestClient(t)
+	if !c.run(func() {
+		c.currentMap = &Map{Name: VaultMapName}
+		c.enterVault()
+		c.handleVaultContent(&server.VaultContent{
+			VaultChestObjectId: 10,
+			VaultContents:      []int32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			PotionContents:     []int32{20},
+		})
+
+		// The vault chest object shows only its first slots
+		addBag(c, 10, 1, 11, EmptyItem)
+		c.syncVaultChest(c.containers[10])
+		// A classic chest that VaultContent did not report
+		addBag(c, 12, 2, 30)
+		c.syncVaultChest(c.containers[12])
+	}) {
+		t.Fatal("state goroutine did not run the vault updates")
+	}
+
+	vault := c.Vault()
+	want := []int32{11, EmptyItem, EmptyItem, EmptyItem, EmptyItem, EmptyItem, EmptyItem, EmptyItem, 9, 10}
+	if len(vault.Vault) != len(want) {
+		t.Fatalf("vault holds %v, want %v", vault.Vault, want)
+	}
+	for i := range want {
+		if vault.Vault[i] != want[i] {
+			t.Fatalf("vault holds %v, want %v", vault.Vault, want)
+		}
+	}
+	if vault.Contents(models.ChestPotion)[0] != 20 {
+		t.Fatalf("potion storage holds %v, want [20]", vault.Potions)
+	}
+	if items := vault.Chests[12]; len(items) == 0 || items[0] != 30 {
+		t.Fatalf("classic chest holds %v, want 30 first", items)
+	}
+}
@@ -24,9 +24,48 @@ type Config struct {
 	SafeWalk           bool    `json:"safeWalk"`
 	AutoAim            bool    `json:"autoAim"`
 
+	// Death handling
+	Death struct {
+		Policy    string `json:"policy"`    // one of the DeathPolicy* values
+		ClassType uint16 `json:"classType"` // class used when a new character is created
+	} `json:"death"`
+
+	// Daily quests
+	Quests struct {
+		AutoFetch  bool `json:"autoFetch"`  // fetch quests on login and after the daily reset
+		AutoRedeem bool `json:"autoRedeem"` // redeem quests as soon as the inventory holds the requirements
+	} `json:"quests"`
+
+	// Chat settings
+	Chat struct {
+		CommandPrefix  string   `json:"commandPrefix"`  // prefix of chat commands, "!" when empty
+		AllowedSenders []string `json:"allowedSenders"` // players allowed to use every command, when empty only tells to yourself and guild chat
+		SendInterval   int      `json:"sendInterval"`   // minimum milliseconds between sent messages
+	} `json:"chat"`
+
+	// Guild settings
+	Guild struct {
+		AcceptInvitesFrom []string `json:"acceptInvitesFrom"` // guilds whose invites are accepted automatically
+	} `json:"guild"`
+
+	// Server queue handling
+	Queue struct {
+		Policy      string `json:"policy"`      // one of the QueuePolicy* values
+		MaxWait     int    `json:"maxWait"`     // seconds in a queue before the policy acts, 0 for no limit
+		MaxPosition int    `json:"maxPosition"` // queue positions above this act immediately, 0 for no limit
+	} `json:"queue"`
+
+	// Server selection
+	ServerSelection struct {
+		Strategy        string   `json:"strategy"`        // preferred, leastLoaded, lowestLatency or sticky
+		Preferred       []string `json:"preferred"`       // server names tried in order after the account preference
+		FailureCooldown int      `json:"failureCooldown"` // seconds a failing server is skipped
+	} `json:"serverSelection"`
+
 	// Proxy settings
 	Proxy struct {
 		Enabled  bool   `json:"enabled"`
+		Type     string `json:"type"` // "http" (default) or "socks5"
 		Host     string `json:"host"`
 		Port     int    `json:"port"`
 		Username string `json:"username"`
@@ -41,6 +80,24 @@ type Config struct {
 	} `json:"plugins"`
 }
 
+// Death recovery policies
+const (
+	DeathPolicyNone     = "none"     // stay disconnected after a death
+	DeathPolicyExisting = "existing" // reconnect with an existing character only
+	DeathPolicyNew      = "new"      // always create a new character
+	DeathPolicyAny      = "any"      // prefer an existing character, create one otherwise
+)
+
+// Queue policies
+const (
+	QueuePolicyWait   = "wait"   // stay in the queue however long it takes
+	QueuePolicySwitch = "switch" // cancel and connect to another server
+	QueuePolicyGiveUp = "giveUp" // cancel and disconnect
+)
+
+// DefaultClassType is the class used for new characters when none is configured (wizard)
+const DefaultClassType uint16 = 768
+
 var cfg *Config
 
 // LoadConfig loads the configuration from a JSON file
@@ -65,8 +122,33 @@ func LoadConfig(path string) (*Config, error) {
 				ReconnectDelay:     5000,
 				SafeWalk:           true,
 				AutoAim:            true,
+				Death: struct {
+					Policy    string `json:"policy"`
+					ClassType uint16 `json:"classType"`
+				}{
+					Policy:    DeathPolicyAny,
+					ClassType: DefaultClassType,
+				},
+				Queue: struct {
+					Policy      string `json:"policy"`
+					MaxWait     int    `json:"maxWait"`
+					MaxPosition int    `json:"maxPosition"`
+				}{
+					Policy:  QueuePolicyWait,
+					MaxWait: 600,
+				},
+				ServerSelection: struct {
+					Strategy        string   `json:"strategy"`
+					Preferred       []string `json:"preferred"`
+					FailureCooldown int      `json:"failureCooldown"`
+				}{
+					Strategy:        "preferred",
+					Preferred:       make([]string, 0),
+					FailureCooldown: 300,
+				},
 				Proxy: struct {
 					Enabled  bool   `json:"enabled"`
+					Type     string `json:"type"`
 					Host     string `json:"host"`
 					Port     int    `json:"port"`
 					Username string `json:"username"`
@@ -96,12 +178,24 @@ func LoadConfig(path string) (*Config, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %v", err)
 	}
+	config.applyDefaults()
 	
 	cfg = &config
 
 	return &config, nil
 }
 
+// applyDefaults fills in settings that are missing from a config file, such as
+// sections added after the file was written
+func (c *Config) applyDefaults() {
+	if c.Death.Policy == "" {
+		c.Death.Policy = DeathPolicyAny
+	}
+	if c.Death.ClassType == 0 {
+		c.Death.ClassType = DefaultClassType
+	}
+}
+
 // Save writes the configuration to a JSON file
 func (c *Config) Save(path string) error {
 	data, err := json.MarshalIndent(c, "", "  ")
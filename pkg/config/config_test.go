@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigDefaultsDeath(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		policy string
+		class  uint16
+	}{
+		{name: "section missing", json: `{"debug": true}`, policy: DeathPolicyAny, class: DefaultClassType},
+		{name: "section empty", json: `{"death": {}}`, policy: DeathPolicyAny, class: DefaultClassType},
+		{name: "policy only", json: `{"death": {"policy": "none"}}`, policy: DeathPolicyNone, class: DefaultClassType},
+		{name: "class only", json: `{"death": {"classType": 782}}`, policy: DeathPolicyAny, class: 782},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if cfg.Death.Policy != tt.policy || cfg.Death.ClassType != tt.class {
+				t.Fatalf("death settings = %q/%d, want %q/%d", cfg.Death.Policy, cfg.Death.ClassType, tt.policy, tt.class)
+			}
+		})
+	}
+}
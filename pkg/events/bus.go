@@ -0,0 +1,249 @@
+package events
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Handler receives events published on a Bus
+type Handler func(*Event)
+
+// PanicHandler is called when a subscriber panics while handling an event
+type PanicHandler func(event *Event, recovered interface{})
+
+// Subscription priorities. Handlers with a higher priority run first; handlers
+// with equal priority run in subscription order.
+const (
+	PriorityLow    = -100
+	PriorityNormal = 0
+	PriorityHigh   = 100
+)
+
+// DefaultQueueSize is the queue length used by async subscriptions that do not set one
+const DefaultQueueSize = 256
+
+// Bus dispatches events to subscribers. It is safe for concurrent use.
+type Bus struct {
+	mu      sync.RWMutex
+	subs    map[EventType][]*Subscription
+	nextID  uint64
+	onPanic PanicHandler
+	closed  bool
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[EventType][]*Subscription),
+	}
+}
+
+// SubscribeOption configures a subscription
+type SubscribeOption func(*Subscription)
+
+// WithPriority sets the order a handler runs in relative to other handlers
+func WithPriority(priority int) SubscribeOption {
+	return func(s *Subscription) {
+		s.priority = priority
+	}
+}
+
+// WithAsync delivers events on a separate goroutine through a bounded queue.
+// When the queue is full new events are dropped and counted rather than
+// blocking the publisher.
+func WithAsync(queueSize int) SubscribeOption {
+	return func(s *Subscription) {
+		if queueSize <= 0 {
+			queueSize = DefaultQueueSize
+		}
+		s.queue = make(chan *Event, queueSize)
+	}
+}
+
+// Subscription is a handle returned by Subscribe that can remove the handler again
+type Subscription struct {
+	id        uint64
+	bus       *Bus
+	eventType EventType
+	handler   Handler
+	priority  int
+
+	queue   chan *Event
+	done    chan struct{}
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// Type returns the event type the subscription listens to
+func (s *Subscription) Type() EventType {
+	return s.eventType
+}
+
+// Dropped returns how many events an async subscription discarded because its queue was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe removes the handler from the bus. Events already queued for an
+// async handler are discarded. It is safe to call more than once.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.bus.remove(s)
+		if s.queue != nil {
+			close(s.done)
+		}
+	})
+}
+
+// SetPanicHandler sets the function told about panicking subscribers. A
+// panicking handler never affects other handlers or the publisher.
+func (b *Bus) SetPanicHandler(handler PanicHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onPanic = handler
+}
+
+// Subscribe registers a handler for an event type
+func (b *Bus) Subscribe(eventType EventType, handler Handler, opts ...SubscribeOption) *Subscription {
+	sub := &Subscription{
+		bus:       b,
+		eventType: eventType,
+		handler:   handler,
+		priority:  PriorityNormal,
+	}
+	for _, opt := range opts {
+		opt(sub)
+	}
+	if sub.queue != nil {
+		sub.done = make(chan struct{})
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	sub.id = b.nextID
+
+	// Copy on write so Publish can iterate without holding the lock
+	current := b.subs[eventType]
+	updated := make([]*Subscription, len(current), len(current)+1)
+	copy(updated, current)
+	updated = append(updated, sub)
+	sort.SliceStable(updated, func(i, j int) bool {
+		return updated[i].priority > updated[j].priority
+	})
+	b.subs[eventType] = updated
+	b.mu.Unlock()
+
+	if sub.queue != nil {
+		go sub.run()
+	}
+	return sub
+}
+
+// Publish delivers an event to every subscriber of its type. Synchronous
+// handlers have run by the time Publish returns.
+func (b *Bus) Publish(event *Event) {
+	if event == nil {
+		return
+	}
+
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return
+	}
+	subs := b.subs[event.Type]
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.queue == nil {
+			sub.call(event)
+			continue
+		}
+		select {
+		case <-sub.done:
+		case sub.queue <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribers returns how many handlers are subscribed to an event type
+func (b *Bus) Subscribers(eventType EventType) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs[eventType])
+}
+
+// Close removes every subscription and stops async delivery. Events published
+// after Close are ignored.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	var all []*Subscription
+	for _, subs := range b.subs {
+		all = append(all, subs...)
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	for _, sub := range all {
+		sub.Unsubscribe()
+	}
+}
+
+// remove drops a subscription from the bus
+func (b *Bus) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := b.subs[sub.eventType]
+	updated := make([]*Subscription, 0, len(current))
+	for _, s := range current {
+		if s.id != sub.id {
+			updated = append(updated, s)
+		}
+	}
+	if len(updated) == 0 {
+		delete(b.subs, sub.eventType)
+		return
+	}
+	b.subs[sub.eventType] = updated
+}
+
+// run delivers queued events for an async subscription
+func (s *Subscription) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case event := <-s.queue:
+			s.call(event)
+		}
+	}
+}
+
+// call runs the handler, isolating the bus from panics
+func (s *Subscription) call(event *Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.bus.mu.RLock()
+			onPanic := s.bus.onPanic
+			s.bus.mu.RUnlock()
+			if onPanic != nil {
+				onPanic(event, r)
+			}
+		}
+	}()
+	s.handler(event)
+}
+
+// On subscribes a handler that receives the event payload as T. Events whose
+// Data is not a T are skipped, so handlers never need to type assert.
+func On[T any](b *Bus, eventType EventType, handler func(*Event, T), opts ...SubscribeOption) *Subscription {
+	return b.Subscribe(eventType, func(event *Event) {
+		if data, ok := event.Data.(T); ok {
+			handler(event, data)
+		}
+	}, opts...)
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBusOrdersHandlersByPriority(t *testing.T) {
+	bus := NewBus()
+	var order []string
+	record := func(name string) Handler {
+		return func(*Event) { order = append(order, name) }
+	}
+
+	bus.Subscribe(EventTick, record("normal 1"))
+	bus.Subscribe(EventTick, record("low"), WithPriority(PriorityLow))
+	bus.Subscribe(EventTick, record("normal 2"))
+	bus.Subscribe(EventTick, record("high"), WithPriority(PriorityHigh))
+	bus.Publish(&Event{Type: EventTick})
+
+	want := []string{"high", "normal 1", "normal 2", "low"}
+	if len(order) != len(want) {
+		t.Fatalf("handlers ran %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("handlers ran %v, want %v", order, want)
+		}
+	}
+}
+
+func TestBusDropsWhenAsyncQueueIsFull(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	sub := bus.Subscribe(EventTick, func(*Event) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+	}, WithAsync(1))
+
+	// The first event blocks the handler, the second fills the queue
+	bus.Publish(&Event{Type: EventTick})
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("async handler never ran")
+	}
+	bus.Publish(&Event{Type: EventTick})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			bus.Publish(&Event{Type: EventTick})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full async queue")
+	}
+	close(release)
+
+	if dropped := sub.Dropped(); dropped != 3 {
+		t.Fatalf("dropped %d events, want 3", dropped)
+	}
+}
+
+func TestBusIsolatesPanickingHandlers(t *testing.T) {
+	bus := NewBus()
+
+	var mu sync.Mutex
+	var recovered []interface{}
+	bus.SetPanicHandler(func(_ *Event, r interface{}) {
+		mu.Lock()
+		recovered = append(recovered, r)
+		mu.Unlock()
+	})
+
+	ran := false
+	bus.Subscribe(EventTick, func(*Event) { panic("boom") }, WithPriority(PriorityHigh))
+	bus.Subscribe(EventTick, func(*Event) { ran = true })
+	bus.Publish(&Event{Type: EventTick})
+
+	if !ran {
+		t.Fatal("handler after a panicking one did not run")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(recovered) != 1 || recovered[0] != "boom" {
+		t.Fatalf("panic handler got %v, want [boom]", recovered)
+	}
+}
+
+func TestOnSkipsOtherPayloads(t *testing.T) {
+	bus := NewBus()
+	var got []*PartyEventData
+	On(bus, EventPartyUpdate, func(_ *Event, data *PartyEventData) {
+		got = append(got, data)
+	})
+
+	bus.Publish(&Event{Type: EventPartyUpdate, Data: "not party data"})
+	bus.Publish(&Event{Type: EventPartyUpdate, Data: &PartyEventData{PartyID: 7}})
+
+	if len(got) != 1 || got[0].PartyID != 7 {
+		t.Fatalf("On delivered %v, want one payload for party 7", got)
+	}
+}
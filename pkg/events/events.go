@@ -1,6 +1,8 @@
 package events
 
 import (
+	"time"
+
 	"gorelay/pkg/packets"
 )
 
@@ -69,6 +71,43 @@ const (
 	// Additional game events
 	EventGroundDamage
 	EventNotification
+	EventStatChange
+
+	// Trade events
+	EventTradeRequested
+	EventTradeStart
+	EventTradeDone
+
+	// Quest and mission events
+	EventQuestsFetched
+	EventQuestRedeemed
+	EventMissionProgress
+	EventMissionClaimed
+
+	// Party events
+	EventPartyInvite
+	EventPartyJoined
+	EventPartyUpdate
+	EventPartyLeft
+
+	// Queue events
+	EventQueueUpdate
+	EventQueueLeft
+
+	// Guild events
+	EventGuildInvite
+	EventGuildResult
+	EventGuildChat
+
+	// Condition effect events
+	EventEffectGained
+	EventEffectLost
+
+	// Quest target events
+	EventQuestChanged
+
+	// Safety events
+	EventSafetyTriggered
 )
 
 // Event represents an event in the game
@@ -79,74 +118,234 @@ type Event struct {
 	Data   interface{}
 }
 
-// EventEmitter handles event dispatching
-type EventEmitter struct {
-	handlers map[EventType][]func(*Event)
-}
-
-// NewEventEmitter creates a new event emitter
-func NewEventEmitter() *EventEmitter {
-	return &EventEmitter{
-		handlers: make(map[EventType][]func(*Event)),
-	}
-}
-
-// On registers a handler for an event type
-func (e *EventEmitter) On(eventType EventType, handler func(*Event)) {
-	if _, exists := e.handlers[eventType]; !exists {
-		e.handlers[eventType] = make([]func(*Event), 0)
-	}
-	e.handlers[eventType] = append(e.handlers[eventType], handler)
-}
-
-// Off removes a handler for an event type
-func (e *EventEmitter) Off(eventType EventType, handler func(*Event)) {
-	if handlers, exists := e.handlers[eventType]; exists {
-		for i, h := range handlers {
-			if &h == &handler {
-				e.handlers[eventType] = append(handlers[:i], handlers[i+1:]...)
-				break
-			}
-		}
-	}
-}
+// Event data structures
 
-// Emit dispatches an event to all registered handlers
-func (e *EventEmitter) Emit(event *Event) {
-	if handlers, exists := e.handlers[event.Type]; exists {
-		for _, handler := range handlers {
-			handler(event)
-		}
-	}
+// Position is a world position carried in event payloads
+type Position struct {
+	X float32
+	Y float32
 }
 
-// Event data structures
 type PlayerEventData struct {
-	PlayerData interface{} // Will be replaced with proper PlayerData type
-	Position   interface{} // Will be replaced with proper Position type
+	ObjectID int32
+	Name     string
+	Level    int32
+	HP       int32
+	MaxHP    int32
+	Position Position
 }
 
 type EnemyEventData struct {
-	Enemy    interface{} // Will be replaced with proper Enemy type
-	Position interface{} // Will be replaced with proper Position type
+	ObjectID   int32
+	ObjectType int32
+	Name       string
+	HP         int32
+	MaxHP      int32
+	Position   Position
+	God        bool
+	Quest      bool
+
+	// Damage taken so far, by the object ID of the player that dealt it
+	TotalDamage int32
+	DamageTaken map[int32]int32
 }
 
 type ProjectileEventData struct {
 	OwnerID      int32
 	ProjectileID int32
-	Position     interface{} // Will be replaced with proper Position type
+	Position     Position
 	Damage       int32
 }
 
 type MapEventData struct {
-	Width  int32
-	Height int32
-	Name   string
-	Seed   int32
+	Width       int32
+	Height      int32
+	Name        string
+	DisplayName string
+	RealmName   string
+	Seed        int32
 }
 
 type ItemEventData struct {
 	ItemID   int32
 	SlotID   int32
-	Position interface{} // Will be replaced with proper Position type
+	Position Position
+}
+
+// ContainerEventData describes a loot bag or chest and its items
+type ContainerEventData struct {
+	ObjectID   int32
+	ObjectType int32
+	BagType    int32
+	Items      []int32
+	Position   Position
+}
+
+// InventoryEventData describes a change to one of the player's item slots
+type InventoryEventData struct {
+	ObjectID int32
+	SlotID   int32
+	OldItem  int32
+	NewItem  int32
+}
+
+// StatEventData describes a change to one of the player's stats
+type StatEventData struct {
+	ObjectID       int32
+	StatType       int32
+	Name           string
+	OldValue       int32
+	NewValue       int32
+	OldStringValue string
+	NewStringValue string
+}
+
+// EffectEventData describes a condition effect gained or lost by an object
+type EffectEventData struct {
+	ObjectID int32
+	Effect   int32
+	Name     string
+}
+
+// QuestTargetEventData describes the object the quest arrow points at. The
+// type, name and position are zero while the object is not in view.
+type QuestTargetEventData struct {
+	ObjectID   int32
+	ObjectType int32
+	Name       string
+	Position   Position
+	Distance   float32
+}
+
+// SafetyAction is what the safety guard did to keep the player alive
+type SafetyAction string
+
+const (
+	SafetyNexus SafetyAction = "nexus" // escaped to the nexus
+	SafetyHeal  SafetyAction = "heal"  // drank a health potion
+	SafetyMana  SafetyAction = "mana"  // drank a magic potion
+)
+
+// SafetyEventData describes an action taken by the safety guard. HP is the
+// value the guard acted on, which includes damage the server has not
+// confirmed yet.
+type SafetyEventData struct {
+	Action    SafetyAction
+	HP        int32
+	MaxHP     int32
+	MP        int32
+	MaxMP     int32
+	Threshold float32
+}
+
+// ChatKind classifies a chat message
+type ChatKind string
+
+const (
+	ChatPublic       ChatKind = "public"
+	ChatPrivate      ChatKind = "private"
+	ChatGuild        ChatKind = "guild"
+	ChatParty        ChatKind = "party"
+	ChatAnnouncement ChatKind = "announcement"
+	ChatServer       ChatKind = "server"
+	ChatOwn          ChatKind = "own" // echo of a message sent by the client
+)
+
+type ChatEventData struct {
+	Kind      ChatKind
+	Name      string // sender with any channel prefix removed
+	Recipient string
+	Text      string
+	ObjectID  int32
+	NumStars  int32
+}
+
+// DamageEventData describes damage dealt to the player
+type DamageEventData struct {
+	TargetID      int32
+	SourceID      int32 // object that dealt the damage, when known
+	Damage        int32
+	ArmorPiercing bool
+	Position      Position
+}
+
+type NotificationEventData struct {
+	ObjectID int32
+	Type     int32
+	Message  string
+	Color    int32
+}
+
+// TradeEventData describes a trade request, start or result
+type TradeEventData struct {
+	Partner     string
+	Success     bool
+	Description string
+}
+
+// QuestSummary describes a daily quest in event payloads
+type QuestSummary struct {
+	ID           string
+	Name         string
+	Requirements []string
+	Completed    bool
+	Ready        bool
+}
+
+// QuestEventData describes fetched quests or the result of a redeem
+type QuestEventData struct {
+	Quests  []QuestSummary
+	Success bool
+	Message string
+}
+
+// MissionEventData describes mission progress or the result of a claim
+type MissionEventData struct {
+	MissionID  int32
+	Progress   int32
+	Objectives []int32
+	Success    bool
+	Message    string
+	Raw        string // undecoded payload of a multiple missions update
+}
+
+// PartyEventData describes a party invite or a change to the party
+type PartyEventData struct {
+	PartyID  uint32
+	Name     string // inviter or the member the change is about
+	PlayerID int32
+	Members  []string
+}
+
+// QueueEventData describes the client's place in a server queue
+type QueueEventData struct {
+	Server      string
+	Position    int
+	MaxPosition int
+	Waited      time.Duration
+	ETA         time.Duration // 0 until the queue has moved
+}
+
+// GuildEventData describes a guild invite or the result of a guild action
+type GuildEventData struct {
+	Name      string // inviter
+	GuildName string
+	Success   bool
+	Message   string
+}
+
+// ConnectionEventData describes a connection lifecycle change
+type ConnectionEventData struct {
+	Server         string
+	Address        string
+	PreviousServer string
+	Attempt        int
+}
+
+type DeathEventData struct {
+	AccountID string
+	CharID    int32
+	CharName  string
+	KilledBy  string
+	Stats     map[string]int64
 }
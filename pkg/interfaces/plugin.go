@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"gorelay/pkg/client"
+	"gorelay/pkg/events"
 	"gorelay/pkg/packets"
 )
 
@@ -23,4 +24,12 @@ type PluginManager interface {
 	RegisterPacketHook(packetType int32, hook PacketHook)
 	UnregisterPacketHook(packetType int32, hook PacketHook)
 	HandlePacket(packet packets.Packet) error
+
+	// Subscribe registers an event handler on the client's event bus. The
+	// subscription is removed automatically when the plugin is unloaded.
+	Subscribe(eventType events.EventType, handler events.Handler, opts ...events.SubscribeOption) *events.Subscription
+
+	// RegisterCommand registers a chat command handler on the client. The
+	// command is removed automatically when the plugin is unloaded.
+	RegisterCommand(name string, handler client.CommandHandler, opts ...client.CommandOption) error
 }
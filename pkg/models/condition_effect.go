@@ -1,6 +1,10 @@
 package models
 
-// ConditionEffect represents different status effects that can be applied to entities
+import "math/bits"
+
+// ConditionEffect represents different status effects that can be applied to entities.
+// The value is the effect's id in the game, effects 1-31 are bits of the
+// CONDITIONSTAT and effects from 32 on are bits of the NEWCONSTAT.
 type ConditionEffect int32
 
 const (
@@ -21,14 +25,131 @@ const (
 	ConditionEffectParalyzed
 	ConditionEffectSpeedy
 	ConditionEffectBleeding
-	ConditionEffectArmorBroken
-	ConditionEffectBerserk
+	ConditionEffectArmorBrokenImmune
 	ConditionEffectHealing
 	ConditionEffectDamaging
-	ConditionEffectBrave
+	ConditionEffectBerserk
+	ConditionEffectPaused
 	ConditionEffectStasis
+	ConditionEffectStasisImmune
 	ConditionEffectInvincible
 	ConditionEffectInvulnerable
 	ConditionEffectArmored
+	ConditionEffectArmorBroken
+	ConditionEffectHexed
+	ConditionEffectNinjaSpeedy
+	ConditionEffectUnstable
+	ConditionEffectDarkness
+	ConditionEffectSlowedImmune
+	ConditionEffectDazedImmune
+	ConditionEffectParalyzedImmune
+	ConditionEffectPetrified
+	ConditionEffectPetrifiedImmune
+	ConditionEffectPetEffectIcon
 	ConditionEffectCursed
+	ConditionEffectCurseImmune
+	ConditionEffectHPBoost
+	ConditionEffectMPBoost
+	ConditionEffectAttBoost
+	ConditionEffectDefBoost
+	ConditionEffectSpdBoost
+	ConditionEffectVitBoost
+	ConditionEffectWisBoost
+	ConditionEffectDexBoost
+	ConditionEffectSilenced
+	ConditionEffectExposed
+	ConditionEffectEnergized
 )
+
+// conditionEffectNames holds the display names of the condition effects
+var conditionEffectNames = [...]string{
+	"None", "Dead", "Quiet", "Weak", "Slowed", "Sick", "Dazed", "Stunned",
+	"Blind", "Hallucinating", "Drunk", "Confused", "Stun Immune", "Invisible",
+	"Paralyzed", "Speedy", "Bleeding", "Armor Broken Immune", "Healing",
+	"Damaging", "Berserk", "Paused", "Stasis", "Stasis Immune", "Invincible",
+	"Invulnerable", "Armored", "Armor Broken", "Hexed", "Ninja Speedy",
+	"Unstable", "Darkness", "Slowed Immune", "Dazed Immune", "Paralyzed Immune",
+	"Petrified", "Petrified Immune", "Pet Effect Icon", "Cursed", "Curse Immune",
+	"HP Boost", "MP Boost", "Att Boost", "Def Boost", "Spd Boost", "Vit Boost",
+	"Wis Boost", "Dex Boost", "Silenced", "Exposed", "Energized",
+}
+
+// String returns the display name of the effect
+func (e ConditionEffect) String() string {
+	if e >= 0 && int(e) < len(conditionEffectNames) {
+		return conditionEffectNames[e]
+	}
+	return "Unknown"
+}
+
+// bit returns the effect's bit in a ConditionEffects set
+func (e ConditionEffect) bit() uint {
+	if e < 32 {
+		return uint(e - 1)
+	}
+	return uint(e)
+}
+
+// ConditionEffects is the set of effects decoded from the two condition stats.
+// The CONDITIONSTAT occupies the low 32 bits and the NEWCONSTAT the high ones.
+type ConditionEffects uint64
+
+// NewConditionEffects decodes the CONDITIONSTAT and NEWCONSTAT values
+func NewConditionEffects(condition, newCondition int32) ConditionEffects {
+	return ConditionEffects(uint32(condition)) | ConditionEffects(uint32(newCondition))<<32
+}
+
+// WithCondition replaces the effects carried by the CONDITIONSTAT
+func (s ConditionEffects) WithCondition(value int32) ConditionEffects {
+	return s&^0xffffffff | ConditionEffects(uint32(value))
+}
+
+// WithNewCondition replaces the effects carried by the NEWCONSTAT
+func (s ConditionEffects) WithNewCondition(value int32) ConditionEffects {
+	return s&0xffffffff | ConditionEffects(uint32(value))<<32
+}
+
+// With returns the set with an effect added
+func (s ConditionEffects) With(effect ConditionEffect) ConditionEffects {
+	if effect <= ConditionEffectNone {
+		return s
+	}
+	return s | 1<<effect.bit()
+}
+
+// Has reports whether an effect is in the set
+func (s ConditionEffects) Has(effect ConditionEffect) bool {
+	if effect <= ConditionEffectNone {
+		return false
+	}
+	return s&(1<<effect.bit()) != 0
+}
+
+// HasAny reports whether any of the effects is in the set
+func (s ConditionEffects) HasAny(effects ...ConditionEffect) bool {
+	for _, effect := range effects {
+		if s.Has(effect) {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the effects in the set, ordered by id
+func (s ConditionEffects) List() []ConditionEffect {
+	var list []ConditionEffect
+	for rest := uint64(s); rest != 0; rest &= rest - 1 {
+		bit := bits.TrailingZeros64(rest)
+		effect := ConditionEffect(bit)
+		if bit < 32 {
+			effect = ConditionEffect(bit + 1)
+		}
+		list = append(list, effect)
+	}
+	return list
+}
+
+// Diff returns the effects gained and lost since a previous set
+func (s ConditionEffects) Diff(previous ConditionEffects) (gained, lost []ConditionEffect) {
+	return (s &^ previous).List(), (previous &^ s).List()
+}
@@ -0,0 +1,81 @@
+package models
+
+import "testing"
+
+func TestNewConditionEffectsDecodesBothStats(t *testing.T) {
+	// Effect n < 32 is bit n-1 of the CONDITIONSTAT, effect n >= 32 is bit
+	// n-32 of the NEWCONSTAT
+	condition := int32(1<<(ConditionEffectQuiet-1) | 1<<(ConditionEffectArmorBroken-1))
+	newCondition := int32(1<<(ConditionEffectSlowedImmune-32) | 1<<(ConditionEffectExposed-32))
+	effects := NewConditionEffects(condition, newCondition)
+
+	want := []ConditionEffect{
+		ConditionEffectQuiet,
+		ConditionEffectArmorBroken,
+		ConditionEffectSlowedImmune,
+		ConditionEffectExposed,
+	}
+	for _, effect := range want {
+		if !effects.Has(effect) {
+			t.Errorf("missing %s", effect)
+		}
+	}
+	for _, effect := range []ConditionEffect{ConditionEffectNone, ConditionEffectDead, ConditionEffectDarkness, ConditionEffectEnergized} {
+		if effects.Has(effect) {
+			t.Errorf("unexpected %s", effect)
+		}
+	}
+
+	list := effects.List()
+	if len(list) != len(want) {
+		t.Fatalf("List() = %v, want %v", list, want)
+	}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Fatalf("List() = %v, want %v", list, want)
+		}
+	}
+}
+
+func TestConditionEffectsSignBit(t *testing.T) {
+	// Stats are signed, the top effect of the NEWCONSTAT arrives as a negative value
+	effects := NewConditionEffects(-1<<31, -1<<31)
+	if effects.WithCondition(0) != ConditionEffects(1)<<63 {
+		t.Fatalf("sign bit of the NEWCONSTAT was not kept: %x", uint64(effects))
+	}
+	if effects.WithNewCondition(0) != ConditionEffects(1)<<31 {
+		t.Fatalf("sign bit of the CONDITIONSTAT leaked: %x", uint64(effects))
+	}
+}
+
+func TestConditionEffectsUpdatesOneStat(t *testing.T) {
+	effects := NewConditionEffects(0, 0).
+		With(ConditionEffectSlowed).
+		With(ConditionEffectCursed)
+
+	effects = effects.WithCondition(int32(1 << (ConditionEffectStunned - 1)))
+	if effects.Has(ConditionEffectSlowed) || !effects.Has(ConditionEffectStunned) {
+		t.Fatal("WithCondition did not replace the CONDITIONSTAT effects")
+	}
+	if !effects.Has(ConditionEffectCursed) {
+		t.Fatal("WithCondition dropped a NEWCONSTAT effect")
+	}
+
+	effects = effects.WithNewCondition(0)
+	if effects.Has(ConditionEffectCursed) || !effects.Has(ConditionEffectStunned) {
+		t.Fatal("WithNewCondition did not replace only the NEWCONSTAT effects")
+	}
+}
+
+func TestConditionEffectsDiff(t *testing.T) {
+	before := NewConditionEffects(0, 0).With(ConditionEffectWeak).With(ConditionEffectHexed)
+	after := NewConditionEffects(0, 0).With(ConditionEffectHexed).With(ConditionEffectExposed)
+
+	gained, lost := after.Diff(before)
+	if len(gained) != 1 || gained[0] != ConditionEffectExposed {
+		t.Errorf("gained %v, want [Exposed]", gained)
+	}
+	if len(lost) != 1 || lost[0] != ConditionEffectWeak {
+		t.Errorf("lost %v, want [Weak]", lost)
+	}
+}
@@ -67,6 +67,7 @@ type Container struct {
 	Items     []int32
 	Slots     int32
 	OwnerId   int32
+	BagType   int32
 	UpdatedAt time.Time
 }
 
@@ -49,6 +49,12 @@ var CachedServers ServerList
 
 // FetchServers retrieves the current server list from the ROTMG API
 func FetchServers(guid string, password string) (ServerList, error) {
+	return FetchServersWithClient(&http.Client{}, guid, password)
+}
+
+// FetchServersWithClient retrieves the server list using the given HTTP client,
+// e.g. one that routes through an account's proxy
+func FetchServersWithClient(client *http.Client, guid string, password string) (ServerList, error) {
 	// Check for empty credentials
 	if guid == "" {
 		return nil, fmt.Errorf("empty email/guid provided")
@@ -67,8 +73,7 @@ func FetchServers(guid string, password string) (ServerList, error) {
 	// Log the request URL for debugging (without the password)
 	fmt.Printf("Fetching servers from URL: https://www.realmofthemadgod.com/account/servers?guid=%s&password=REDACTED\n", encodedGuid)
 
-	// Create a custom HTTP client with appropriate headers
-	client := &http.Client{}
+	// Create the request with appropriate headers
 	req, err := http.NewRequest("GET", requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
@@ -147,6 +152,15 @@ func GetServer(name string) *Server {
 	return DefaultServer // Absolute fallback if no servers available
 }
 
+// LookupServer returns the cached server with the given name, if it is known
+func LookupServer(name string) (*Server, bool) {
+	if CachedServers == nil {
+		return nil, false
+	}
+	server, ok := CachedServers[name]
+	return server, ok
+}
+
 // ServerList represents a list of available game servers
 type ServerListStruct struct {
 	Servers  []Server `json:"servers"`
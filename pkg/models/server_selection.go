@@ -0,0 +1,315 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Server selection strategies
+const (
+	SelectionPreferred     = "preferred"     // preferred list in order, then least loaded
+	SelectionLeastLoaded   = "leastLoaded"   // lowest reported usage
+	SelectionLowestLatency = "lowestLatency" // fastest measured TCP connect
+	SelectionSticky        = "sticky"        // keep each account on the server it used last
+)
+
+// ServerSelector chooses which server an account should connect to
+type ServerSelector interface {
+	Select(servers ServerList, accountKey string) (*Server, error)
+}
+
+// NewServerSelector creates a selector for the given strategy name. Unknown or
+// empty strategies fall back to the preferred list strategy.
+func NewServerSelector(strategy string, preferred []string) ServerSelector {
+	switch strategy {
+	case SelectionLeastLoaded:
+		return &LeastLoadedSelector{}
+	case SelectionLowestLatency:
+		return NewLowestLatencySelector(2*time.Second, time.Minute)
+	case SelectionSticky:
+		return NewStickySelector(&PreferredSelector{Preferred: preferred, Fallback: &LeastLoadedSelector{}})
+	default:
+		return &PreferredSelector{Preferred: preferred, Fallback: &LeastLoadedSelector{}}
+	}
+}
+
+// PreferredSelector picks the first healthy server from an ordered list of names
+type PreferredSelector struct {
+	Preferred []string
+	Fallback  ServerSelector
+}
+
+// Select implements ServerSelector
+func (s *PreferredSelector) Select(servers ServerList, accountKey string) (*Server, error) {
+	candidates := HealthyServers(servers)
+	for _, name := range s.Preferred {
+		if server, ok := candidates[name]; ok {
+			return server, nil
+		}
+	}
+	if s.Fallback != nil {
+		return s.Fallback.Select(servers, accountKey)
+	}
+	return firstServer(candidates)
+}
+
+// LeastLoadedSelector picks the healthy server with the lowest reported usage
+type LeastLoadedSelector struct{}
+
+// Select implements ServerSelector
+func (s *LeastLoadedSelector) Select(servers ServerList, accountKey string) (*Server, error) {
+	sorted := sortedServers(HealthyServers(servers))
+	if len(sorted) == 0 {
+		return nil, fmt.Errorf("no servers available")
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Usage < sorted[j].Usage
+	})
+	return sorted[0], nil
+}
+
+// LowestLatencySelector picks the healthy server with the fastest TCP connect time
+type LowestLatencySelector struct {
+	Timeout time.Duration
+	MaxAge  time.Duration
+
+	mu       sync.Mutex
+	measured map[string]latencySample
+}
+
+type latencySample struct {
+	latency time.Duration
+	at      time.Time
+}
+
+// NewLowestLatencySelector creates a latency selector. Measurements are reused
+// for maxAge before servers are probed again.
+func NewLowestLatencySelector(timeout, maxAge time.Duration) *LowestLatencySelector {
+	return &LowestLatencySelector{
+		Timeout:  timeout,
+		MaxAge:   maxAge,
+		measured: make(map[string]latencySample),
+	}
+}
+
+// Select implements ServerSelector
+func (s *LowestLatencySelector) Select(servers ServerList, accountKey string) (*Server, error) {
+	sorted := sortedServers(HealthyServers(servers))
+	if len(sorted) == 0 {
+		return nil, fmt.Errorf("no servers available")
+	}
+
+	latencies := s.measure(sorted)
+
+	var best *Server
+	var bestLatency time.Duration
+	for _, server := range sorted {
+		latency, ok := latencies[server.Name]
+		if !ok {
+			continue
+		}
+		if best == nil || latency < bestLatency {
+			best = server
+			bestLatency = latency
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no servers reachable")
+	}
+	return best, nil
+}
+
+// Latency returns the last measured connect latency for a server
+func (s *LowestLatencySelector) Latency(name string) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample, ok := s.measured[name]
+	return sample.latency, ok
+}
+
+// measure probes every server without a fresh sample concurrently
+func (s *LowestLatencySelector) measure(servers []*Server) map[string]time.Duration {
+	now := time.Now()
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		s.mu.Lock()
+		sample, ok := s.measured[server.Name]
+		s.mu.Unlock()
+		if ok && now.Sub(sample.at) < s.MaxAge {
+			continue
+		}
+
+		wg.Add(1)
+		go func(server *Server) {
+			defer wg.Done()
+			latency, err := MeasureLatency(server, s.Timeout)
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if err != nil {
+				delete(s.measured, server.Name)
+				MarkServerFailed(server.Name)
+				return
+			}
+			s.measured[server.Name] = latencySample{latency: latency, at: time.Now()}
+		}(server)
+	}
+	wg.Wait()
+
+	results := make(map[string]time.Duration)
+	s.mu.Lock()
+	for _, server := range servers {
+		if sample, ok := s.measured[server.Name]; ok {
+			results[server.Name] = sample.latency
+		}
+	}
+	s.mu.Unlock()
+	return results
+}
+
+// StickySelector keeps each account on the server it was last assigned
+type StickySelector struct {
+	Fallback ServerSelector
+
+	mu          sync.Mutex
+	assignments map[string]string
+}
+
+// NewStickySelector creates a sticky selector that uses fallback for new accounts
+func NewStickySelector(fallback ServerSelector) *StickySelector {
+	return &StickySelector{
+		Fallback:    fallback,
+		assignments: make(map[string]string),
+	}
+}
+
+// Remember assigns a server to an account, e.g. from a previous session
+func (s *StickySelector) Remember(accountKey, serverName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assignments[accountKey] = serverName
+}
+
+// Select implements ServerSelector
+func (s *StickySelector) Select(servers ServerList, accountKey string) (*Server, error) {
+	s.mu.Lock()
+	name, ok := s.assignments[accountKey]
+	s.mu.Unlock()
+
+	if ok {
+		if server, exists := HealthyServers(servers)[name]; exists {
+			return server, nil
+		}
+	}
+
+	fallback := s.Fallback
+	if fallback == nil {
+		fallback = &LeastLoadedSelector{}
+	}
+	server, err := fallback.Select(servers, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	s.Remember(accountKey, server.Name)
+	return server, nil
+}
+
+// MeasureLatency returns how long a TCP connect to the server takes
+func MeasureLatency(server *Server, timeout time.Duration) (time.Duration, error) {
+	port := server.Port
+	if port == 0 {
+		port = 2050
+	}
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(server.Address, strconv.Itoa(port)), timeout)
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+	return time.Since(start), nil
+}
+
+// serverHealth tracks servers that recently failed so selectors can skip them
+var serverHealth = struct {
+	sync.Mutex
+	failedAt map[string]time.Time
+	cooldown time.Duration
+}{
+	failedAt: make(map[string]time.Time),
+	cooldown: 5 * time.Minute,
+}
+
+// SetServerFailureCooldown sets how long a failing server is excluded from selection
+func SetServerFailureCooldown(cooldown time.Duration) {
+	serverHealth.Lock()
+	defer serverHealth.Unlock()
+	serverHealth.cooldown = cooldown
+}
+
+// MarkServerFailed excludes a server from selection until the cooldown expires
+func MarkServerFailed(name string) {
+	serverHealth.Lock()
+	defer serverHealth.Unlock()
+	serverHealth.failedAt[name] = time.Now()
+}
+
+// MarkServerHealthy clears any recorded failure for a server
+func MarkServerHealthy(name string) {
+	serverHealth.Lock()
+	defer serverHealth.Unlock()
+	delete(serverHealth.failedAt, name)
+}
+
+// IsServerHealthy reports whether a server is currently eligible for selection
+func IsServerHealthy(name string) bool {
+	serverHealth.Lock()
+	defer serverHealth.Unlock()
+	failedAt, ok := serverHealth.failedAt[name]
+	if !ok {
+		return true
+	}
+	if time.Since(failedAt) >= serverHealth.cooldown {
+		delete(serverHealth.failedAt, name)
+		return true
+	}
+	return false
+}
+
+// HealthyServers returns the servers that have not failed recently. If every
+// server has failed the full list is returned so there is always a candidate.
+func HealthyServers(servers ServerList) ServerList {
+	healthy := make(ServerList)
+	for name, server := range servers {
+		if IsServerHealthy(name) {
+			healthy[name] = server
+		}
+	}
+	if len(healthy) == 0 {
+		return servers
+	}
+	return healthy
+}
+
+// sortedServers returns the servers ordered by name so selection is deterministic
+func sortedServers(servers ServerList) []*Server {
+	sorted := make([]*Server, 0, len(servers))
+	for _, server := range servers {
+		sorted = append(sorted, server)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// firstServer returns the alphabetically first server
+func firstServer(servers ServerList) (*Server, error) {
+	sorted := sortedServers(servers)
+	if len(sorted) == 0 {
+		return nil, fmt.Errorf("no servers available")
+	}
+	return sorted[0], nil
+}
@@ -0,0 +1,286 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// VaultChest identifies one of an account's storage chests
+type VaultChest string
+
+// Storage chests reported by VaultContent
+const (
+	ChestVault    VaultChest = "vault"
+	ChestMaterial VaultChest = "material"
+	ChestGift     VaultChest = "gift"
+	ChestPotion   VaultChest = "potion"
+	ChestSpoils   VaultChest = "spoils"
+	ChestInMap    VaultChest = "chest" // classic chest object seen in the vault map
+)
+
+// VaultRecord mirrors the storage of one account
+type VaultRecord struct {
+	Alias     string    `json:"alias"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Vault    []int32 `json:"vault"`
+	Material []int32 `json:"material"`
+	Gift     []int32 `json:"gift"`
+	Potions  []int32 `json:"potions"`
+	Spoils   []int32 `json:"spoils"`
+
+	// Chests holds classic vault chests by object ID. Object IDs change between
+	// visits, so the map is replaced every time the vault is entered.
+	Chests map[int32][]int32 `json:"chests,omitempty"`
+
+	VaultUpgradeCost    int16  `json:"vaultUpgradeCost"`
+	MaterialUpgradeCost int16  `json:"materialUpgradeCost"`
+	PotionUpgradeCost   int16  `json:"potionUpgradeCost"`
+	CurrentPotionMax    int16  `json:"currentPotionMax"`
+	NextPotionMax       int16  `json:"nextPotionMax"`
+	VaultEnchants       string `json:"vaultEnchants,omitempty"`
+	GiftEnchants        string `json:"giftEnchants,omitempty"`
+	SpoilsEnchants      string `json:"spoilsEnchants,omitempty"`
+}
+
+// Contents returns the items of a chest
+func (r *VaultRecord) Contents(chest VaultChest) []int32 {
+	switch chest {
+	case ChestVault:
+		return r.Vault
+	case ChestMaterial:
+		return r.Material
+	case ChestGift:
+		return r.Gift
+	case ChestPotion:
+		return r.Potions
+	case ChestSpoils:
+		return r.Spoils
+	}
+	return nil
+}
+
+// SetContents replaces the items of a chest
+func (r *VaultRecord) SetContents(chest VaultChest, items []int32) {
+	switch chest {
+	case ChestVault:
+		r.Vault = items
+	case ChestMaterial:
+		r.Material = items
+	case ChestGift:
+		r.Gift = items
+	case ChestPotion:
+		r.Potions = items
+	case ChestSpoils:
+		r.Spoils = items
+	}
+}
+
+// Clone returns a deep copy of the record
+func (r *VaultRecord) Clone() *VaultRecord {
+	clone := *r
+	clone.Vault = append([]int32(nil), r.Vault...)
+	clone.Material = append([]int32(nil), r.Material...)
+	clone.Gift = append([]int32(nil), r.Gift...)
+	clone.Potions = append([]int32(nil), r.Potions...)
+	clone.Spoils = append([]int32(nil), r.Spoils...)
+	if r.Chests != nil {
+		clone.Chests = make(map[int32][]int32, len(r.Chests))
+		for id, items := range r.Chests {
+			clone.Chests[id] = append([]int32(nil), items...)
+		}
+	}
+	return &clone
+}
+
+// VaultItemLocation is where a stored item was found
+type VaultItemLocation struct {
+	Alias    string     `json:"alias"`
+	Chest    VaultChest `json:"chest"`
+	ChestID  int32      `json:"chestId,omitempty"` // object ID of classic chests
+	Slot     int        `json:"slot"`
+	ItemType int32      `json:"itemType"`
+}
+
+// VaultStorage keeps the vault records of every account and persists them to
+// one JSON file per account
+type VaultStorage struct {
+	dir     string
+	mu      sync.RWMutex
+	records map[string]*VaultRecord
+	files   map[string]*sync.Mutex // serializes writes to each account's file
+}
+
+// NewVaultStorage creates a storage in dir and loads the records saved there
+func NewVaultStorage(dir string) (*VaultStorage, error) {
+	storage := &VaultStorage{
+		dir:     dir,
+		records: make(map[string]*VaultRecord),
+		files:   make(map[string]*sync.Mutex),
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create vault directory: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vault files: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vault file %s: %v", file, err)
+		}
+		var record VaultRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to parse vault file %s: %v", file, err)
+		}
+		if record.Alias != "" {
+			storage.records[record.Alias] = &record
+		}
+	}
+	return storage, nil
+}
+
+// Get returns a copy of an account's record, or nil if it was never seen
+func (s *VaultStorage) Get(alias string) *VaultRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.records[alias]
+	if !ok {
+		return nil
+	}
+	return record.Clone()
+}
+
+// Records returns a copy of every record, sorted by alias
+func (s *VaultStorage) Records() []*VaultRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records := make([]*VaultRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record.Clone())
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Alias < records[j].Alias
+	})
+	return records
+}
+
+// Update stores a record and writes it to disk
+func (s *VaultStorage) Update(record *VaultRecord) error {
+	s.Store(record)
+	return s.Flush(record.Alias)
+}
+
+// Store replaces an account's record in memory. Flush writes it to disk.
+func (s *VaultStorage) Store(record *VaultRecord) {
+	record = record.Clone()
+	record.UpdatedAt = time.Now()
+
+	s.mu.Lock()
+	s.records[record.Alias] = record
+	s.mu.Unlock()
+}
+
+// Flush writes the latest record of an account to disk. Writes of one account
+// are serialized and each writes the record stored at that time, so a slow
+// write never replaces the file with an older record.
+func (s *VaultStorage) Flush(alias string) error {
+	s.mu.Lock()
+	fileMu, ok := s.files[alias]
+	if !ok {
+		fileMu = &sync.Mutex{}
+		s.files[alias] = fileMu
+	}
+	s.mu.Unlock()
+
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	record := s.Get(alias)
+	if record == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault: %v", err)
+	}
+	return writeFileAtomic(filepath.Join(s.dir, vaultFileName(alias)), data)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// over path, so readers never see a partly written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create vault file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write vault file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write vault file: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write vault file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace vault file: %v", err)
+	}
+	return nil
+}
+
+// FindItem returns every stored copy of an item type across all accounts
+func (s *VaultStorage) FindItem(itemType int32) []VaultItemLocation {
+	locations := make([]VaultItemLocation, 0)
+	for _, record := range s.Records() {
+		for _, chest := range []VaultChest{ChestVault, ChestMaterial, ChestGift, ChestPotion, ChestSpoils} {
+			for slot, item := range record.Contents(chest) {
+				if item == itemType {
+					locations = append(locations, VaultItemLocation{
+						Alias: record.Alias, Chest: chest, Slot: slot, ItemType: item,
+					})
+				}
+			}
+		}
+		for id, items := range record.Chests {
+			for slot, item := range items {
+				if item == itemType {
+					locations = append(locations, VaultItemLocation{
+						Alias: record.Alias, Chest: ChestInMap, ChestID: id, Slot: slot, ItemType: item,
+					})
+				}
+			}
+		}
+	}
+	return locations
+}
+
+// vaultFileName turns an alias into a safe file name. The alias is hex encoded,
+// so distinct aliases never share a file.
+func vaultFileName(alias string) string {
+	return hex.EncodeToString([]byte(alias)) + ".json"
+}
+
+// Global vault storage shared by every client
+var vaultStorage *VaultStorage
+
+// GetVaultStorage returns the shared vault storage, nil if none was set
+func GetVaultStorage() *VaultStorage {
+	return vaultStorage
+}
+
+// SetVaultStorage sets the shared vault storage
+func SetVaultStorage(storage *VaultStorage) {
+	vaultStorage = storage
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestVaultStorageReloadsRecords(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewVaultStorage(dir)
+	if err != nil {
+		t.Fatalf("NewVaultStorage failed: %v", err)
+	}
+	record := &VaultRecord{
+		Alias:   "main/1",
+		Vault:   []int32{-1, 2591},
+		Potions: []int32{2594},
+		Chests:  map[int32][]int32{77: {2592}},
+	}
+	if err := storage.Update(record); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	record.Vault[1] = 0
+	if got := storage.Get("main/1"); got.Vault[1] != 2591 {
+		t.Fatal("storage shares the record passed to Update")
+	}
+
+	reloaded, err := NewVaultStorage(dir)
+	if err != nil {
+		t.Fatalf("NewVaultStorage failed: %v", err)
+	}
+	got := reloaded.Get("main/1")
+	if got == nil || got.Vault[1] != 2591 || got.Potions[0] != 2594 || got.Chests[77][0] != 2592 {
+		t.Fatalf("reloaded record %+v does not match the saved one", got)
+	}
+}
+
+func TestVaultStorageConcurrentUpdates(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewVaultStorage(dir)
+	if err != nil {
+		t.Fatalf("NewVaultStorage failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := int32(0); i < 20; i++ {
+		wg.Add(1)
+		go func(i int32) {
+			defer wg.Done()
+			if err := storage.Update(&VaultRecord{Alias: "alt", Vault: []int32{i}}); err != nil {
+				t.Errorf("Update failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Whichever update won, the file must hold the record kept in memory
+	reloaded, err := NewVaultStorage(dir)
+	if err != nil {
+		t.Fatalf("NewVaultStorage failed: %v", err)
+	}
+	if got, want := reloaded.Get("alt").Vault[0], storage.Get("alt").Vault[0]; got != want {
+		t.Fatalf("file holds vault item %d, memory holds %d", got, want)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 1 {
+		t.Fatalf("vault directory holds %v, want one file", files)
+	}
+}
+
+func TestVaultStorageFlushWithoutRecord(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewVaultStorage(dir)
+	if err != nil {
+		t.Fatalf("NewVaultStorage failed: %v", err)
+	}
+	if err := storage.Flush("unknown"); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("Flush of an unknown account wrote %d files", len(entries))
+	}
+}
+
+func TestVaultFileName(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"main/1", "main_1"},
+		{"../x", "..x"},
+		{"Main", "main"},
+	}
+	for _, tt := range tests {
+		a, b := vaultFileName(tt.a), vaultFileName(tt.b)
+		if a == b {
+			t.Errorf("%q and %q share the file %s", tt.a, tt.b, a)
+		}
+		if filepath.Base(a) != a {
+			t.Errorf("file name %s for %q leaves the vault directory", a, tt.a)
+		}
+	}
+}
+
+func TestVaultFindItem(t *testing.T) {
+	storage, err := NewVaultStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewVaultStorage failed: %v", err)
+	}
+	storage.Store(&VaultRecord{Alias: "b", Gift: []int32{5, 9}})
+	storage.Store(&VaultRecord{Alias: "a", Vault: []int32{9}, Chests: map[int32][]int32{40: {-1, 9}}})
+
+	got := storage.FindItem(9)
+	want := []VaultItemLocation{
+		{Alias: "a", Chest: ChestVault, Slot: 0, ItemType: 9},
+		{Alias: "a", Chest: ChestInMap, ChestID: 40, Slot: 1, ItemType: 9},
+		{Alias: "b", Chest: ChestGift, Slot: 1, ItemType: 9},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindItem() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("FindItem() = %+v, want %+v", got, want)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
@@ -11,8 +10,8 @@ type Location struct {
 	Y float32
 }
 
-// NewLocation creates a new Location from a packet reader
-func NewLocation(r *packets.PacketReader) (*Location, error) {
+// NewLocation creates a new Location from a reader
+func NewLocation(r interfaces.Reader) (*Location, error) {
 	x, err := r.ReadFloat32()
 	if err != nil {
 		return nil, err
@@ -26,8 +25,8 @@ func NewLocation(r *packets.PacketReader) (*Location, error) {
 	return &Location{X: x, Y: y}, nil
 }
 
-// Write writes the location to a packet writer
-func (l *Location) Write(w *packets.PacketWriter) error {
+// Write writes the location to a writer
+func (l *Location) Write(w interfaces.Writer) error {
 	if err := w.WriteFloat32(l.X); err != nil {
 		return err
 	}
@@ -36,16 +35,13 @@ func (l *Location) Write(w *packets.PacketWriter) error {
 
 // AOEAck represents an area of effect acknowledgment packet
 type AOEAck struct {
-	*packets.BasePacket
 	Time     int32
 	Position *Location
 }
 
 // NewAOEAck creates a new AOEAck packet
 func NewAOEAck() *AOEAck {
-	return &AOEAck{
-		BasePacket: packets.NewPacket(interfaces.AOEAck, byte(interfaces.AOEAck)),
-	}
+	return &AOEAck{}
 }
 
 // Type returns the packet type
@@ -53,8 +49,13 @@ func (p *AOEAck) Type() interfaces.PacketType {
 	return interfaces.AOEAck
 }
 
+// ID returns the packet ID
+func (p *AOEAck) ID() int32 {
+	return int32(interfaces.AOEAck)
+}
+
 // Read reads the packet data from the reader
-func (p *AOEAck) Read(r *packets.PacketReader) error {
+func (p *AOEAck) Read(r interfaces.Reader) error {
 	var err error
 	p.Time, err = r.ReadInt32()
 	if err != nil {
@@ -70,7 +71,7 @@ func (p *AOEAck) Read(r *packets.PacketReader) error {
 }
 
 // Write writes the packet data to the writer
-func (p *AOEAck) Write(w *packets.PacketWriter) error {
+func (p *AOEAck) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.Time); err != nil {
 		return err
 	}
@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // AcceptTrade represents a trade acceptance packet
 type AcceptTrade struct {
-	*packets.BasePacket
 	MyOffers   []bool
 	YourOffers []bool
 }
 
 // NewAcceptTrade creates a new AcceptTrade packet
 func NewAcceptTrade() *AcceptTrade {
-	return &AcceptTrade{
-		BasePacket: packets.NewPacket(interfaces.AcceptTrade, byte(interfaces.AcceptTrade)),
-	}
+	return &AcceptTrade{}
 }
 
 // Type returns the packet type
@@ -24,6 +20,11 @@ func (p *AcceptTrade) Type() interfaces.PacketType {
 	return interfaces.AcceptTrade
 }
 
+// ID returns the packet ID
+func (p *AcceptTrade) ID() int32 {
+	return int32(interfaces.AcceptTrade)
+}
+
 // Read reads the packet data from the reader
 func (p *AcceptTrade) Read(r interfaces.Reader) error {
 	// Read MyOffers
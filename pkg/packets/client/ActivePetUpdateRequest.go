@@ -1,7 +1,6 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
@@ -14,16 +13,13 @@ const (
 
 // ActivePetUpdateRequest represents a pet update request packet
 type ActivePetUpdateRequest struct {
-	*packets.BasePacket
 	CommandID byte
 	PetID     uint32
 }
 
 // NewActivePetUpdateRequest creates a new ActivePetUpdateRequest packet
 func NewActivePetUpdateRequest() *ActivePetUpdateRequest {
-	return &ActivePetUpdateRequest{
-		BasePacket: packets.NewPacket(interfaces.ActivePetUpdateRequest, byte(interfaces.ActivePetUpdateRequest)),
-	}
+	return &ActivePetUpdateRequest{}
 }
 
 // Type returns the packet type
@@ -31,8 +27,13 @@ func (p *ActivePetUpdateRequest) Type() interfaces.PacketType {
 	return interfaces.ActivePetUpdateRequest
 }
 
+// ID returns the packet ID
+func (p *ActivePetUpdateRequest) ID() int32 {
+	return int32(interfaces.ActivePetUpdateRequest)
+}
+
 // Read reads the packet data from the reader
-func (p *ActivePetUpdateRequest) Read(r *packets.PacketReader) error {
+func (p *ActivePetUpdateRequest) Read(r interfaces.Reader) error {
 	var err error
 	p.CommandID, err = r.ReadByte()
 	if err != nil {
@@ -44,7 +45,7 @@ func (p *ActivePetUpdateRequest) Read(r *packets.PacketReader) error {
 }
 
 // Write writes the packet data to the writer
-func (p *ActivePetUpdateRequest) Write(w *packets.PacketWriter) error {
+func (p *ActivePetUpdateRequest) Write(w interfaces.Writer) error {
 	if err := w.WriteByte(p.CommandID); err != nil {
 		return err
 	}
@@ -1,21 +1,17 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // BoostBPMilestone represents a battle pass milestone boost packet
 type BoostBPMilestone struct {
-	*packets.BasePacket
 	MilestoneID byte
 }
 
 // NewBoostBPMilestone creates a new BoostBPMilestone packet
 func NewBoostBPMilestone() *BoostBPMilestone {
-	return &BoostBPMilestone{
-		BasePacket: packets.NewPacket(interfaces.BoostBPMilestone, byte(interfaces.BoostBPMilestone)),
-	}
+	return &BoostBPMilestone{}
 }
 
 // Type returns the packet type
@@ -23,14 +19,19 @@ func (p *BoostBPMilestone) Type() interfaces.PacketType {
 	return interfaces.BoostBPMilestone
 }
 
+// ID returns the packet ID
+func (p *BoostBPMilestone) ID() int32 {
+	return int32(interfaces.BoostBPMilestone)
+}
+
 // Read reads the packet data from the reader
-func (p *BoostBPMilestone) Read(r *packets.PacketReader) error {
+func (p *BoostBPMilestone) Read(r interfaces.Reader) error {
 	var err error
 	p.MilestoneID, err = r.ReadByte()
 	return err
 }
 
 // Write writes the packet data to the writer
-func (p *BoostBPMilestone) Write(w *packets.PacketWriter) error {
+func (p *BoostBPMilestone) Write(w interfaces.Writer) error {
 	return w.WriteByte(p.MilestoneID)
 }
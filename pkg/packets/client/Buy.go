@@ -7,22 +7,18 @@
 package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Buy represents a purchase packet
 type Buy struct {
-	*packets.BasePacket
 	ObjectID int32
 	Quantity int32
 }
 
 // NewBuy creates a new Buy packet
 func NewBuy() *Buy {
-	return &Buy{
-		BasePacket: packets.NewPacket(interfaces.Buy, byte(interfaces.Buy)),
-	}
+	return &Buy{}
 }
 
 // Type returns the packet type
@@ -30,8 +26,13 @@ func (p *Buy) Type() interfaces.PacketType {
 	return interfaces.Buy
 }
 
+// ID returns the packet ID
+func (p *Buy) ID() int32 {
+	return int32(interfaces.Buy)
+}
+
 // Read reads the packet data from the reader
-func (p *Buy) Read(r *packets.PacketReader) error {
+func (p *Buy) Read(r interfaces.Reader) error {
 	var err error
 	p.ObjectID, err = r.ReadInt32()
 	if err != nil {
@@ -47,7 +48,7 @@ func (p *Buy) Read(r *packets.PacketReader) error {
 }
 
 // Write writes the packet data to the writer
-func (p *Buy) Write(w *packets.PacketWriter) error {
+func (p *Buy) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.ObjectID); err != nil {
 		return err
 	}
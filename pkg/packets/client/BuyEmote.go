@@ -1,21 +1,17 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // BuyEmote represents an emote purchase packet
 type BuyEmote struct {
-	*packets.BasePacket
 	EmoteID int32
 }
 
 // NewBuyEmote creates a new BuyEmote packet
 func NewBuyEmote() *BuyEmote {
-	return &BuyEmote{
-		BasePacket: packets.NewPacket(interfaces.BuyEmote, byte(interfaces.BuyEmote)),
-	}
+	return &BuyEmote{}
 }
 
 // Type returns the packet type
@@ -23,14 +19,19 @@ func (p *BuyEmote) Type() interfaces.PacketType {
 	return interfaces.BuyEmote
 }
 
+// ID returns the packet ID
+func (p *BuyEmote) ID() int32 {
+	return int32(interfaces.BuyEmote)
+}
+
 // Read reads the packet data from the reader
-func (p *BuyEmote) Read(r *packets.PacketReader) error {
+func (p *BuyEmote) Read(r interfaces.Reader) error {
 	var err error
 	p.EmoteID, err = r.ReadInt32()
 	return err
 }
 
 // Write writes the packet data to the writer
-func (p *BuyEmote) Write(w *packets.PacketWriter) error {
+func (p *BuyEmote) Write(w interfaces.Writer) error {
 	return w.WriteInt32(p.EmoteID)
 }
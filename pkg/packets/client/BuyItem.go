@@ -1,21 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // BuyItem represents an item purchase packet
 type BuyItem struct {
-	*packets.BasePacket
 	ItemIDs []int32
 }
 
 // NewBuyItem creates a new BuyItem packet
 func NewBuyItem() *BuyItem {
 	return &BuyItem{
-		BasePacket: packets.NewPacket(interfaces.BuyItem, byte(interfaces.BuyItem)),
-		ItemIDs:    make([]int32, 0),
+		ItemIDs: make([]int32, 0),
 	}
 }
 
@@ -24,8 +21,13 @@ func (p *BuyItem) Type() interfaces.PacketType {
 	return interfaces.BuyItem
 }
 
+// ID returns the packet ID
+func (p *BuyItem) ID() int32 {
+	return int32(interfaces.BuyItem)
+}
+
 // Read reads the packet data from the reader
-func (p *BuyItem) Read(r *packets.PacketReader) error {
+func (p *BuyItem) Read(r interfaces.Reader) error {
 	// Read array length
 	length, err := r.ReadInt16()
 	if err != nil {
@@ -45,7 +47,7 @@ func (p *BuyItem) Read(r *packets.PacketReader) error {
 }
 
 // Write writes the packet data to the writer
-func (p *BuyItem) Write(w *packets.PacketWriter) error {
+func (p *BuyItem) Write(w interfaces.Writer) error {
 	// Write array length
 	if err := w.WriteInt16(int16(len(p.ItemIDs))); err != nil {
 		return err
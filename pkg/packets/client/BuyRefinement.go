@@ -1,14 +1,12 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/dataobjects"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // BuyRefinement represents a packet for buying refinements
 type BuyRefinement struct {
-	*packets.BasePacket
 	Slot   *dataobjects.SlotObject
 	Action int16
 }
@@ -16,8 +14,7 @@ type BuyRefinement struct {
 // NewBuyRefinement creates a new BuyRefinement packet
 func NewBuyRefinement() *BuyRefinement {
 	return &BuyRefinement{
-		BasePacket: packets.NewPacket(interfaces.BuyRefinement, byte(interfaces.BuyRefinement)),
-		Slot:       dataobjects.NewSlotObject(),
+		Slot: dataobjects.NewSlotObject(),
 	}
 }
 
@@ -26,8 +23,13 @@ func (p *BuyRefinement) Type() interfaces.PacketType {
 	return interfaces.BuyRefinement
 }
 
-// Read reads the packet data from a PacketReader
-func (p *BuyRefinement) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *BuyRefinement) ID() int32 {
+	return int32(interfaces.BuyRefinement)
+}
+
+// Read reads the packet data from a Reader
+func (p *BuyRefinement) Read(r interfaces.Reader) error {
 	var err error
 	if err = p.Slot.Read(r); err != nil {
 		return err
@@ -36,8 +38,8 @@ func (p *BuyRefinement) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *BuyRefinement) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *BuyRefinement) Write(w interfaces.Writer) error {
 	if err := p.Slot.Write(w); err != nil {
 		return err
 	}
@@ -1,20 +1,16 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // CancelTrade represents a packet for canceling a trade
 type CancelTrade struct {
-	*packets.BasePacket
 }
 
 // NewCancelTrade creates a new CancelTrade packet
 func NewCancelTrade() *CancelTrade {
-	return &CancelTrade{
-		BasePacket: packets.NewPacket(interfaces.CancelTrade, byte(interfaces.CancelTrade)),
-	}
+	return &CancelTrade{}
 }
 
 // Type returns the packet type
@@ -22,12 +18,17 @@ func (p *CancelTrade) Type() interfaces.PacketType {
 	return interfaces.CancelTrade
 }
 
-// Read reads the packet data from a PacketReader
-func (p *CancelTrade) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *CancelTrade) ID() int32 {
+	return int32(interfaces.CancelTrade)
+}
+
+// Read reads the packet data from a Reader
+func (p *CancelTrade) Read(r interfaces.Reader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *CancelTrade) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *CancelTrade) Write(w interfaces.Writer) error {
 	return nil
 }
@@ -1,7 +1,6 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
@@ -17,15 +16,12 @@ const (
 
 // ChangeAllyShoot represents a packet for changing ally shoot settings
 type ChangeAllyShoot struct {
-	*packets.BasePacket
 	Setting int32
 }
 
 // NewChangeAllyShoot creates a new ChangeAllyShoot packet
 func NewChangeAllyShoot() *ChangeAllyShoot {
-	return &ChangeAllyShoot{
-		BasePacket: packets.NewPacket(interfaces.ChangeAllyShoot, byte(interfaces.ChangeAllyShoot)),
-	}
+	return &ChangeAllyShoot{}
 }
 
 // Type returns the packet type
@@ -33,14 +29,19 @@ func (p *ChangeAllyShoot) Type() interfaces.PacketType {
 	return interfaces.ChangeAllyShoot
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ChangeAllyShoot) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ChangeAllyShoot) ID() int32 {
+	return int32(interfaces.ChangeAllyShoot)
+}
+
+// Read reads the packet data from a Reader
+func (p *ChangeAllyShoot) Read(r interfaces.Reader) error {
 	var err error
 	p.Setting, err = r.ReadInt32()
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ChangeAllyShoot) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ChangeAllyShoot) Write(w interfaces.Writer) error {
 	return w.WriteInt32(p.Setting)
 }
@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ChangeGuildRank represents a packet for changing a guild member's rank
 type ChangeGuildRank struct {
-	*packets.BasePacket
 	Name      string
 	GuildRank byte
 }
 
 // NewChangeGuildRank creates a new ChangeGuildRank packet
 func NewChangeGuildRank() *ChangeGuildRank {
-	return &ChangeGuildRank{
-		BasePacket: packets.NewPacket(interfaces.ChangeGuildRank, byte(interfaces.ChangeGuildRank)),
-	}
+	return &ChangeGuildRank{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (p *ChangeGuildRank) Type() interfaces.PacketType {
 	return interfaces.ChangeGuildRank
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ChangeGuildRank) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ChangeGuildRank) ID() int32 {
+	return int32(interfaces.ChangeGuildRank)
+}
+
+// Read reads the packet data from a Reader
+func (p *ChangeGuildRank) Read(r interfaces.Reader) error {
 	var err error
 	p.Name, err = r.ReadString()
 	if err != nil {
@@ -35,8 +36,8 @@ func (p *ChangeGuildRank) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ChangeGuildRank) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ChangeGuildRank) Write(w interfaces.Writer) error {
 	if err := w.WriteString(p.Name); err != nil {
 		return err
 	}
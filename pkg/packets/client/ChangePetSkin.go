@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ChangePetSkin represents a packet for changing a pet's skin
 type ChangePetSkin struct {
-	*packets.BasePacket
 	PetID    int32
 	SkinType int32
 	Currency int32
@@ -15,9 +13,7 @@ type ChangePetSkin struct {
 
 // NewChangePetSkin creates a new ChangePetSkin packet
 func NewChangePetSkin() *ChangePetSkin {
-	return &ChangePetSkin{
-		BasePacket: packets.NewPacket(interfaces.ChangePetSkin, byte(interfaces.ChangePetSkin)),
-	}
+	return &ChangePetSkin{}
 }
 
 // Type returns the packet type
@@ -25,8 +21,13 @@ func (p *ChangePetSkin) Type() interfaces.PacketType {
 	return interfaces.ChangePetSkin
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ChangePetSkin) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ChangePetSkin) ID() int32 {
+	return int32(interfaces.ChangePetSkin)
+}
+
+// Read reads the packet data from a Reader
+func (p *ChangePetSkin) Read(r interfaces.Reader) error {
 	var err error
 	p.PetID, err = r.ReadInt32()
 	if err != nil {
@@ -40,8 +41,8 @@ func (p *ChangePetSkin) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ChangePetSkin) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ChangePetSkin) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.PetID); err != nil {
 		return err
 	}
@@ -1,21 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ChangeTrade represents a packet for modifying a trade
 type ChangeTrade struct {
-	*packets.BasePacket
 	Offers []bool
 }
 
 // NewChangeTrade creates a new ChangeTrade packet
 func NewChangeTrade() *ChangeTrade {
 	return &ChangeTrade{
-		BasePacket: packets.NewPacket(interfaces.ChangeTrade, byte(interfaces.ChangeTrade)),
-		Offers:     make([]bool, 0),
+		Offers: make([]bool, 0),
 	}
 }
 
@@ -24,8 +21,13 @@ func (p *ChangeTrade) Type() interfaces.PacketType {
 	return interfaces.ChangeTrade
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ChangeTrade) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ChangeTrade) ID() int32 {
+	return int32(interfaces.ChangeTrade)
+}
+
+// Read reads the packet data from a Reader
+func (p *ChangeTrade) Read(r interfaces.Reader) error {
 	length, err := r.ReadInt16()
 	if err != nil {
 		return err
@@ -42,8 +44,8 @@ func (p *ChangeTrade) Read(r *packets.PacketReader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ChangeTrade) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ChangeTrade) Write(w interfaces.Writer) error {
 	if err := w.WriteInt16(int16(len(p.Offers))); err != nil {
 		return err
 	}
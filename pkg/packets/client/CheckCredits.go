@@ -1,20 +1,16 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // CheckCredits represents a packet for checking credits
 type CheckCredits struct {
-	*packets.BasePacket
 }
 
 // NewCheckCredits creates a new CheckCredits packet
 func NewCheckCredits() *CheckCredits {
-	return &CheckCredits{
-		BasePacket: packets.NewPacket(interfaces.CheckCredits, byte(interfaces.CheckCredits)),
-	}
+	return &CheckCredits{}
 }
 
 // Type returns the packet type
@@ -22,12 +18,17 @@ func (p *CheckCredits) Type() interfaces.PacketType {
 	return interfaces.CheckCredits
 }
 
-// Read reads the packet data from a PacketReader
-func (p *CheckCredits) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *CheckCredits) ID() int32 {
+	return int32(interfaces.CheckCredits)
+}
+
+// Read reads the packet data from a Reader
+func (p *CheckCredits) Read(r interfaces.Reader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *CheckCredits) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *CheckCredits) Write(w interfaces.Writer) error {
 	return nil
 }
@@ -1,21 +1,17 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ChooseName represents a packet for choosing a character name
 type ChooseName struct {
-	*packets.BasePacket
 	Name string
 }
 
 // NewChooseName creates a new ChooseName packet
 func NewChooseName() *ChooseName {
-	return &ChooseName{
-		BasePacket: packets.NewPacket(interfaces.ChooseName, byte(interfaces.ChooseName)),
-	}
+	return &ChooseName{}
 }
 
 // Type returns the packet type
@@ -23,14 +19,19 @@ func (p *ChooseName) Type() interfaces.PacketType {
 	return interfaces.ChooseName
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ChooseName) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ChooseName) ID() int32 {
+	return int32(interfaces.ChooseName)
+}
+
+// Read reads the packet data from a Reader
+func (p *ChooseName) Read(r interfaces.Reader) error {
 	var err error
 	p.Name, err = r.ReadString()
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ChooseName) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ChooseName) Write(w interfaces.Writer) error {
 	return w.WriteString(p.Name)
 }
@@ -1,21 +1,17 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ClaimBPMilestone represents a packet for claiming a battle pass milestone
 type ClaimBPMilestone struct {
-	*packets.BasePacket
 	RewardID int8 // Using int8 instead of sbyte as Go doesn't have sbyte
 }
 
 // NewClaimBPMilestone creates a new ClaimBPMilestone packet
 func NewClaimBPMilestone() *ClaimBPMilestone {
-	return &ClaimBPMilestone{
-		BasePacket: packets.NewPacket(interfaces.ClaimBPMilestone, byte(interfaces.ClaimBPMilestone)),
-	}
+	return &ClaimBPMilestone{}
 }
 
 // Type returns the packet type
@@ -23,8 +19,13 @@ func (p *ClaimBPMilestone) Type() interfaces.PacketType {
 	return interfaces.ClaimBPMilestone
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ClaimBPMilestone) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ClaimBPMilestone) ID() int32 {
+	return int32(interfaces.ClaimBPMilestone)
+}
+
+// Read reads the packet data from a Reader
+func (p *ClaimBPMilestone) Read(r interfaces.Reader) error {
 	rewardID, err := r.ReadByte()
 	if err != nil {
 		return err
@@ -33,7 +34,7 @@ func (p *ClaimBPMilestone) Read(r *packets.PacketReader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ClaimBPMilestone) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ClaimBPMilestone) Write(w interfaces.Writer) error {
 	return w.WriteByte(byte(p.RewardID))
 }
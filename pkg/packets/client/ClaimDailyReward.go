@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ClaimDailyReward represents a packet for claiming daily rewards
 type ClaimDailyReward struct {
-	*packets.BasePacket
 	ClaimKey  string
 	ClaimType string
 }
 
 // NewClaimDailyReward creates a new ClaimDailyReward packet
 func NewClaimDailyReward() *ClaimDailyReward {
-	return &ClaimDailyReward{
-		BasePacket: packets.NewPacket(interfaces.ClaimDailyReward, byte(interfaces.ClaimDailyReward)),
-	}
+	return &ClaimDailyReward{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (p *ClaimDailyReward) Type() interfaces.PacketType {
 	return interfaces.ClaimDailyReward
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ClaimDailyReward) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ClaimDailyReward) ID() int32 {
+	return int32(interfaces.ClaimDailyReward)
+}
+
+// Read reads the packet data from a Reader
+func (p *ClaimDailyReward) Read(r interfaces.Reader) error {
 	var err error
 	p.ClaimKey, err = r.ReadString()
 	if err != nil {
@@ -35,8 +36,8 @@ func (p *ClaimDailyReward) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ClaimDailyReward) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ClaimDailyReward) Write(w interfaces.Writer) error {
 	if err := w.WriteString(p.ClaimKey); err != nil {
 		return err
 	}
@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ClaimMission represents a packet for claiming a mission
 type ClaimMission struct {
-	*packets.BasePacket
 	MissionID   int32  // CIGJNLDGNAF in original
 	MissionType byte   // BGJKHDCELPO in original
 	Category    byte   // MAOGDBIHOOB in original
@@ -16,9 +14,7 @@ type ClaimMission struct {
 
 // NewClaimMission creates a new ClaimMission packet
 func NewClaimMission() *ClaimMission {
-	return &ClaimMission{
-		BasePacket: packets.NewPacket(interfaces.ClaimMission, byte(interfaces.ClaimMission)),
-	}
+	return &ClaimMission{}
 }
 
 // Type returns the packet type
@@ -26,8 +22,13 @@ func (p *ClaimMission) Type() interfaces.PacketType {
 	return interfaces.ClaimMission
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ClaimMission) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ClaimMission) ID() int32 {
+	return int32(interfaces.ClaimMission)
+}
+
+// Read reads the packet data from a Reader
+func (p *ClaimMission) Read(r interfaces.Reader) error {
 	var err error
 	p.MissionID, err = r.ReadInt32()
 	if err != nil {
@@ -45,8 +46,8 @@ func (p *ClaimMission) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ClaimMission) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ClaimMission) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.MissionID); err != nil {
 		return err
 	}
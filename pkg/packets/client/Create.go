@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Create represents a packet for creating a new character
 type Create struct {
-	*packets.BasePacket
 	ClassType    uint16
 	SkinType     uint16
 	IsChallenger bool
@@ -16,9 +14,7 @@ type Create struct {
 
 // NewCreate creates a new Create packet
 func NewCreate() *Create {
-	return &Create{
-		BasePacket: packets.NewPacket(interfaces.Create, byte(interfaces.Create)),
-	}
+	return &Create{}
 }
 
 // Type returns the packet type
@@ -26,8 +22,13 @@ func (p *Create) Type() interfaces.PacketType {
 	return interfaces.Create
 }
 
-// Read reads the packet data from a PacketReader
-func (p *Create) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *Create) ID() int32 {
+	return int32(interfaces.Create)
+}
+
+// Read reads the packet data from a Reader
+func (p *Create) Read(r interfaces.Reader) error {
 	var err error
 	p.ClassType, err = r.ReadUInt16()
 	if err != nil {
@@ -45,8 +46,8 @@ func (p *Create) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *Create) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *Create) Write(w interfaces.Writer) error {
 	if err := w.WriteUInt16(p.ClassType); err != nil {
 		return err
 	}
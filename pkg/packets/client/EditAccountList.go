@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // EditAccountList represents a packet for editing an account list
 type EditAccountList struct {
-	*packets.BasePacket
 	AccountListID int32
 	Add           bool
 	ObjectID      int32
@@ -15,9 +13,7 @@ type EditAccountList struct {
 
 // NewEditAccountList creates a new EditAccountList packet
 func NewEditAccountList() *EditAccountList {
-	return &EditAccountList{
-		BasePacket: packets.NewPacket(interfaces.EditAccountList, byte(interfaces.EditAccountList)),
-	}
+	return &EditAccountList{}
 }
 
 // Type returns the packet type
@@ -25,8 +21,13 @@ func (p *EditAccountList) Type() interfaces.PacketType {
 	return interfaces.EditAccountList
 }
 
-// Read reads the packet data from a PacketReader
-func (p *EditAccountList) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *EditAccountList) ID() int32 {
+	return int32(interfaces.EditAccountList)
+}
+
+// Read reads the packet data from a Reader
+func (p *EditAccountList) Read(r interfaces.Reader) error {
 	var err error
 	p.AccountListID, err = r.ReadInt32()
 	if err != nil {
@@ -40,8 +41,8 @@ func (p *EditAccountList) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *EditAccountList) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *EditAccountList) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.AccountListID); err != nil {
 		return err
 	}
@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Emote represents a packet for performing an emote
 type Emote struct {
-	*packets.BasePacket
 	EmoteID      int32
 	Time         int32
 	UnknownBool0 bool
@@ -15,9 +13,7 @@ type Emote struct {
 
 // NewEmote creates a new Emote packet
 func NewEmote() *Emote {
-	return &Emote{
-		BasePacket: packets.NewPacket(interfaces.Emote, byte(interfaces.Emote)),
-	}
+	return &Emote{}
 }
 
 // Type returns the packet type
@@ -25,8 +21,13 @@ func (p *Emote) Type() interfaces.PacketType {
 	return interfaces.Emote
 }
 
-// Read reads the packet data from a PacketReader
-func (p *Emote) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *Emote) ID() int32 {
+	return int32(interfaces.Emote)
+}
+
+// Read reads the packet data from a Reader
+func (p *Emote) Read(r interfaces.Reader) error {
 	var err error
 	p.EmoteID, err = r.ReadInt32()
 	if err != nil {
@@ -40,8 +41,8 @@ func (p *Emote) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *Emote) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *Emote) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.EmoteID); err != nil {
 		return err
 	}
@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // EnemyHit represents a packet for enemy hit events
 type EnemyHit struct {
-	*packets.BasePacket
 	Time     int32
 	BulletID uint16
 	SourceID int32
@@ -18,9 +16,7 @@ type EnemyHit struct {
 
 // NewEnemyHit creates a new EnemyHit packet
 func NewEnemyHit() *EnemyHit {
-	return &EnemyHit{
-		BasePacket: packets.NewPacket(interfaces.EnemyHit, byte(interfaces.EnemyHit)),
-	}
+	return &EnemyHit{}
 }
 
 // Type returns the packet type
@@ -28,8 +24,13 @@ func (p *EnemyHit) Type() interfaces.PacketType {
 	return interfaces.EnemyHit
 }
 
-// Read reads the packet data from a PacketReader
-func (p *EnemyHit) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *EnemyHit) ID() int32 {
+	return int32(interfaces.EnemyHit)
+}
+
+// Read reads the packet data from a Reader
+func (p *EnemyHit) Read(r interfaces.Reader) error {
 	var err error
 	p.Time, err = r.ReadInt32()
 	if err != nil {
@@ -55,8 +56,8 @@ func (p *EnemyHit) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *EnemyHit) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *EnemyHit) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.Time); err != nil {
 		return err
 	}
@@ -1,20 +1,16 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Escape represents a packet for escape actions
 type Escape struct {
-	*packets.BasePacket
 }
 
 // NewEscape creates a new Escape packet
 func NewEscape() *Escape {
-	return &Escape{
-		BasePacket: packets.NewPacket(interfaces.Escape, byte(interfaces.Escape)),
-	}
+	return &Escape{}
 }
 
 // Type returns the packet type
@@ -22,12 +18,17 @@ func (p *Escape) Type() interfaces.PacketType {
 	return interfaces.Escape
 }
 
-// Read reads the packet data from a PacketReader
-func (p *Escape) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *Escape) ID() int32 {
+	return int32(interfaces.Escape)
+}
+
+// Read reads the packet data from a Reader
+func (p *Escape) Read(r interfaces.Reader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *Escape) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *Escape) Write(w interfaces.Writer) error {
 	return nil
 }
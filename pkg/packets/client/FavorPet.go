@@ -7,21 +7,17 @@
 package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // FavorPet represents a packet for favoriting a pet
 type FavorPet struct {
-	*packets.BasePacket
 	PetID int32
 }
 
 // NewFavorPet creates a new FavorPet packet
 func NewFavorPet() *FavorPet {
-	return &FavorPet{
-		BasePacket: packets.NewPacket(interfaces.FavorPet, byte(interfaces.FavorPet)),
-	}
+	return &FavorPet{}
 }
 
 // Type returns the packet type
@@ -29,14 +25,19 @@ func (p *FavorPet) Type() interfaces.PacketType {
 	return interfaces.FavorPet
 }
 
-// Read reads the packet data from a PacketReader
-func (p *FavorPet) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *FavorPet) ID() int32 {
+	return int32(interfaces.FavorPet)
+}
+
+// Read reads the packet data from a Reader
+func (p *FavorPet) Read(r interfaces.Reader) error {
 	var err error
 	p.PetID, err = r.ReadInt32()
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *FavorPet) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *FavorPet) Write(w interfaces.Writer) error {
 	return w.WriteInt32(p.PetID)
 }
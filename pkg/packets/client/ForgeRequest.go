@@ -1,14 +1,12 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/dataobjects"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // ForgeRequest represents a packet for forge requests
 type ForgeRequest struct {
-	*packets.BasePacket
 	ForgeTargetItem int32
 	DismantleSlots  []*dataobjects.SlotObject
 }
@@ -16,7 +14,6 @@ type ForgeRequest struct {
 // NewForgeRequest creates a new ForgeRequest packet
 func NewForgeRequest() *ForgeRequest {
 	return &ForgeRequest{
-		BasePacket:     packets.NewPacket(interfaces.ForgeRequest, byte(interfaces.ForgeRequest)),
 		DismantleSlots: make([]*dataobjects.SlotObject, 0),
 	}
 }
@@ -26,8 +23,13 @@ func (p *ForgeRequest) Type() interfaces.PacketType {
 	return interfaces.ForgeRequest
 }
 
-// Read reads the packet data from a PacketReader
-func (p *ForgeRequest) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *ForgeRequest) ID() int32 {
+	return int32(interfaces.ForgeRequest)
+}
+
+// Read reads the packet data from a Reader
+func (p *ForgeRequest) Read(r interfaces.Reader) error {
 	var err error
 	p.ForgeTargetItem, err = r.ReadInt32()
 	if err != nil {
@@ -50,8 +52,8 @@ func (p *ForgeRequest) Read(r *packets.PacketReader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *ForgeRequest) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *ForgeRequest) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.ForgeTargetItem); err != nil {
 		return err
 	}
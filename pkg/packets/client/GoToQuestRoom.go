@@ -1,20 +1,16 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // GoToQuestRoom represents a packet for going to quest room
 type GoToQuestRoom struct {
-	*packets.BasePacket
 }
 
 // NewGoToQuestRoom creates a new GoToQuestRoom packet
 func NewGoToQuestRoom() *GoToQuestRoom {
-	return &GoToQuestRoom{
-		BasePacket: packets.NewPacket(interfaces.GoToQuestRoom, byte(interfaces.GoToQuestRoom)),
-	}
+	return &GoToQuestRoom{}
 }
 
 // Type returns the packet type
@@ -22,12 +18,17 @@ func (p *GoToQuestRoom) Type() interfaces.PacketType {
 	return interfaces.GoToQuestRoom
 }
 
-// Read reads the packet data from a PacketReader
-func (p *GoToQuestRoom) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *GoToQuestRoom) ID() int32 {
+	return int32(interfaces.GoToQuestRoom)
+}
+
+// Read reads the packet data from a Reader
+func (p *GoToQuestRoom) Read(r interfaces.Reader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *GoToQuestRoom) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *GoToQuestRoom) Write(w interfaces.Writer) error {
 	return nil
 }
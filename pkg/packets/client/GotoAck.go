@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // GotoAck represents a packet for acknowledging goto commands
 type GotoAck struct {
-	*packets.BasePacket
 	Time    int32
 	Unknown bool // UnknownFCOJFJPBJFA in original
 }
 
 // NewGotoAck creates a new GotoAck packet
 func NewGotoAck() *GotoAck {
-	return &GotoAck{
-		BasePacket: packets.NewPacket(interfaces.GotoAck, byte(interfaces.GotoAck)),
-	}
+	return &GotoAck{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (p *GotoAck) Type() interfaces.PacketType {
 	return interfaces.GotoAck
 }
 
-// Read reads the packet data from a PacketReader
-func (p *GotoAck) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *GotoAck) ID() int32 {
+	return int32(interfaces.GotoAck)
+}
+
+// Read reads the packet data from a Reader
+func (p *GotoAck) Read(r interfaces.Reader) error {
 	var err error
 	p.Time, err = r.ReadInt32()
 	if err != nil {
@@ -35,8 +36,8 @@ func (p *GotoAck) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *GotoAck) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *GotoAck) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.Time); err != nil {
 		return err
 	}
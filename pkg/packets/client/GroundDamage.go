@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // GroundDamage represents a packet for ground damage events
 type GroundDamage struct {
-	*packets.BasePacket
 	Time     int32
 	Position *Location
 }
 
 // NewGroundDamage creates a new GroundDamage packet
 func NewGroundDamage() *GroundDamage {
-	return &GroundDamage{
-		BasePacket: packets.NewPacket(interfaces.GroundDamage, byte(interfaces.GroundDamage)),
-	}
+	return &GroundDamage{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (p *GroundDamage) Type() interfaces.PacketType {
 	return interfaces.GroundDamage
 }
 
-// Read reads the packet data from a PacketReader
-func (p *GroundDamage) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *GroundDamage) ID() int32 {
+	return int32(interfaces.GroundDamage)
+}
+
+// Read reads the packet data from a Reader
+func (p *GroundDamage) Read(r interfaces.Reader) error {
 	var err error
 	p.Time, err = r.ReadInt32()
 	if err != nil {
@@ -35,8 +36,8 @@ func (p *GroundDamage) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *GroundDamage) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *GroundDamage) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.Time); err != nil {
 		return err
 	}
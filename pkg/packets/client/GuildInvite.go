@@ -1,21 +1,17 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // GuildInvite represents a packet for guild invitations
 type GuildInvite struct {
-	*packets.BasePacket
 	Name string
 }
 
 // NewGuildInvite creates a new GuildInvite packet
 func NewGuildInvite() *GuildInvite {
-	return &GuildInvite{
-		BasePacket: packets.NewPacket(interfaces.GuildInvite, byte(interfaces.GuildInvite)),
-	}
+	return &GuildInvite{}
 }
 
 // Type returns the packet type
@@ -23,14 +19,19 @@ func (p *GuildInvite) Type() interfaces.PacketType {
 	return interfaces.GuildInvite
 }
 
-// Read reads the packet data from a PacketReader
-func (p *GuildInvite) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *GuildInvite) ID() int32 {
+	return int32(interfaces.GuildInvite)
+}
+
+// Read reads the packet data from a Reader
+func (p *GuildInvite) Read(r interfaces.Reader) error {
 	var err error
 	p.Name, err = r.ReadString()
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *GuildInvite) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *GuildInvite) Write(w interfaces.Writer) error {
 	return w.WriteString(p.Name)
 }
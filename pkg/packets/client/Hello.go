@@ -3,13 +3,11 @@
 import (
 	"encoding/hex"
 	"fmt"
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Hello represents a packet for client hello handshake
 type Hello struct {
-	*packets.BasePacket
 	GameID               int32
 	BuildVersion         string
 	AccessToken          string
@@ -24,9 +22,7 @@ type Hello struct {
 
 // NewHello creates a new Hello packet
 func NewHello() *Hello {
-	return &Hello{
-		BasePacket: packets.NewPacket(interfaces.Hello, byte(interfaces.Hello)),
-	}
+	return &Hello{}
 }
 
 // Type returns the packet type
@@ -1,14 +1,12 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/dataobjects"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // InventoryDrop represents a packet for dropping inventory items
 type InventoryDrop struct {
-	*packets.BasePacket
 	Slot    *dataobjects.SlotObject
 	Unknown bool
 }
@@ -16,8 +14,7 @@ type InventoryDrop struct {
 // NewInventoryDrop creates a new InventoryDrop packet
 func NewInventoryDrop() *InventoryDrop {
 	return &InventoryDrop{
-		BasePacket: packets.NewPacket(interfaces.InventoryDrop, byte(interfaces.InventoryDrop)),
-		Slot:       dataobjects.NewSlotObject(),
+		Slot: dataobjects.NewSlotObject(),
 	}
 }
 
@@ -26,8 +23,13 @@ func (p *InventoryDrop) Type() interfaces.PacketType {
 	return interfaces.InventoryDrop
 }
 
-// Read reads the packet data from a PacketReader
-func (p *InventoryDrop) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *InventoryDrop) ID() int32 {
+	return int32(interfaces.InventoryDrop)
+}
+
+// Read reads the packet data from a Reader
+func (p *InventoryDrop) Read(r interfaces.Reader) error {
 	var err error
 	if err = p.Slot.Read(r); err != nil {
 		return err
@@ -36,8 +38,8 @@ func (p *InventoryDrop) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *InventoryDrop) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *InventoryDrop) Write(w interfaces.Writer) error {
 	if err := p.Slot.Write(w); err != nil {
 		return err
 	}
@@ -1,14 +1,12 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/dataobjects"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // InventorySwap represents a packet for swapping inventory items
 type InventorySwap struct {
-	*packets.BasePacket
 	Time        int32
 	Position    *Location
 	SlotObject1 *dataobjects.SlotObject
@@ -18,7 +16,6 @@ type InventorySwap struct {
 // NewInventorySwap creates a new InventorySwap packet
 func NewInventorySwap() *InventorySwap {
 	return &InventorySwap{
-		BasePacket:  packets.NewPacket(interfaces.InventorySwap, byte(interfaces.InventorySwap)),
 		SlotObject1: dataobjects.NewSlotObject(),
 		SlotObject2: dataobjects.NewSlotObject(),
 	}
@@ -29,8 +26,13 @@ func (p *InventorySwap) Type() interfaces.PacketType {
 	return interfaces.InventorySwap
 }
 
-// Read reads the packet data from a PacketReader
-func (p *InventorySwap) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *InventorySwap) ID() int32 {
+	return int32(interfaces.InventorySwap)
+}
+
+// Read reads the packet data from a Reader
+func (p *InventorySwap) Read(r interfaces.Reader) error {
 	var err error
 	p.Time, err = r.ReadInt32()
 	if err != nil {
@@ -46,8 +48,8 @@ func (p *InventorySwap) Read(r *packets.PacketReader) error {
 	return p.SlotObject2.Read(r)
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *InventorySwap) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *InventorySwap) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.Time); err != nil {
 		return err
 	}
@@ -1,21 +1,17 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // JoinGuild represents a packet for joining a guild
 type JoinGuild struct {
-	*packets.BasePacket
 	GuildName string
 }
 
 // NewJoinGuild creates a new JoinGuild packet
 func NewJoinGuild() *JoinGuild {
-	return &JoinGuild{
-		BasePacket: packets.NewPacket(interfaces.JoinGuild, byte(interfaces.JoinGuild)),
-	}
+	return &JoinGuild{}
 }
 
 // Type returns the packet type
@@ -23,14 +19,19 @@ func (p *JoinGuild) Type() interfaces.PacketType {
 	return interfaces.JoinGuild
 }
 
-// Read reads the packet data from a PacketReader
-func (p *JoinGuild) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *JoinGuild) ID() int32 {
+	return int32(interfaces.JoinGuild)
+}
+
+// Read reads the packet data from a Reader
+func (p *JoinGuild) Read(r interfaces.Reader) error {
 	var err error
 	p.GuildName, err = r.ReadString()
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *JoinGuild) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *JoinGuild) Write(w interfaces.Writer) error {
 	return w.WriteString(p.GuildName)
 }
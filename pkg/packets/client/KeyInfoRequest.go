@@ -1,21 +1,17 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // KeyInfoRequest represents a packet for requesting key information
 type KeyInfoRequest struct {
-	*packets.BasePacket
 	ItemID int32
 }
 
 // NewKeyInfoRequest creates a new KeyInfoRequest packet
 func NewKeyInfoRequest() *KeyInfoRequest {
-	return &KeyInfoRequest{
-		BasePacket: packets.NewPacket(interfaces.KeyInfoRequest, byte(interfaces.KeyInfoRequest)),
-	}
+	return &KeyInfoRequest{}
 }
 
 // Type returns the packet type
@@ -23,14 +19,19 @@ func (p *KeyInfoRequest) Type() interfaces.PacketType {
 	return interfaces.KeyInfoRequest
 }
 
-// Read reads the packet data from a PacketReader
-func (p *KeyInfoRequest) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *KeyInfoRequest) ID() int32 {
+	return int32(interfaces.KeyInfoRequest)
+}
+
+// Read reads the packet data from a Reader
+func (p *KeyInfoRequest) Read(r interfaces.Reader) error {
 	var err error
 	p.ItemID, err = r.ReadInt32()
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *KeyInfoRequest) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *KeyInfoRequest) Write(w interfaces.Writer) error {
 	return w.WriteInt32(p.ItemID)
 }
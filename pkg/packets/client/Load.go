@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Load represents a packet for loading character data
 type Load struct {
-	*packets.BasePacket
 	CharacterID  int32
 	FirstSession bool
 }
 
 // NewLoad creates a new Load packet
 func NewLoad() *Load {
-	return &Load{
-		BasePacket: packets.NewPacket(interfaces.Load, byte(interfaces.Load)),
-	}
+	return &Load{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (p *Load) Type() interfaces.PacketType {
 	return interfaces.Load
 }
 
-// Read reads the packet data from a PacketReader
-func (p *Load) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *Load) ID() int32 {
+	return int32(interfaces.Load)
+}
+
+// Read reads the packet data from a Reader
+func (p *Load) Read(r interfaces.Reader) error {
 	var err error
 	p.CharacterID, err = r.ReadInt32()
 	if err != nil {
@@ -35,8 +36,8 @@ func (p *Load) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *Load) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *Load) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.CharacterID); err != nil {
 		return err
 	}
@@ -1,14 +1,12 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/dataobjects"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Move represents a packet for movement
 type Move struct {
-	*packets.BasePacket
 	TickID  int32
 	Time    int32
 	Records []*dataobjects.LocationRecord
@@ -17,8 +15,7 @@ type Move struct {
 // NewMove creates a new Move packet
 func NewMove() *Move {
 	return &Move{
-		BasePacket: packets.NewPacket(interfaces.Move, byte(interfaces.Move)),
-		Records:    make([]*dataobjects.LocationRecord, 0),
+		Records: make([]*dataobjects.LocationRecord, 0),
 	}
 }
 
@@ -27,8 +24,13 @@ func (p *Move) Type() interfaces.PacketType {
 	return interfaces.Move
 }
 
-// Read reads the packet data from a PacketReader
-func (p *Move) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *Move) ID() int32 {
+	return int32(interfaces.Move)
+}
+
+// Read reads the packet data from a Reader
+func (p *Move) Read(r interfaces.Reader) error {
 	var err error
 	p.TickID, err = r.ReadInt32()
 	if err != nil {
@@ -54,8 +56,8 @@ func (p *Move) Read(r *packets.PacketReader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *Move) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *Move) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.TickID); err != nil {
 		return err
 	}
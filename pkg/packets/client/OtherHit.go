@@ -7,13 +7,11 @@
 package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // OtherHit represents a packet for other entity hits
 type OtherHit struct {
-	*packets.BasePacket
 	Time     int32
 	BulletID uint16
 	ObjectID int32
@@ -22,9 +20,7 @@ type OtherHit struct {
 
 // NewOtherHit creates a new OtherHit packet
 func NewOtherHit() *OtherHit {
-	return &OtherHit{
-		BasePacket: packets.NewPacket(interfaces.OtherHit, byte(interfaces.OtherHit)),
-	}
+	return &OtherHit{}
 }
 
 // Type returns the packet type
@@ -32,8 +28,13 @@ func (p *OtherHit) Type() interfaces.PacketType {
 	return interfaces.OtherHit
 }
 
-// Read reads the packet data from a PacketReader
-func (p *OtherHit) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *OtherHit) ID() int32 {
+	return int32(interfaces.OtherHit)
+}
+
+// Read reads the packet data from a Reader
+func (p *OtherHit) Read(r interfaces.Reader) error {
 	var err error
 	p.Time, err = r.ReadInt32()
 	if err != nil {
@@ -51,8 +52,8 @@ func (p *OtherHit) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *OtherHit) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *OtherHit) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.Time); err != nil {
 		return err
 	}
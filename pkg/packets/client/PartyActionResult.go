@@ -1,7 +1,6 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
@@ -29,15 +28,13 @@ const (
 
 // PartyActionResult represents a packet for party action results
 type PartyActionResult struct {
-	*packets.BasePacket
 	PlayerID uint16
 	ActionID PartyActionID
 }
 
 // NewPartyActionResult creates a new PartyActionResult packet
 func NewPartyActionResult() *PartyActionResult {
-	return &PartyActionResult{
-		BasePacket: packets.NewPacket(interfaces.PartyActionResult, 254), // 254 is the unsigned byte equivalent of -2
+	return &PartyActionResult{ // 254 is the unsigned byte equivalent of -2
 	}
 }
 
@@ -46,8 +43,13 @@ func (p *PartyActionResult) Type() interfaces.PacketType {
 	return interfaces.PartyActionResult
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PartyActionResult) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PartyActionResult) ID() int32 {
+	return int32(interfaces.PartyActionResult)
+}
+
+// Read reads the packet data from a Reader
+func (p *PartyActionResult) Read(r interfaces.Reader) error {
 	var err error
 	p.PlayerID, err = r.ReadUInt16()
 	if err != nil {
@@ -61,8 +63,8 @@ func (p *PartyActionResult) Read(r *packets.PacketReader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PartyActionResult) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PartyActionResult) Write(w interfaces.Writer) error {
 	if err := w.WriteUInt16(p.PlayerID); err != nil {
 		return err
 	}
@@ -1,7 +1,6 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
@@ -10,7 +9,6 @@ type PartyPrivacy byte
 
 // PartyCreate represents a packet for creating a party
 type PartyCreate struct {
-	*packets.BasePacket
 	Description   string
 	PowerLevelMin uint16
 	PartySizeMax  byte
@@ -22,18 +20,22 @@ type PartyCreate struct {
 
 // NewPartyCreate creates a new PartyCreate packet
 func NewPartyCreate() *PartyCreate {
-	return &PartyCreate{
-		BasePacket: packets.NewPacket(interfaces.PartyAction, 251), // 251 is the unsigned byte equivalent of -5
+	return &PartyCreate{ // 251 is the unsigned byte equivalent of -5
 	}
 }
 
 // Type returns the packet type
 func (p *PartyCreate) Type() interfaces.PacketType {
-	return interfaces.PartyAction
+	return interfaces.PartyCreate
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PartyCreate) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PartyCreate) ID() int32 {
+	return int32(interfaces.PartyCreate)
+}
+
+// Read reads the packet data from a Reader
+func (p *PartyCreate) Read(r interfaces.Reader) error {
 	var err error
 	p.Description, err = r.ReadString()
 	if err != nil {
@@ -64,8 +66,8 @@ func (p *PartyCreate) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PartyCreate) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PartyCreate) Write(w interfaces.Writer) error {
 	if err := w.WriteString(p.Description); err != nil {
 		return err
 	}
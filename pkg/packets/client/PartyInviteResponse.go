@@ -1,7 +1,6 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
@@ -17,15 +16,13 @@ const (
 
 // PartyInviteResponse represents a packet for responding to party invites
 type PartyInviteResponse struct {
-	*packets.BasePacket
 	PartyID      uint32
 	AcceptInvite AcceptDecline
 }
 
 // NewPartyInviteResponse creates a new PartyInviteResponse packet
 func NewPartyInviteResponse() *PartyInviteResponse {
-	return &PartyInviteResponse{
-		BasePacket: packets.NewPacket(interfaces.PartyInviteResponse, 253), // 253 is the unsigned byte equivalent of -3
+	return &PartyInviteResponse{ // 253 is the unsigned byte equivalent of -3
 	}
 }
 
@@ -34,8 +31,13 @@ func (p *PartyInviteResponse) Type() interfaces.PacketType {
 	return interfaces.PartyInviteResponse
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PartyInviteResponse) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PartyInviteResponse) ID() int32 {
+	return int32(interfaces.PartyInviteResponse)
+}
+
+// Read reads the packet data from a Reader
+func (p *PartyInviteResponse) Read(r interfaces.Reader) error {
 	var err error
 	p.PartyID, err = r.ReadUInt32()
 	if err != nil {
@@ -49,8 +51,8 @@ func (p *PartyInviteResponse) Read(r *packets.PacketReader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PartyInviteResponse) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PartyInviteResponse) Write(w interfaces.Writer) error {
 	if err := w.WriteUInt32(p.PartyID); err != nil {
 		return err
 	}
@@ -1,21 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // PartyJoinRequest represents a packet for party join requests
 type PartyJoinRequest struct {
-	*packets.BasePacket
 	PlayerID uint32
 	Unknown1 byte
 }
 
 // NewPartyJoinRequest creates a new PartyJoinRequest packet
 func NewPartyJoinRequest() *PartyJoinRequest {
-	return &PartyJoinRequest{
-		BasePacket: packets.NewPacket(interfaces.PartyJoinRequest, 252), // 252 is the unsigned byte equivalent of -4
+	return &PartyJoinRequest{ // 252 is the unsigned byte equivalent of -4
 	}
 }
 
@@ -24,8 +21,13 @@ func (p *PartyJoinRequest) Type() interfaces.PacketType {
 	return interfaces.PartyJoinRequest
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PartyJoinRequest) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PartyJoinRequest) ID() int32 {
+	return int32(interfaces.PartyJoinRequest)
+}
+
+// Read reads the packet data from a Reader
+func (p *PartyJoinRequest) Read(r interfaces.Reader) error {
 	var err error
 	p.PlayerID, err = r.ReadUInt32()
 	if err != nil {
@@ -35,8 +37,8 @@ func (p *PartyJoinRequest) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PartyJoinRequest) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PartyJoinRequest) Write(w interfaces.Writer) error {
 	if err := w.WriteUInt32(p.PlayerID); err != nil {
 		return err
 	}
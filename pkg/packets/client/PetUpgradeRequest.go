@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // PetUpgradeRequest represents a packet for requesting a pet upgrade
 type PetUpgradeRequest struct {
-	*packets.BasePacket
 	PetTransType     byte
 	PetID1           int32
 	PetID2           int32
@@ -18,9 +16,7 @@ type PetUpgradeRequest struct {
 
 // NewPetUpgradeRequest creates a new PetUpgradeRequest packet
 func NewPetUpgradeRequest() *PetUpgradeRequest {
-	return &PetUpgradeRequest{
-		BasePacket: packets.NewPacket(interfaces.PetUpgradeRequest, byte(interfaces.PetUpgradeRequest)),
-	}
+	return &PetUpgradeRequest{}
 }
 
 // Type returns the packet type
@@ -28,8 +24,13 @@ func (p *PetUpgradeRequest) Type() interfaces.PacketType {
 	return interfaces.PetUpgradeRequest
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PetUpgradeRequest) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PetUpgradeRequest) ID() int32 {
+	return int32(interfaces.PetUpgradeRequest)
+}
+
+// Read reads the packet data from a Reader
+func (p *PetUpgradeRequest) Read(r interfaces.Reader) error {
 	var err error
 	p.PetTransType, err = r.ReadByte()
 	if err != nil {
@@ -55,8 +56,8 @@ func (p *PetUpgradeRequest) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PetUpgradeRequest) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PetUpgradeRequest) Write(w interfaces.Writer) error {
 	if err := w.WriteByte(p.PetTransType); err != nil {
 		return err
 	}
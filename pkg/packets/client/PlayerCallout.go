@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // PlayerCallout represents a packet for player callouts
 type PlayerCallout struct {
-	*packets.BasePacket
 	X float32
 	Y float32
 }
 
 // NewPlayerCallout creates a new PlayerCallout packet
 func NewPlayerCallout() *PlayerCallout {
-	return &PlayerCallout{
-		BasePacket: packets.NewPacket(interfaces.PlayerCallout, byte(interfaces.PlayerCallout)),
-	}
+	return &PlayerCallout{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (p *PlayerCallout) Type() interfaces.PacketType {
 	return interfaces.PlayerCallout
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PlayerCallout) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PlayerCallout) ID() int32 {
+	return int32(interfaces.PlayerCallout)
+}
+
+// Read reads the packet data from a Reader
+func (p *PlayerCallout) Read(r interfaces.Reader) error {
 	var err error
 	p.X, err = r.ReadFloat32()
 	if err != nil {
@@ -35,8 +36,8 @@ func (p *PlayerCallout) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PlayerCallout) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PlayerCallout) Write(w interfaces.Writer) error {
 	if err := w.WriteFloat32(p.X); err != nil {
 		return err
 	}
@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // PlayerHit represents a packet for player hits
 type PlayerHit struct {
-	*packets.BasePacket
 	BulletID int32
 	ObjectID int32
 }
 
 // NewPlayerHit creates a new PlayerHit packet
 func NewPlayerHit() *PlayerHit {
-	return &PlayerHit{
-		BasePacket: packets.NewPacket(interfaces.PlayerHit, byte(interfaces.PlayerHit)),
-	}
+	return &PlayerHit{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (p *PlayerHit) Type() interfaces.PacketType {
 	return interfaces.PlayerHit
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PlayerHit) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PlayerHit) ID() int32 {
+	return int32(interfaces.PlayerHit)
+}
+
+// Read reads the packet data from a Reader
+func (p *PlayerHit) Read(r interfaces.Reader) error {
 	var err error
 	p.BulletID, err = r.ReadInt32()
 	if err != nil {
@@ -35,8 +36,8 @@ func (p *PlayerHit) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PlayerHit) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PlayerHit) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.BulletID); err != nil {
 		return err
 	}
@@ -1,13 +1,11 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // PlayerShoot represents a packet for player shooting
 type PlayerShoot struct {
-	*packets.BasePacket
 	Time          int32
 	BulletID      byte
 	ContainerType int32
@@ -23,9 +21,7 @@ type PlayerShoot struct {
 
 // NewPlayerShoot creates a new PlayerShoot packet
 func NewPlayerShoot() *PlayerShoot {
-	return &PlayerShoot{
-		BasePacket: packets.NewPacket(interfaces.PlayerShoot, byte(interfaces.PlayerShoot)),
-	}
+	return &PlayerShoot{}
 }
 
 // Type returns the packet type
@@ -33,8 +29,13 @@ func (p *PlayerShoot) Type() interfaces.PacketType {
 	return interfaces.PlayerShoot
 }
 
-// Read reads the packet data from a PacketReader
-func (p *PlayerShoot) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (p *PlayerShoot) ID() int32 {
+	return int32(interfaces.PlayerShoot)
+}
+
+// Read reads the packet data from a Reader
+func (p *PlayerShoot) Read(r interfaces.Reader) error {
 	var err error
 	p.Time, err = r.ReadInt32()
 	if err != nil {
@@ -72,8 +73,8 @@ func (p *PlayerShoot) Read(r *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (p *PlayerShoot) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (p *PlayerShoot) Write(w interfaces.Writer) error {
 	if err := w.WriteInt32(p.Time); err != nil {
 		return err
 	}
@@ -15,6 +15,11 @@ func (p *Pong) Type() interfaces.PacketType {
 	return interfaces.Pong
 }
 
+// ID returns the packet ID
+func (p *Pong) ID() int32 {
+	return int32(interfaces.Pong)
+}
+
 // Read reads the packet data from the given reader
 func (p *Pong) Read(r interfaces.Reader) error {
 	var err error
@@ -1,20 +1,16 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // QuestFetchAsk represents a packet for requesting quest information
 type QuestFetchAsk struct {
-	*packets.BasePacket
 }
 
 // NewQuestFetchAsk creates a new QuestFetchAsk packet
 func NewQuestFetchAsk() *QuestFetchAsk {
-	return &QuestFetchAsk{
-		BasePacket: packets.NewPacket(interfaces.QuestFetchAsk, byte(interfaces.QuestFetchAsk)),
-	}
+	return &QuestFetchAsk{}
 }
 
 // Type returns the packet type
@@ -22,12 +18,17 @@ func (q *QuestFetchAsk) Type() interfaces.PacketType {
 	return interfaces.QuestFetchAsk
 }
 
-// Read reads the packet data from a PacketReader
-func (q *QuestFetchAsk) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (q *QuestFetchAsk) ID() int32 {
+	return int32(interfaces.QuestFetchAsk)
+}
+
+// Read reads the packet data from a Reader
+func (q *QuestFetchAsk) Read(r interfaces.Reader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (q *QuestFetchAsk) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (q *QuestFetchAsk) Write(w interfaces.Writer) error {
 	return nil
 }
@@ -7,13 +7,11 @@
 package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // QuestRedeem represents a packet for redeeming a quest
 type QuestRedeem struct {
-	*packets.BasePacket
 	QuestID string
 	Slots   []int32
 	ItemIDs []int32
@@ -21,9 +19,7 @@ type QuestRedeem struct {
 
 // NewQuestRedeem creates a new QuestRedeem packet
 func NewQuestRedeem() *QuestRedeem {
-	return &QuestRedeem{
-		BasePacket: packets.NewPacket(interfaces.QuestRedeem, byte(interfaces.QuestRedeem)),
-	}
+	return &QuestRedeem{}
 }
 
 // Type returns the packet type
@@ -31,8 +27,13 @@ func (q *QuestRedeem) Type() interfaces.PacketType {
 	return interfaces.QuestRedeem
 }
 
-// Read reads the packet data from a PacketReader
-func (q *QuestRedeem) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (q *QuestRedeem) ID() int32 {
+	return int32(interfaces.QuestRedeem)
+}
+
+// Read reads the packet data from a Reader
+func (q *QuestRedeem) Read(r interfaces.Reader) error {
 	var err error
 	q.QuestID, err = r.ReadString()
 	if err != nil {
@@ -61,8 +62,8 @@ func (q *QuestRedeem) Read(r *packets.PacketReader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (q *QuestRedeem) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (q *QuestRedeem) Write(w interfaces.Writer) error {
 	if err := w.WriteString(q.QuestID); err != nil {
 		return err
 	}
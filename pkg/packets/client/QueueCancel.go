@@ -1,20 +1,16 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // QueueCancel represents a packet for canceling a queue
 type QueueCancel struct {
-	*packets.BasePacket
 }
 
 // NewQueueCancel creates a new QueueCancel packet
 func NewQueueCancel() *QueueCancel {
-	return &QueueCancel{
-		BasePacket: packets.NewPacket(interfaces.QueueCancel, byte(interfaces.QueueCancel)),
-	}
+	return &QueueCancel{}
 }
 
 // Type returns the packet type
@@ -22,12 +18,17 @@ func (q *QueueCancel) Type() interfaces.PacketType {
 	return interfaces.QueueCancel
 }
 
-// Read reads the packet data from a PacketReader
-func (q *QueueCancel) Read(r *packets.PacketReader) error {
+// ID returns the packet ID
+func (q *QueueCancel) ID() int32 {
+	return int32(interfaces.QueueCancel)
+}
+
+// Read reads the packet data from a Reader
+func (q *QueueCancel) Read(r interfaces.Reader) error {
 	return nil
 }
 
-// Write writes the packet data to a PacketWriter
-func (q *QueueCancel) Write(w *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (q *QueueCancel) Write(w interfaces.Writer) error {
 	return nil
 }
@@ -1,22 +1,18 @@
 ﻿package client
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // Retitle represents a packet for changing a title
 type Retitle struct {
-	*packets.BasePacket
 	Prefix int32
 	Suffix int32
 }
 
 // NewRetitle creates a new Retitle packet
 func NewRetitle() *Retitle {
-	return &Retitle{
-		BasePacket: packets.NewPacket(interfaces.Retitle, byte(interfaces.Retitle)),
-	}
+	return &Retitle{}
 }
 
 // Type returns the packet type
@@ -24,8 +20,13 @@ func (r *Retitle) Type() interfaces.PacketType {
 	return interfaces.Retitle
 }
 
-// Read reads the packet data from a PacketReader
-func (r *Retitle) Read(reader *packets.PacketReader) error {
+// ID returns the packet ID
+func (r *Retitle) ID() int32 {
+	return int32(interfaces.Retitle)
+}
+
+// Read reads the packet data from a Reader
+func (r *Retitle) Read(reader interfaces.Reader) error {
 	var err error
 	r.Prefix, err = reader.ReadInt32()
 	if err != nil {
@@ -35,8 +36,8 @@ func (r *Retitle) Read(reader *packets.PacketReader) error {
 	return err
 }
 
-// Write writes the packet data to a PacketWriter
-func (r *Retitle) Write(writer *packets.PacketWriter) error {
+// Write writes the packet data to a Writer
+func (r *Retitle) Write(writer interfaces.Writer) error {
 	if err := writer.WriteInt32(r.Prefix); err != nil {
 		return err
 	}
@@ -15,6 +15,11 @@ func (p *SetAbility) Type() interfaces.PacketType {
 	return interfaces.SetAbility
 }
 
+// ID returns the packet ID
+func (p *SetAbility) ID() int32 {
+	return int32(interfaces.SetAbility)
+}
+
 // Read reads the packet data from the given reader
 func (p *SetAbility) Read(r interfaces.Reader) error {
 	var err error
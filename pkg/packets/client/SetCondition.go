@@ -15,6 +15,11 @@ func (p *SetCondition) Type() interfaces.PacketType {
 	return interfaces.SetCondition
 }
 
+// ID returns the packet ID
+func (p *SetCondition) ID() int32 {
+	return int32(interfaces.SetCondition)
+}
+
 // Read reads the packet data from the given reader
 func (p *SetCondition) Read(r interfaces.Reader) error {
 	var err error
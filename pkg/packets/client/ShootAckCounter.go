@@ -15,6 +15,11 @@ func (p *ShootAckCounter) Type() interfaces.PacketType {
 	return interfaces.ShootAckCounter
 }
 
+// ID returns the packet ID
+func (p *ShootAckCounter) ID() int32 {
+	return int32(interfaces.ShootAckCounter)
+}
+
 // Read reads the packet data from the given reader
 func (p *ShootAckCounter) Read(r interfaces.Reader) error {
 	var err error
@@ -15,6 +15,11 @@ func (p *SkinRecycle) Type() interfaces.PacketType {
 	return interfaces.SkinRecycle
 }
 
+// ID returns the packet ID
+func (p *SkinRecycle) ID() int32 {
+	return int32(interfaces.SkinRecycle)
+}
+
 // Read reads the packet data from the given reader
 func (p *SkinRecycle) Read(r interfaces.Reader) error {
 	p.Item = dataobjects.NewSlotObject()
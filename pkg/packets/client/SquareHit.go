@@ -16,6 +16,11 @@ func (p *SquareHit) Type() interfaces.PacketType {
 	return interfaces.SquareHit
 }
 
+// ID returns the packet ID
+func (p *SquareHit) ID() int32 {
+	return int32(interfaces.SquareHit)
+}
+
 // Read reads the packet data from the given reader
 func (p *SquareHit) Read(r interfaces.Reader) error {
 	var err error
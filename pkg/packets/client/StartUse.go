@@ -17,6 +17,11 @@ func (p *StartUse) Type() interfaces.PacketType {
 	return interfaces.StartUse
 }
 
+// ID returns the packet ID
+func (p *StartUse) ID() int32 {
+	return int32(interfaces.StartUse)
+}
+
 // Read reads the packet data from the given reader
 func (p *StartUse) Read(r interfaces.Reader) error {
 	var err error
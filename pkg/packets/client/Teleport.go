@@ -15,6 +15,11 @@ func (p *Teleport) Type() interfaces.PacketType {
 	return interfaces.Teleport
 }
 
+// ID returns the packet ID
+func (p *Teleport) ID() int32 {
+	return int32(interfaces.Teleport)
+}
+
 // Read reads the packet data from the given reader
 func (p *Teleport) Read(r interfaces.Reader) error {
 	var err error
@@ -12,6 +12,11 @@ func (p *UnseasonRequest) Type() interfaces.PacketType {
 	return interfaces.UnseasonRequest
 }
 
+// ID returns the packet ID
+func (p *UnseasonRequest) ID() int32 {
+	return int32(interfaces.UnseasonRequest)
+}
+
 // Read reads the packet data from the given reader
 func (p *UnseasonRequest) Read(r interfaces.Reader) error {
 	return nil
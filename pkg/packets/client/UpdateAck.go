@@ -15,6 +15,11 @@ func (p *UpdateAck) Type() interfaces.PacketType {
 	return interfaces.UpdateAck
 }
 
+// ID returns the packet ID
+func (p *UpdateAck) ID() int32 {
+	return int32(interfaces.UpdateAck)
+}
+
 // Read reads the packet data from the given reader
 func (p *UpdateAck) Read(r interfaces.Reader) error {
 	return nil
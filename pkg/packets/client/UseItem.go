@@ -18,6 +18,11 @@ func (p *UseItem) Type() interfaces.PacketType {
 	return interfaces.UseItem
 }
 
+// ID returns the packet ID
+func (p *UseItem) ID() int32 {
+	return int32(interfaces.UseItem)
+}
+
 // Read reads the packet data from the given reader
 func (p *UseItem) Read(r interfaces.Reader) error {
 	var err error
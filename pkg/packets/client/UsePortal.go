@@ -14,6 +14,11 @@ func (p *UsePortal) Type() interfaces.PacketType {
 	return interfaces.UsePortal
 }
 
+// ID returns the packet ID
+func (p *UsePortal) ID() int32 {
+	return int32(interfaces.UsePortal)
+}
+
 // Read reads the packet data from the given reader
 func (p *UsePortal) Read(r interfaces.Reader) error {
 	var err error
@@ -0,0 +1,68 @@
+package client_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gorelay/pkg/packets"
+	"gorelay/pkg/packets/client"
+	"gorelay/pkg/packets/dataobjects"
+	"gorelay/pkg/packets/interfaces"
+)
+
+func TestClientPacketsRoundTrip(t *testing.T) {
+	tests := []struct {
+		packet packets.Packet
+		empty  packets.Packet // read target, constructors allocate nested objects
+		want   interfaces.PacketType
+	}{
+		{packet: &client.Hello{GameID: -2, BuildVersion: "5.0", AccessToken: "token", Key: []byte{1, 2}, GameNet: "rotmg"}, empty: client.NewHello(), want: interfaces.Hello},
+		{packet: &client.Load{CharacterID: 12, FirstSession: true}, empty: client.NewLoad(), want: interfaces.Load},
+		{packet: &client.Create{ClassType: 768, SkinType: 2, IsSeasonal: true}, empty: client.NewCreate(), want: interfaces.Create},
+		{packet: &client.PlayerText{Text: "/tell friend hi"}, empty: client.NewPlayerText(), want: interfaces.PlayerText},
+		{packet: &client.GotoAck{Time: 1200, Unknown: true}, empty: client.NewGotoAck(), want: interfaces.GotoAck},
+		{packet: &client.Move{TickID: 7, Time: 900, Records: []*dataobjects.LocationRecord{
+			{Time: 850, Position: &dataobjects.Location{X: 1.5, Y: 2.5}},
+			{Time: 875, Position: &dataobjects.Location{X: 1.75, Y: 2.5}},
+		}}, empty: client.NewMove(), want: interfaces.Move},
+		{packet: &client.InventorySwap{
+			Time:        300,
+			Position:    &client.Location{X: 10.5, Y: 20.5},
+			SlotObject1: &dataobjects.SlotObject{ObjectID: 1, SlotID: 4, ObjectType: 2591},
+			SlotObject2: &dataobjects.SlotObject{ObjectID: 1, SlotID: 5, ObjectType: -1},
+		}, empty: client.NewInventorySwap(), want: interfaces.InventorySwap},
+		{packet: &client.UseItem{
+			Time:       400,
+			SlotObject: &dataobjects.SlotObject{ObjectID: 1, SlotID: 2, ObjectType: 2594},
+			ItemUsePos: &dataobjects.Location{X: 3, Y: 4},
+			UseType:    1,
+		}, empty: &client.UseItem{}, want: interfaces.UseItem},
+		{packet: &client.ChangeTrade{Offers: []bool{true, false, true}}, empty: client.NewChangeTrade(), want: interfaces.ChangeTrade},
+		{packet: &client.AcceptTrade{MyOffers: []bool{true}, YourOffers: []bool{false, true}}, empty: client.NewAcceptTrade(), want: interfaces.AcceptTrade},
+		{packet: &client.Escape{}, empty: client.NewEscape(), want: interfaces.Escape},
+	}
+	for _, tt := range tests {
+		name := reflect.TypeOf(tt.packet).Elem().Name()
+		t.Run(name, func(t *testing.T) {
+			if got := tt.packet.Type(); got != tt.want {
+				t.Fatalf("Type() = %d, want %d", got, tt.want)
+			}
+
+			w := packets.NewPacketWriter()
+			if err := tt.packet.Write(w); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			got := tt.empty
+			r := packets.NewPacketReader(w.Bytes())
+			if err := got.Read(r); err != nil {
+				t.Fatalf("Read failed: %v", err)
+			}
+			if r.RemainingBytes() != 0 {
+				t.Fatalf("Read left %d of %d bytes", r.RemainingBytes(), len(w.Bytes()))
+			}
+			if !reflect.DeepEqual(got, tt.packet) {
+				t.Fatalf("read %+v, want %+v", got, tt.packet)
+			}
+		})
+	}
+}
@@ -145,8 +145,8 @@ func (p *BasePacket) ID() int32 {
 	return int32(p.PacketID)
 }
 
-// EncodePacket encodes a packet into a byte array ready for transmission
-// It adds the packet length and ID, then writes the packet data
+// EncodePacket encodes a packet into a byte array ready for transmission.
+// It writes the total length (including the 5 byte header) and ID, then the packet data
 func EncodePacket(packet Packet) ([]byte, error) {
 	// Create a new packet writer
 	writer := NewPacketWriter()
@@ -170,8 +170,8 @@ func EncodePacket(packet Packet) ([]byte, error) {
 	// Get the packet data
 	packetData := tempWriter.Bytes()
 
-	// Write the packet length (including ID byte) to the main writer
-	if err := writer.WriteInt32(int32(len(packetData))); err != nil {
+	// Write the packet length (including the length field itself) to the main writer
+	if err := writer.WriteInt32(int32(len(packetData) + 4)); err != nil {
 		return nil, fmt.Errorf("failed to write packet length: %v", err)
 	}
 
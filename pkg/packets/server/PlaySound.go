@@ -1,22 +1,18 @@
 package server
 
 import (
-	"gorelay/pkg/packets"
 	"gorelay/pkg/packets/interfaces"
 )
 
 // PlaySound represents a server packet for playing sounds
 type PlaySound struct {
-	*packets.BasePacket
 	OwnerId int32
 	SoundId byte
 }
 
 // NewPlaySound creates a new PlaySound packet
 func NewPlaySound() *PlaySound {
-	return &PlaySound{
-		BasePacket: packets.NewPacket(interfaces.PlaySound, byte(interfaces.PlaySound)),
-	}
+	return &PlaySound{}
 }
 
 // Type returns the packet type
@@ -24,6 +20,11 @@ func (p *PlaySound) Type() interfaces.PacketType {
 	return interfaces.PlaySound
 }
 
+// ID returns the packet ID
+func (p *PlaySound) ID() int32 {
+	return int32(interfaces.PlaySound)
+}
+
 // Read reads the packet data from the reader
 func (p *PlaySound) Read(r interfaces.Reader) error {
 	var err error
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"gorelay/pkg/client"
+	"gorelay/pkg/events"
 	"gorelay/pkg/interfaces"
 	"gorelay/pkg/models"
 	"gorelay/pkg/packets"
@@ -24,14 +25,21 @@ type Manager struct {
 	plugins     []*PluginInstance
 	client      *client.Client
 	packetHooks map[int32][]interfaces.PacketHook
+
+	// Event subscriptions made by each plugin, keyed by plugin name
+	subscriptions map[string][]*events.Subscription
+	commands      map[string][]string
+	registering   string
 }
 
 // NewManager creates a new plugin manager
 func NewManager(client *client.Client) *Manager {
 	return &Manager{
-		plugins:     make([]*PluginInstance, 0),
-		client:      client,
-		packetHooks: make(map[int32][]interfaces.PacketHook),
+		plugins:       make([]*PluginInstance, 0),
+		client:        client,
+		packetHooks:   make(map[int32][]interfaces.PacketHook),
+		subscriptions: make(map[string][]*events.Subscription),
+		commands:      make(map[string][]string),
 	}
 }
 
@@ -59,8 +67,13 @@ func (m *Manager) LoadPlugin(path string) error {
 		return fmt.Errorf("failed to initialize plugin: %v", err)
 	}
 
-	// Register the plugin with the manager
-	if err := pluginInstance.Register(m); err != nil {
+	// Register the plugin with the manager. Subscriptions made during
+	// registration belong to this plugin.
+	m.registering = pluginInstance.Name()
+	err := pluginInstance.Register(m)
+	m.registering = ""
+	if err != nil {
+		m.unsubscribeAll(pluginInstance.Name())
 		return fmt.Errorf("failed to register plugin: %v", err)
 	}
 
@@ -79,6 +92,7 @@ func (m *Manager) UnloadPlugin(name string) error {
 			if err := plugin.Instance.OnDisable(); err != nil {
 				return err
 			}
+			m.unsubscribeAll(name)
 
 			// Remove the plugin from the slice
 			m.plugins = append(m.plugins[:i], m.plugins[i+1:]...)
@@ -121,6 +135,35 @@ func (m *Manager) HandlePacket(packet packets.Packet) error {
 	return nil
 }
 
+// Subscribe registers an event handler for the plugin currently being registered
+func (m *Manager) Subscribe(eventType events.EventType, handler events.Handler, opts ...events.SubscribeOption) *events.Subscription {
+	sub := m.client.Events().Subscribe(eventType, handler, opts...)
+	m.subscriptions[m.registering] = append(m.subscriptions[m.registering], sub)
+	return sub
+}
+
+// RegisterCommand registers a chat command for the plugin currently being registered
+func (m *Manager) RegisterCommand(name string, handler client.CommandHandler, opts ...client.CommandOption) error {
+	if err := m.client.Chat().RegisterCommand(name, handler, opts...); err != nil {
+		return err
+	}
+	m.commands[m.registering] = append(m.commands[m.registering], name)
+	return nil
+}
+
+// unsubscribeAll removes every event subscription and chat command a plugin made
+func (m *Manager) unsubscribeAll(name string) {
+	for _, sub := range m.subscriptions[name] {
+		sub.Unsubscribe()
+	}
+	delete(m.subscriptions, name)
+
+	for _, command := range m.commands[name] {
+		m.client.Chat().UnregisterCommand(command)
+	}
+	delete(m.commands, name)
+}
+
 // RegisterPlugin registers a plugin with the manager
 func (m *Manager) RegisterPlugin(plugin interfaces.Plugin) {
 	m.plugins = append(m.plugins, &PluginInstance{
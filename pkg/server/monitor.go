@@ -6,10 +6,12 @@ import (
 	"html/template"
 	"net/http"
 	"runtime"
+	"strconv"
 	"sync"
 	"time"
 
 	"gorelay/pkg/models"
+	"gorelay/pkg/xmldata"
 )
 
 // MonitorServer provides an HTTP server for debugging and monitoring
@@ -48,6 +50,7 @@ func NewMonitorServer(port int) *MonitorServer {
 	ms.handlers["/"] = ms.handleDashboard
 	ms.handlers["/status"] = ms.handleStatus
 	ms.handlers["/api/status"] = ms.handleAPIStatus
+	ms.handlers["/api/vaults"] = ms.handleAPIVaults
 	ms.handlers["/static/"] = http.StripPrefix("/static/", http.FileServer(http.Dir("pkg/server/static"))).ServeHTTP
 
 	return ms
@@ -171,6 +174,41 @@ func (ms *MonitorServer) handleAPIStatus(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(clientsCopy)
 }
 
+// handleAPIVaults returns the mirrored vaults of every account in JSON format.
+// With an item query parameter, given as a name or type ID, it returns where
+// that item is stored instead.
+func (ms *MonitorServer) handleAPIVaults(w http.ResponseWriter, r *http.Request) {
+	storage := models.GetVaultStorage()
+	if storage == nil {
+		http.Error(w, "vault storage is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	item := r.URL.Query().Get("item")
+	if item == "" {
+		json.NewEncoder(w).Encode(storage.Records())
+		return
+	}
+
+	itemType, err := strconv.Atoi(item)
+	if err != nil {
+		obj := xmldata.GetObjectByName(item)
+		if obj == nil {
+			http.Error(w, fmt.Sprintf("unknown item %q", item), http.StatusNotFound)
+			return
+		}
+		// Type attributes are hex (0xNNNN) or decimal
+		parsed, err := strconv.ParseInt(obj.Type, 0, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("item %q has invalid type %q", item, obj.Type), http.StatusInternalServerError)
+			return
+		}
+		itemType = int(parsed)
+	}
+	json.NewEncoder(w).Encode(storage.FindItem(int32(itemType)))
+}
+
 // collectMetrics periodically updates system metrics
 func (ms *MonitorServer) collectMetrics() {
 	ticker := time.NewTicker(time.Second)
@@ -3,14 +3,25 @@ package pathfinding
 import (
 	"container/heap"
 	"gorelay/pkg/client"
+	"gorelay/pkg/xmldata"
 	"math"
 )
 
+// Cost penalties added for hazardous ground, so routes only cross it when
+// there is no reasonable way around
+const (
+	damagePenalty      = 8.0
+	damagePenaltyPerHP = 0.1
+	sinkPenalty        = 4.0
+	pushPenalty        = 2.0
+)
+
 // Node represents a point in the pathfinding grid
 type Node struct {
 	X, Y     int
 	F, G, H  float64
 	Walkable bool
+	Cost     float64 // multiplier for moving onto the node, 1 for plain ground
 	Parent   *Node
 	index    int // Used by heap.Interface
 }
@@ -27,6 +38,9 @@ type Pathfinder struct {
 	nodes         [][]*Node
 }
 
+// Pathfinder can route client movement, e.g. with Client.PathToQuest
+var _ client.PathFinder = (*Pathfinder)(nil)
+
 // NewPathfinder creates a new pathfinder instance
 func NewPathfinder(width, height int) *Pathfinder {
 	p := &Pathfinder{
@@ -43,6 +57,7 @@ func NewPathfinder(width, height int) *Pathfinder {
 				X:        x,
 				Y:        y,
 				Walkable: true,
+				Cost:     1,
 			}
 		}
 	}
@@ -98,7 +113,7 @@ func (p *Pathfinder) FindPath(startX, startY, endX, endY int) []*Node {
 				continue
 			}
 
-			gScore := current.G + p.distance(current, neighbor)
+			gScore := current.G + p.distance(current, neighbor)*neighbor.Cost
 			inOpenSet := false
 			for _, node := range *openSet {
 				if node == neighbor {
@@ -133,6 +148,51 @@ func (p *Pathfinder) UpdateWalkableNodes(updates []NodeUpdate) {
 	}
 }
 
+// TileCost returns the cost multiplier of walking over a ground type. Slow
+// ground costs more, and damaging, sinking or pushing ground adds a penalty.
+func TileCost(ground *xmldata.GroundType) float64 {
+	cost := 1.0
+	if ground.Speed > 0 && ground.Speed < 1 {
+		cost /= float64(ground.Speed)
+	}
+	if ground.Damaging() {
+		cost += damagePenalty + float64(ground.MaxDamage)*damagePenaltyPerHP
+	}
+	if ground.Sink != nil {
+		cost += sinkPenalty
+	}
+	if ground.Push != nil {
+		cost += pushPenalty
+	}
+	return cost
+}
+
+// SetTile applies the walkability and cost of a tile type to a node. Unknown
+// tile types are treated as plain ground.
+func (p *Pathfinder) SetTile(x, y, tileType int) {
+	if !p.isValidCoord(x, y) {
+		return
+	}
+	node := p.nodes[y][x]
+	node.Walkable = true
+	node.Cost = 1
+	if ground := xmldata.GetGroundByTypeID(tileType); ground != nil {
+		node.Walkable = ground.NoWalk == nil
+		node.Cost = TileCost(ground)
+	}
+}
+
+// LoadMap applies every tile the client has seen on a map
+func (p *Pathfinder) LoadMap(m *client.Map) {
+	for y, row := range m.Tiles {
+		for x, tileType := range row {
+			if tileType != client.UnknownTile {
+				p.SetTile(x, y, int(tileType))
+			}
+		}
+	}
+}
+
 // Helper methods
 
 func (p *Pathfinder) isValidCoord(x, y int) bool {
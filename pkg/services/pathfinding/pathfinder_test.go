@@ -0,0 +1,133 @@
+package pathfinding
+
+import (
+	"testing"
+
+	"gorelay/pkg/client"
+	"gorelay/pkg/xmldata"
+)
+
+// checkPath fails unless path runs from start to end in single steps over walkable nodes
+func checkPath(t *testing.T, p *Pathfinder, path []*Node, startX, startY, endX, endY int) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatal("no path found")
+	}
+	if first := path[0]; first.X != startX || first.Y != startY {
+		t.Fatalf("path starts at %d,%d, want %d,%d", first.X, first.Y, startX, startY)
+	}
+	if last := path[len(path)-1]; last.X != endX || last.Y != endY {
+		t.Fatalf("path ends at %d,%d, want %d,%d", last.X, last.Y, endX, endY)
+	}
+	for i, node := range path {
+		if !node.Walkable {
+			t.Fatalf("path crosses blocked node %d,%d", node.X, node.Y)
+		}
+		if i == 0 {
+			continue
+		}
+		dx, dy := node.X-path[i-1].X, node.Y-path[i-1].Y
+		if dx < -1 || dx > 1 || dy < -1 || dy > 1 {
+			t.Fatalf("path jumps from %d,%d to %d,%d", path[i-1].X, path[i-1].Y, node.X, node.Y)
+		}
+	}
+}
+
+func TestFindPathOpenGrid(t *testing.T) {
+	p := NewPathfinder(10, 10)
+	path := p.FindPath(0, 0, 9, 0)
+	checkPath(t, p, path, 0, 0, 9, 0)
+	if len(path) != 10 {
+		t.Fatalf("path has %d nodes, want the straight line of 10", len(path))
+	}
+}
+
+func TestFindPathThroughGap(t *testing.T) {
+	// A wall across x=5 with a single gap at y=8
+	p := NewPathfinder(10, 10)
+	var updates []NodeUpdate
+	for y := 0; y < 10; y++ {
+		if y != 8 {
+			updates = append(updates, NodeUpdate{X: 5, Y: y, Walkable: false})
+		}
+	}
+	p.UpdateWalkableNodes(updates)
+
+	path := p.FindPath(0, 0, 9, 0)
+	checkPath(t, p, path, 0, 0, 9, 0)
+	crossed := false
+	for _, node := range path {
+		if node.X == 5 && node.Y == 8 {
+			crossed = true
+		}
+	}
+	if !crossed {
+		t.Fatal("path did not go through the gap")
+	}
+}
+
+func TestFindPathBlocked(t *testing.T) {
+	p := NewPathfinder(10, 10)
+	var updates []NodeUpdate
+	for y := 0; y < 10; y++ {
+		updates = append(updates, NodeUpdate{X: 5, Y: y, Walkable: false})
+	}
+	p.UpdateWalkableNodes(updates)
+
+	if path := p.FindPath(0, 0, 9, 0); path != nil {
+		t.Fatalf("found a path through a closed wall: %d nodes", len(path))
+	}
+	if path := p.FindPath(0, 0, 10, 0); path != nil {
+		t.Fatal("found a path to a node outside the grid")
+	}
+}
+
+func TestFindPathAvoidsHazards(t *testing.T) {
+	// A lava column across x=5 with plain ground only at y=9
+	lava := &xmldata.GroundType{ID: "Lava", MinDamage: 50, MaxDamage: 100}
+	p := NewPathfinder(10, 10)
+	for y := 0; y < 9; y++ {
+		p.nodes[y][5].Cost = TileCost(lava)
+	}
+
+	path := p.FindPath(0, 5, 9, 5)
+	checkPath(t, p, path, 0, 5, 9, 5)
+	for _, node := range path {
+		if node.Cost > 1 {
+			t.Fatalf("path crosses lava at %d,%d instead of going around", node.X, node.Y)
+		}
+	}
+}
+
+func TestTileCost(t *testing.T) {
+	tests := []struct {
+		name   string
+		ground xmldata.GroundType
+		want   float64
+	}{
+		{name: "plain", ground: xmldata.GroundType{}, want: 1},
+		{name: "slow", ground: xmldata.GroundType{Speed: 0.5}, want: 2},
+		{name: "damaging", ground: xmldata.GroundType{MaxDamage: 100}, want: 1 + damagePenalty + 100*damagePenaltyPerHP},
+		{name: "sinking", ground: xmldata.GroundType{Sink: &struct{}{}}, want: 1 + sinkPenalty},
+		{name: "pushing", ground: xmldata.GroundType{Push: &struct{}{}}, want: 1 + pushPenalty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TileCost(&tt.ground); got != tt.want {
+				t.Fatalf("TileCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindPathWorldCentersTiles(t *testing.T) {
+	p := NewPathfinder(10, 10)
+	path := p.FindPathWorld(&client.WorldPosData{X: 1.2, Y: 1.7}, &client.WorldPosData{X: 3.9, Y: 1.1})
+	if len(path) == 0 {
+		t.Fatal("no path found")
+	}
+	last := path[len(path)-1]
+	if last.X != 3.5 || last.Y != 1.5 {
+		t.Fatalf("path ends at %v,%v, want the center of tile 3,1", last.X, last.Y)
+	}
+}
@@ -0,0 +1,93 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpConnectDialer tunnels connections through an HTTP proxy using CONNECT
+type httpConnectDialer struct {
+	settings Settings
+	forward  *net.Dialer
+	timeout  time.Duration
+}
+
+func (d *httpConnectDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *httpConnectDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := dialProxy(ctx, d.forward, &d.settings, d.timeout)
+	if err != nil {
+		return nil, err
+	}
+	return d.handshake(conn, address)
+}
+
+// handshake asks the proxy to open a tunnel to address and returns the
+// connection to use for it. conn is closed when the handshake fails.
+func (d *httpConnectDialer) handshake(conn net.Conn, address string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if d.settings.Username != "" {
+		credentials := d.settings.Username + ":" + d.settings.Password
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT to %s: %v", d.settings.String(), err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read CONNECT response from %s: %v", d.settings.String(), err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusProxyAuthRequired:
+		conn.Close()
+		return nil, fmt.Errorf("%w: %s rejected credentials: %s", ErrProxyUnavailable, d.settings.String(), resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s could not reach %s: %s", d.settings.String(), address, resp.Status)
+	}
+
+	// Clear the handshake deadline now that the tunnel is up
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Keep any bytes the target sent right after the response
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn serves data already read into a bufio.Reader before reading from the connection
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	if c.reader.Buffered() > 0 {
+		return c.reader.Read(b)
+	}
+	return c.Conn.Read(b)
+}
@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+// fakeHTTPProxy answers one CONNECT request on conn with status, followed by
+// extra bytes from the target
+func fakeHTTPProxy(conn net.Conn, wantAuth string, status int, extra string) error {
+	req, err := http.ReadRequest(bufio.NewReader(conn))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if req.Method != http.MethodConnect || req.Host != "example.com:2050" {
+		conn.Close()
+		return errors.New("unexpected request " + req.Method + " " + req.Host)
+	}
+	if got := req.Header.Get("Proxy-Authorization"); got != wantAuth {
+		conn.Close()
+		return errors.New("unexpected Proxy-Authorization " + got)
+	}
+
+	resp := fmt.Sprintf("HTTP/1.1 %d %s\r\n\r\n%s", status, http.StatusText(status), extra)
+	if _, err := io.WriteString(conn, resp); err != nil {
+		conn.Close()
+		return err
+	}
+	if status != http.StatusOK {
+		conn.Close()
+	}
+	return nil
+}
+
+func TestHTTPConnectHandshake(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	proxyErr := make(chan error, 1)
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	go func() { proxyErr <- fakeHTTPProxy(server, auth, http.StatusOK, "hello") }()
+
+	dialer := &httpConnectDialer{settings: Settings{Host: "127.0.0.1", Port: 8080, Username: "user", Password: "secret"}}
+	conn, err := dialer.handshake(client, "example.com:2050")
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	if err := <-proxyErr; err != nil {
+		t.Fatalf("proxy: %v", err)
+	}
+
+	// Bytes sent right after the response belong to the tunnel
+	got := make([]byte, len("hello"))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("failed to read tunneled data: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("tunnel returned %q, want %q", got, "hello")
+	}
+}
+
+func TestHTTPConnectErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		unavailable bool
+	}{
+		{name: "auth required", status: http.StatusProxyAuthRequired, unavailable: true},
+		{name: "target unreachable", status: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			proxyErr := make(chan error, 1)
+			go func() { proxyErr <- fakeHTTPProxy(server, "", tt.status, "") }()
+
+			dialer := &httpConnectDialer{settings: Settings{Host: "127.0.0.1", Port: 8080}}
+			_, err := dialer.handshake(client, "example.com:2050")
+			if err == nil {
+				t.Fatal("handshake succeeded, want an error")
+			}
+			if errors.Is(err, ErrProxyUnavailable) != tt.unavailable {
+				t.Fatalf("handshake error = %v, ErrProxyUnavailable want %v", err, tt.unavailable)
+			}
+			if err := <-proxyErr; err != nil {
+				t.Fatalf("proxy: %v", err)
+			}
+		})
+	}
+}
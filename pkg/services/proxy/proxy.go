@@ -0,0 +1,114 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Supported proxy types
+const (
+	TypeHTTP   = "http"
+	TypeSOCKS5 = "socks5"
+)
+
+// ErrProxyUnavailable is returned when the proxy itself cannot be reached, as
+// opposed to the proxy failing to reach the target
+var ErrProxyUnavailable = errors.New("proxy unavailable")
+
+// Settings describes a proxy server and its credentials
+type Settings struct {
+	Type     string
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// Address returns the proxy's host:port
+func (s *Settings) Address() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// String returns the proxy URL without credentials, safe for logging
+func (s *Settings) String() string {
+	proxyType := s.Type
+	if proxyType == "" {
+		proxyType = TypeHTTP
+	}
+	return fmt.Sprintf("%s://%s", proxyType, s.Address())
+}
+
+// Dialer opens TCP connections, optionally through a proxy
+type Dialer interface {
+	Dial(network, address string) (net.Conn, error)
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// NewDialer returns a dialer for the given proxy settings. A nil or empty
+// settings value returns a direct dialer.
+func NewDialer(settings *Settings, timeout time.Duration) (Dialer, error) {
+	forward := &net.Dialer{Timeout: timeout, KeepAlive: 60 * time.Second}
+	if settings == nil || settings.Host == "" {
+		return &directDialer{forward: forward}, nil
+	}
+
+	switch settings.Type {
+	case "", TypeHTTP:
+		return &httpConnectDialer{settings: *settings, forward: forward, timeout: timeout}, nil
+	case TypeSOCKS5:
+		return &socks5Dialer{settings: *settings, forward: forward, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy type: %s", settings.Type)
+	}
+}
+
+// NewHTTPClient returns an HTTP client whose connections go through the dialer
+func NewHTTPClient(dialer Dialer, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// directDialer connects straight to the target
+type directDialer struct {
+	forward *net.Dialer
+}
+
+func (d *directDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *directDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return d.forward.DialContext(ctx, network, address)
+}
+
+// dialProxy opens the connection to the proxy and applies the handshake deadline
+func dialProxy(ctx context.Context, forward *net.Dialer, settings *Settings, timeout time.Duration) (net.Conn, error) {
+	conn, err := forward.DialContext(ctx, "tcp", settings.Address())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrProxyUnavailable, settings, err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok && timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if !deadline.IsZero() {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
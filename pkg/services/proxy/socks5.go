@@ -0,0 +1,189 @@
+package proxy
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// SOCKS5 protocol constants (RFC 1928 and RFC 1929)
+const (
+	socks5Version       = 0x05
+	socks5AuthNone      = 0x00
+	socks5AuthPassword  = 0x02
+	socks5AuthNoMethods = 0xFF
+	socks5CmdConnect    = 0x01
+	socks5AddrIPv4      = 0x01
+	socks5AddrDomain    = 0x03
+	socks5AddrIPv6      = 0x04
+	socks5PasswordVer   = 0x01
+)
+
+// socks5Replies maps SOCKS5 reply codes to readable errors
+var socks5Replies = map[byte]string{
+	0x01: "general server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// socks5Dialer tunnels connections through a SOCKS5 proxy
+type socks5Dialer struct {
+	settings Settings
+	forward  *net.Dialer
+	timeout  time.Duration
+}
+
+func (d *socks5Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *socks5Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := dialProxy(ctx, d.forward, &d.settings, d.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.handshake(conn, address); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Clear the handshake deadline now that the tunnel is up
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// handshake negotiates authentication and issues the CONNECT command
+func (d *socks5Dialer) handshake(conn net.Conn, address string) error {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid target address %s: %v", address, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 0 || port > 65535 {
+		return fmt.Errorf("invalid target port %s", portStr)
+	}
+
+	// Offer username/password auth only when credentials are configured
+	methods := []byte{socks5AuthNone}
+	if d.settings.Username != "" {
+		methods = []byte{socks5AuthNone, socks5AuthPassword}
+	}
+	greeting := append([]byte{socks5Version, byte(len(methods))}, methods...)
+	if _, err := conn.Write(greeting); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 greeting: %v", err)
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 greeting reply: %v", err)
+	}
+	if reply[0] != socks5Version {
+		return fmt.Errorf("%w: %s is not a SOCKS5 proxy", ErrProxyUnavailable, d.settings.String())
+	}
+
+	switch reply[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if err := d.authenticate(conn); err != nil {
+			return err
+		}
+	case socks5AuthNoMethods:
+		return fmt.Errorf("%w: %s accepted none of the offered auth methods", ErrProxyUnavailable, d.settings.String())
+	default:
+		return fmt.Errorf("%w: %s selected unsupported auth method %d", ErrProxyUnavailable, d.settings.String(), reply[1])
+	}
+
+	// CONNECT request
+	req := []byte{socks5Version, socks5CmdConnect, 0x00}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(req, socks5AddrIPv4)
+			req = append(req, ip4...)
+		} else {
+			req = append(req, socks5AddrIPv6)
+			req = append(req, ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return fmt.Errorf("target host name too long: %s", host)
+		}
+		req = append(req, socks5AddrDomain, byte(len(host)))
+		req = append(req, host...)
+	}
+	req = binary.BigEndian.AppendUint16(req, uint16(port))
+
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 connect request: %v", err)
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 connect reply: %v", err)
+	}
+	if header[1] != 0x00 {
+		reason, ok := socks5Replies[header[1]]
+		if !ok {
+			reason = fmt.Sprintf("unknown error %d", header[1])
+		}
+		return fmt.Errorf("proxy %s could not reach %s: %s", d.settings.String(), address, reason)
+	}
+
+	// Skip the bound address, its length depends on the address type
+	var skip int
+	switch header[3] {
+	case socks5AddrIPv4:
+		skip = net.IPv4len
+	case socks5AddrIPv6:
+		skip = net.IPv6len
+	case socks5AddrDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return fmt.Errorf("failed to read SOCKS5 bound address: %v", err)
+		}
+		skip = int(length[0])
+	default:
+		return fmt.Errorf("SOCKS5 reply has unknown address type %d", header[3])
+	}
+	if _, err := io.ReadFull(conn, make([]byte, skip+2)); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 bound address: %v", err)
+	}
+	return nil
+}
+
+// authenticate performs username/password authentication
+func (d *socks5Dialer) authenticate(conn net.Conn) error {
+	user, pass := d.settings.Username, d.settings.Password
+	if len(user) > 255 || len(pass) > 255 {
+		return fmt.Errorf("SOCKS5 credentials too long")
+	}
+
+	req := []byte{socks5PasswordVer, byte(len(user))}
+	req = append(req, user...)
+	req = append(req, byte(len(pass)))
+	req = append(req, pass...)
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 credentials: %v", err)
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 auth reply: %v", err)
+	}
+	if reply[1] != 0x00 {
+		return fmt.Errorf("%w: %s rejected credentials", ErrProxyUnavailable, d.settings.String())
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSOCKS5 plays the proxy side of a handshake on conn. It checks every
+// message the dialer sends and answers the CONNECT with reply.
+func fakeSOCKS5(conn net.Conn, user, pass string, reply byte) error {
+	defer conn.Close()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return err
+	}
+	if header[0] != socks5Version {
+		return errors.New("greeting has the wrong version")
+	}
+
+	if user == "" {
+		if _, err := conn.Write([]byte{socks5Version, socks5AuthNone}); err != nil {
+			return err
+		}
+	} else {
+		if !bytes.Contains(methods, []byte{socks5AuthPassword}) {
+			return errors.New("password auth was not offered")
+		}
+		if _, err := conn.Write([]byte{socks5Version, socks5AuthPassword}); err != nil {
+			return err
+		}
+		want := []byte{socks5PasswordVer, byte(len(user))}
+		want = append(want, user...)
+		want = append(want, byte(len(pass)))
+		want = append(want, pass...)
+		got := make([]byte, len(want))
+		if _, err := io.ReadFull(conn, got); err != nil {
+			return err
+		}
+		if !bytes.Equal(got, want) {
+			return errors.New("credentials do not match")
+		}
+		if _, err := conn.Write([]byte{socks5PasswordVer, 0x00}); err != nil {
+			return err
+		}
+	}
+
+	// CONNECT to example.com:2050 as a domain name
+	want := []byte{socks5Version, socks5CmdConnect, 0x00, socks5AddrDomain, byte(len("example.com"))}
+	want = append(want, "example.com"...)
+	want = append(want, 0x08, 0x02)
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		return err
+	}
+	if !bytes.Equal(got, want) {
+		return errors.New("connect request does not match")
+	}
+
+	if _, err := conn.Write([]byte{socks5Version, reply, 0x00, socks5AddrIPv4}); err != nil {
+		return err
+	}
+	if reply != 0x00 {
+		// The dialer stops reading after a failure
+		return nil
+	}
+	_, err := conn.Write([]byte{127, 0, 0, 1, 0x08, 0x02})
+	return err
+}
+
+func TestSOCKS5Handshake(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		pass    string
+		reply   byte
+		wantErr string
+	}{
+		{name: "no auth"},
+		{name: "password auth", user: "user", pass: "secret"},
+		{name: "connection refused", reply: 0x05, wantErr: "connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+
+			proxyErr := make(chan error, 1)
+			go func() { proxyErr <- fakeSOCKS5(server, tt.user, tt.pass, tt.reply) }()
+
+			dialer := &socks5Dialer{settings: Settings{
+				Type: TypeSOCKS5, Host: "127.0.0.1", Port: 1080, Username: tt.user, Password: tt.pass,
+			}}
+			err := dialer.handshake(client, "example.com:2050")
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("handshake failed: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("handshake error = %v, want %q", err, tt.wantErr)
+			}
+			if err := <-proxyErr; err != nil {
+				t.Fatalf("proxy: %v", err)
+			}
+		})
+	}
+}
+
+func TestSOCKS5RejectsNonSOCKSProxy(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		defer server.Close()
+		io.ReadFull(server, make([]byte, 3))
+		server.Write([]byte("HT"))
+	}()
+
+	dialer := &socks5Dialer{settings: Settings{Type: TypeSOCKS5, Host: "127.0.0.1", Port: 1080}}
+	err := dialer.handshake(client, "example.com:2050")
+	if !errors.Is(err, ErrProxyUnavailable) {
+		t.Fatalf("handshake error = %v, want ErrProxyUnavailable", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -38,6 +39,23 @@ type GameObject struct {
 	
 	// Labels string for quick checking
 	Labels string `xml:"Labels"`
+
+	// Item properties
+	Item       *struct{} `xml:"Item"`
+	SlotType   int       `xml:"SlotType"`
+	Consumable *struct{} `xml:"Consumable"`
+	Potion     *struct{} `xml:"Potion"`
+	Soulbound  *struct{} `xml:"Soulbound"`
+	BagType    int       `xml:"BagType"`
+
+	// Player class properties, comma separated slot type for each item slot
+	SlotTypes string `xml:"SlotTypes"`
+
+	// Portal properties
+	DungeonName     string    `xml:"DungeonName"`
+	IntergamePortal *struct{} `xml:"IntergamePortal"`
+	NexusPortal     *struct{} `xml:"NexusPortal"`
+	LockedPortal    *struct{} `xml:"LockedPortal"`
 	
 	// Projectiles
 	Projectiles []Projectile `xml:"Projectile"`
@@ -68,6 +86,12 @@ type GroundType struct {
 	Speed       float32 `xml:"Speed"`
 	SinkLevel   int `xml:"SinkLevel"`
 	Texture     *TextureData `xml:"Texture"`
+
+	// Hazards, damage is dealt to players standing on the ground
+	MinDamage int       `xml:"MinDamage"`
+	MaxDamage int       `xml:"MaxDamage"`
+	Sink      *struct{} `xml:"Sink"`
+	Push      *struct{} `xml:"Push"`
 }
 
 // TextureData holds texture file and index information
@@ -258,10 +282,48 @@ func GetObjectByTypeID(typeID int) *GameObject {
 	return Objects.ObjectsByTypeID[typeID]
 }
 
+// GetObjectName returns the display name of an object, falling back to its id
+func GetObjectName(typeID int) string {
+	obj := GetObjectByTypeID(typeID)
+	if obj == nil {
+		return ""
+	}
+	if obj.DisplayID != "" {
+		return obj.DisplayID
+	}
+	return obj.ID
+}
+
+// GetObjectByName returns a game object by its id or display name, ignoring case
+func GetObjectByName(name string) *GameObject {
+	if Objects == nil {
+		return nil
+	}
+	if obj, ok := Objects.ObjectsByID[name]; ok {
+		return obj
+	}
+	for _, obj := range Objects.ObjectsByTypeID {
+		if strings.EqualFold(obj.ID, name) || strings.EqualFold(obj.DisplayID, name) {
+			return obj
+		}
+	}
+	return nil
+}
+
 // GetGroundByTypeID returns a ground type by its numeric type ID
 func GetGroundByTypeID(typeID int) *GroundType {
 	if Tiles == nil {
 		return nil
 	}
 	return Tiles.GroundsByTypeID[typeID]
-}
\ No newline at end of file
+}
+
+// Damaging reports whether standing on the ground hurts players
+func (g *GroundType) Damaging() bool {
+	return g.MaxDamage > 0
+}
+
+// Hazardous reports whether the ground damages, sinks or pushes players
+func (g *GroundType) Hazardous() bool {
+	return g.Damaging() || g.Sink != nil || g.Push != nil
+}
@@ -3,9 +3,9 @@ package example
 import (
 	"fmt"
 	"gorelay/pkg/client"
+	"gorelay/pkg/events"
 	"gorelay/pkg/interfaces"
 	"gorelay/pkg/packets"
-	clientpackets "gorelay/pkg/packets/client"
 	packetinterfaces "gorelay/pkg/packets/interfaces"
 	"gorelay/pkg/packets/server"
 )
@@ -45,6 +45,14 @@ func (p *ExamplePlugin) Register(manager interfaces.PluginManager) error {
 	manager.RegisterPacketHook(int32(packetinterfaces.Update), p.handleUpdate)
 	manager.RegisterPacketHook(int32(packetinterfaces.AllyShoot), p.handleAllyShoot)
 
+	// Subscribe to game events
+	manager.Subscribe(events.EventDeath, p.handleDeath)
+
+	// Register chat commands
+	if err := manager.RegisterCommand("hello", p.handleHello); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -88,15 +96,8 @@ func (p *ExamplePlugin) handleText(packet packets.Packet) error {
 	if textPacket.Recipient == "Extreem" {
 		p.client.GetLogger().Info("HelloWorld", "Received direct message from %s: %s", textPacket.Name, textPacket.RawText)
 
-		reply := clientpackets.NewPlayerText()
-		reply.Text = fmt.Sprintf("/tell %s %s", textPacket.Name, p.responseText)
-
-		p.client.GetLogger().Debug("HelloWorld", "Attempting to send reply packet: %s", reply.Text)
-
-		if err := p.client.Send(reply); err != nil {
+		if err := p.client.Chat().Tell(textPacket.Name, p.responseText); err != nil {
 			p.client.GetLogger().Error("HelloWorld", "Failed to send reply: %v", err)
-		} else {
-			p.client.GetLogger().Info("HelloWorld", "Successfully sent PlayerText packet: %s", reply.Text)
 		}
 	}
 	return nil
@@ -125,6 +126,24 @@ func (p *ExamplePlugin) handleAllyShoot(packet packets.Packet) error {
 	return nil
 }
 
+// Event handlers
+func (p *ExamplePlugin) handleDeath(event *events.Event) {
+	death, ok := event.Data.(*events.DeathEventData)
+	if !ok {
+		return
+	}
+	p.client.GetLogger().Info("HelloWorld", "Goodbye %s, killed by %s", death.CharName, death.KilledBy)
+}
+
+// Chat command handlers
+func (p *ExamplePlugin) handleHello(ctx *client.CommandContext) error {
+	name := ctx.Sender
+	if len(ctx.Args) > 0 {
+		name = ctx.Args[0]
+	}
+	return ctx.Reply(fmt.Sprintf("Hello, %s!", name))
+}
+
 // OnUnknownPacket is called when an unknown packet is received
 func (p *ExamplePlugin) OnUnknownPacket(packetID int, data []byte) {
 	// Log unknown packets for debugging